@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"go-backend-todo/internal/config"
+	"go-backend-todo/internal/db"
+	"go-backend-todo/internal/models"
+	audit_repository "go-backend-todo/internal/repository/audit"
+	auth_repository "go-backend-todo/internal/repository/auth"
+	list_repository "go-backend-todo/internal/repository/list"
+	token_repository "go-backend-todo/internal/repository/token"
+	user_repository "go-backend-todo/internal/repository/user"
+	"go-backend-todo/internal/service"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const adminUsage = `usage: admin <command>
+
+commands:
+  create-admin -email <email> -username <name> [-password <password>]
+                                   create an administrator with a confirmed email address
+  reset-password [-password <password>] <email|username>
+                                   set a new password and sign the user out everywhere
+  verify-email <email|username>    confirm an email address without the verification link
+  revoke-sessions <email|username> sign the user out of every session and revoke their personal
+                                   access tokens
+  purge-tokens                     delete expired verification, recovery and personal access tokens
+                                   and expired list invitations
+
+Passwords that are not given as flags are read from standard input.`
+
+// adminServices are the services used by the admin commands, wired like the HTTP server wires them
+type adminServices struct {
+	userService  service.UserService
+	authService  service.AuthService
+	tokenService service.TokenService
+	listService  service.ListService
+}
+
+// newAdminServices creates the services used by the admin commands
+func newAdminServices(cfg *config.Config, pool *pgxpool.Pool) *adminServices {
+	userRepo := user_repository.NewUserRepository(pool)
+	authRepo := auth_repository.NewAuthRepository(pool)
+	tokenRepo := token_repository.NewTokenRepository(pool)
+	listRepo := list_repository.NewListRepository(pool)
+
+	auditService := service.NewAuditService(audit_repository.NewAuditRepository(pool))
+	emailService := service.NewEmailService(cfg)
+	txManager := db.NewTxManager(pool)
+
+	return &adminServices{
+		userService:  service.NewUserService(userRepo, tokenRepo, auditService, emailService, txManager, cfg),
+		authService:  service.NewAuthService(userRepo, authRepo, emailService, auditService, txManager, cfg),
+		tokenService: service.NewTokenService(tokenRepo, userRepo, auditService),
+		listService:  service.NewListService(listRepo, userRepo, emailService, cfg),
+	}
+}
+
+// runAdmin runs an administrative command against the database
+func runAdmin(cfg *config.Config, args []string) {
+	if len(args) == 0 {
+		log.Fatal(adminUsage)
+	}
+
+	pool, err := db.Open(context.Background(), cfg)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	defer pool.Close()
+
+	// Audit events recorded by the commands show where they came from
+	ctx := context.WithValue(context.Background(), models.ClientInfoKey, models.ClientInfo{UserAgent: "admin-cli"})
+	services := newAdminServices(cfg, pool)
+
+	switch args[0] {
+	case "create-admin":
+		err = services.createAdmin(ctx, args[1:])
+	case "reset-password":
+		err = services.resetPassword(ctx, args[1:])
+	case "verify-email":
+		err = services.verifyEmail(ctx, args[1:])
+	case "revoke-sessions":
+		err = services.revokeSessions(ctx, args[1:])
+	case "purge-tokens":
+		err = services.purgeTokens(ctx)
+	default:
+		log.Fatal(adminUsage)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (s *adminServices) createAdmin(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("create-admin", flag.ExitOnError)
+	email := flags.String("email", "", "email address of the administrator")
+	username := flags.String("username", "", "username of the administrator")
+	password := flags.String("password", "", "password of the administrator")
+	flags.Parse(args)
+
+	if *email == "" || *username == "" {
+		return fmt.Errorf("create-admin needs -email and -username")
+	}
+
+	req := &models.RegisterRequest{
+		Email:    strings.TrimSpace(*email),
+		Username: strings.TrimSpace(*username),
+		Password: readPassword(*password),
+	}
+	user, err := s.authService.CreateAdmin(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Created administrator %s (%s) with ID %s", user.Username, user.Email, user.UserID)
+	return nil
+}
+
+func (s *adminServices) resetPassword(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("reset-password", flag.ExitOnError)
+	password := flags.String("password", "", "new password")
+	flags.Parse(args)
+
+	user, err := s.lookupUser(ctx, flags.Args())
+	if err != nil {
+		return err
+	}
+
+	if err := s.userService.SetPassword(ctx, user.UserID, readPassword(*password)); err != nil {
+		return err
+	}
+
+	log.Printf("Reset the password of %s and revoked their sessions", user.Username)
+	return nil
+}
+
+func (s *adminServices) verifyEmail(ctx context.Context, args []string) error {
+	user, err := s.lookupUser(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	confirmed, err := s.userService.ConfirmEmail(ctx, user.UserID)
+	if err != nil {
+		return err
+	}
+
+	if !confirmed {
+		log.Printf("The email address %s of %s was already confirmed", user.Email, user.Username)
+		return nil
+	}
+	log.Printf("Confirmed the email address %s of %s", user.Email, user.Username)
+	return nil
+}
+
+func (s *adminServices) revokeSessions(ctx context.Context, args []string) error {
+	user, err := s.lookupUser(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userService.RevokeSessions(ctx, user.UserID); err != nil {
+		return err
+	}
+
+	log.Printf("Revoked every session and personal access token of %s", user.Username)
+	return nil
+}
+
+func (s *adminServices) purgeTokens(ctx context.Context) error {
+	cleared, err := s.authService.PurgeExpiredTokens(ctx)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.tokenService.PurgeExpiredTokens(ctx)
+	if err != nil {
+		return err
+	}
+
+	invitations, err := s.listService.PurgeExpiredInvitations(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Cleared expired verification or recovery tokens of %d users, deleted %d personal access tokens and %d list invitations", cleared, deleted, invitations)
+	return nil
+}
+
+// lookupUser finds the user named by the single argument of a command
+func (s *adminServices) lookupUser(ctx context.Context, args []string) (*models.UserProfile, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("expected the email address or username of one user")
+	}
+
+	user, err := s.userService.GetUserByLogin(ctx, strings.TrimSpace(args[0]))
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// readPassword returns the password given as a flag, or reads it from the first line of standard input
+func readPassword(password string) string {
+	if password != "" {
+		return password
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		log.Fatal("Failed to read password:", err)
+	}
+	return strings.TrimRight(line, "\r\n")
+}
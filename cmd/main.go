@@ -1,12 +1,22 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+	_ "time/tzdata" // Embed time zone database for user time zones
 
 	_ "go-backend-todo/docs" // Import for swagger docs
 	"go-backend-todo/internal/config"
 	"go-backend-todo/internal/db"
 	"go-backend-todo/internal/routes"
+	"go-backend-todo/internal/service"
+	"go-backend-todo/internal/worker"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -36,21 +46,126 @@ func main() {
 	// Load configuration
 	cfg := config.Load()
 
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			runMigrate(cfg, os.Args[2:])
+			return
+		case "seed":
+			runSeed(cfg)
+			return
+		case "admin":
+			runAdmin(cfg, os.Args[2:])
+			return
+		case "serve":
+		default:
+			log.Fatalf("Unknown command %q, expected serve, migrate, seed or admin", os.Args[1])
+		}
+	}
+
+	if err := serve(cfg); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// serve runs the HTTP server and background workers until SIGINT or SIGTERM, then shuts down gracefully.
+// Errors are returned instead of exiting, so the deferred pool closes run.
+func serve(cfg *config.Config) error {
 	// Create a new Fiber app with configuration
 	app := fiber.New(config.GetFiberConfig(cfg))
 
-	// Connect to database using connection pool (RECOMMENDED for production)
-	pool, err := db.ConnectPoolWithConfig(cfg)
+	// Cancelled on SIGINT or SIGTERM, which also aborts startup
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Connect to database using connection pool
+	pool, err := db.Open(ctx, cfg)
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		return fmt.Errorf("failed to connect to database: %w", err)
 	}
 	defer pool.Close()
 
+	// Readiness compares the schema with the migrations, which are also applied on startup if enabled
+	migrator, err := db.NewMigrator(pool)
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+	if cfg.Database.AutoMigrate {
+		if _, err := migrator.Up(ctx); err != nil {
+			return fmt.Errorf("failed to apply migrations: %w", err)
+		}
+	}
+
+	// Route reads that tolerate replication lag to the replicas, if any are configured
+	router, err := db.OpenRouter(ctx, cfg, pool)
+	if err != nil {
+		return fmt.Errorf("failed to connect to replicas: %w", err)
+	}
+	defer router.Close()
+
+	// Background workers run until shutdown stops the supervisor
+	supervisor := worker.NewSupervisor(context.Background())
+
 	// Setup routes with configuration and database pool
-	routes.SetupRoutes(app, cfg, pool)
+	healthService := routes.SetupRoutes(app, cfg, pool, router, migrator, supervisor)
+
+	if cfg.Server.Prefork && !fiber.IsChild() {
+		log.Println("Warning: prefork is enabled, shutdown will not drain or wait for in-flight requests")
+	}
 
 	// Start server
 	serverAddr := config.GetServerAddress(cfg)
-	log.Printf("Server starting on %s...", serverAddr)
-	log.Fatal(app.Listen(serverAddr))
+	listenErr := make(chan error, 1)
+	go func() {
+		log.Printf("Server starting on %s...", serverAddr)
+		listenErr <- app.Listen(serverAddr)
+	}()
+
+	select {
+	case <-ctx.Done():
+		stop()
+		log.Println("Shutdown signal received")
+	case err = <-listenErr:
+		err = fmt.Errorf("server stopped: %w", err)
+	}
+
+	shutdown(cfg, app, healthService, supervisor, err == nil)
+	return err
+}
+
+// shutdown drains the instance, then stops the workers while waiting for in-flight requests.
+// Everything shares one deadline, so shutdown ends within the termination grace period of orchestrators.
+// Draining is skipped when the listener already failed, since no traffic can reach the instance.
+func shutdown(cfg *config.Config, app *fiber.App, healthService service.HealthService, supervisor *worker.Supervisor, drain bool) {
+	deadline := time.Now().Add(time.Duration(cfg.Server.ShutdownTimeout) * time.Second)
+
+	if drain && cfg.Server.DrainPeriod > 0 {
+		// Readiness fails while the load balancer notices and stops sending new requests
+		healthService.StartDraining()
+		drainPeriod := min(time.Duration(cfg.Server.DrainPeriod)*time.Second, time.Until(deadline))
+		log.Printf("Draining for %s before shutting down", drainPeriod)
+		time.Sleep(drainPeriod)
+	}
+
+	remaining := max(time.Until(deadline), 0)
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	// Stopping the workers also closes the stream hub, which ends open event streams the server waits for
+	go func() {
+		defer wg.Done()
+		if err := supervisor.Stop(remaining); err != nil {
+			log.Println("Error stopping workers:", err)
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		if err := app.ShutdownWithTimeout(remaining); err != nil {
+			log.Println("Error shutting down server:", err)
+		}
+	}()
+
+	wg.Wait()
+	log.Println("Server stopped")
 }
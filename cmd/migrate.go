@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"go-backend-todo/internal/config"
+	"go-backend-todo/internal/db"
+)
+
+const migrateUsage = `usage: migrate <command>
+
+commands:
+  up               apply all pending migrations
+  down [n]         revert the last n applied migrations (default 1)
+  to <version>     apply or revert migrations until exactly those up to version are applied (0 reverts all)
+  status           list migrations and whether they are applied
+  baseline <ver>   record migrations up to version as applied without running them,
+                   for databases whose schema was created by hand`
+
+// runMigrate applies, reverts or lists the embedded migrations
+func runMigrate(cfg *config.Config, args []string) {
+	if len(args) == 0 {
+		log.Fatal(migrateUsage)
+	}
+
+	pool, err := db.Open(context.Background(), cfg)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	defer pool.Close()
+
+	migrator, err := db.NewMigrator(pool)
+	if err != nil {
+		log.Fatal("Failed to load migrations:", err)
+	}
+
+	ctx := context.Background()
+	var changed []int64
+
+	switch args[0] {
+	case "up":
+		changed, err = migrator.Up(ctx)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				log.Fatalf("Invalid number of migrations to revert: %s", args[1])
+			}
+		}
+		changed, err = migrator.Down(ctx, steps)
+	case "to":
+		changed, err = migrator.To(ctx, parseVersion(args))
+	case "baseline":
+		changed, err = migrator.Baseline(ctx, parseVersion(args))
+	case "status":
+		printMigrationStatus(ctx, migrator)
+		return
+	default:
+		log.Fatal(migrateUsage)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if len(changed) == 0 {
+		log.Println("Nothing to do, database is up to date")
+		return
+	}
+
+	version, err := migrator.Version(ctx)
+	if err != nil {
+		log.Fatal("Failed to read migration version:", err)
+	}
+	log.Printf("Database is at version %d", version)
+}
+
+// parseVersion reads the version argument of the to and baseline commands
+func parseVersion(args []string) int64 {
+	if len(args) < 2 {
+		log.Fatal(migrateUsage)
+	}
+	version, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil || version < 0 {
+		log.Fatalf("Invalid migration version: %s", args[1])
+	}
+	return version
+}
+
+// printMigrationStatus prints a table of the known migrations
+func printMigrationStatus(ctx context.Context, migrator *db.Migrator) {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		log.Fatal("Failed to read migration status:", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS")
+	for _, status := range statuses {
+		state := "pending"
+		if status.AppliedAt != nil {
+			state = "applied " + status.AppliedAt.Local().Format("2006-01-02 15:04:05")
+			if status.Modified {
+				state += " (modified since applied)"
+			}
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\n", status.Version, status.Name, state)
+	}
+	w.Flush()
+}
+
+// runSeed loads the sample data into a migrated database
+func runSeed(cfg *config.Config) {
+	pool, err := db.Open(context.Background(), cfg)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	defer pool.Close()
+
+	if err := db.Seed(context.Background(), pool); err != nil {
+		log.Fatal("Failed to seed database:", err)
+	}
+}
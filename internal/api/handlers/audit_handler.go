@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"go-backend-todo/internal/api/middlewares"
+	"go-backend-todo/internal/api/responses"
+	"go-backend-todo/internal/models"
+	"go-backend-todo/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// AuditHandler handles security audit log HTTP requests
+type AuditHandler struct {
+	auditService service.AuditService
+}
+
+// NewAuditHandler creates a new instance of audit handler
+func NewAuditHandler(auditService service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// GetSecurityEvents lists current user's security events
+// @Summary Get user's security events
+// @Description Retrieve the security events of the currently authenticated user, newest first: logins, failed logins, password changes and resets, token refreshes and email verifications, with the IP address and user agent of each
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param limit query int false "Number of items per page (default: 20)" minimum(1) maximum(100)
+// @Param offset query int false "Number of items to skip (default: 0)" minimum(0)
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Paginated security events"
+// @Failure 400 {object} map[string]string "Invalid query parameters"
+// @Failure 401 {object} map[string]string "Unauthorized - missing or invalid token"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /users/security-events [get]
+func (h *AuditHandler) GetSecurityEvents(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	limit, offset, err := parseAuditPagination(c)
+	if err != nil {
+		return responses.BadRequest(c, err.Error())
+	}
+
+	events, total, err := h.auditService.GetUserEvents(c.Context(), userID, limit, offset)
+	if err != nil {
+		return responses.InternalServerErrorWithError(c, "Failed to get security events", err)
+	}
+
+	page := (offset / limit) + 1
+	return responses.OKWithPagination(c, "Security events retrieved successfully", events, page, limit, total)
+}
+
+// QuerySecurityEvents queries the security events of all users
+// @Summary Query security events
+// @Description Query the security audit log of all users, newest first. Only administrators can query the audit log
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param user_id query string false "Only return the events of this user" format(uuid)
+// @Param event_type query string false "Only return events of this type" Enums(user.registered, login.succeeded, login.failed, password.changed, password.change_failed, password.reset, token.refreshed, token.refresh_failed, email.verified)
+// @Param email query string false "Only return events for this email address"
+// @Param ip_address query string false "Only return events from this IP address"
+// @Param from query string false "Only return events at or after this time (RFC 3339)"
+// @Param to query string false "Only return events before this time (RFC 3339)"
+// @Param limit query int false "Number of items per page (default: 20)" minimum(1) maximum(100)
+// @Param offset query int false "Number of items to skip (default: 0)" minimum(0)
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Paginated security events"
+// @Failure 400 {object} map[string]string "Invalid query parameters"
+// @Failure 401 {object} map[string]string "Unauthorized - missing or invalid token"
+// @Failure 403 {object} map[string]string "Forbidden - admin role required"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/security-events [get]
+func (h *AuditHandler) QuerySecurityEvents(c *fiber.Ctx) error {
+	limit, offset, err := parseAuditPagination(c)
+	if err != nil {
+		return responses.BadRequest(c, err.Error())
+	}
+
+	filter := models.AuditFilter{
+		EventType: models.AuditEventType(c.Query("event_type")),
+		Email:     c.Query("email"),
+		IPAddress: c.Query("ip_address"),
+		Limit:     limit,
+		Offset:    offset,
+	}
+
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return responses.BadRequest(c, "Invalid user_id parameter")
+		}
+		filter.UserID = &userID
+	}
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return responses.BadRequest(c, "Invalid from parameter, expected RFC 3339")
+		}
+		filter.From = &from
+	}
+
+	if toStr := c.Query("to"); toStr != "" {
+		to, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return responses.BadRequest(c, "Invalid to parameter, expected RFC 3339")
+		}
+		filter.To = &to
+	}
+
+	events, total, err := h.auditService.QueryEvents(c.Context(), filter)
+	if err != nil {
+		return responses.InternalServerErrorWithError(c, "Failed to get security events", err)
+	}
+
+	page := (offset / limit) + 1
+	return responses.OKWithPagination(c, "Security events retrieved successfully", events, page, limit, total)
+}
+
+// parseAuditPagination parses the limit and offset query parameters
+func parseAuditPagination(c *fiber.Ctx) (int, int, error) {
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		return 0, 0, fiber.NewError(fiber.StatusBadRequest, "Invalid limit parameter (1-100)")
+	}
+
+	offset, err := strconv.Atoi(c.Query("offset", "0"))
+	if err != nil || offset < 0 {
+		return 0, 0, fiber.NewError(fiber.StatusBadRequest, "Invalid offset parameter")
+	}
+
+	return limit, offset, nil
+}
@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"go-backend-todo/internal/api/middlewares"
 	"go-backend-todo/internal/api/responses"
 	"go-backend-todo/internal/models"
 	"go-backend-todo/internal/service"
+	"go-backend-todo/internal/utils"
 
 	"log"
 
@@ -41,9 +43,12 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	}
 
 	user, err := h.authService.Login(c.Context(), &req)
-	if err != nil {
+	if errors.Is(err, utils.ErrBadCredentials) {
 		return responses.Unauthorized(c, "Invalid email or password")
 	}
+	if err != nil {
+		return responses.InternalServerErrorWithError(c, "Failed to log in", err)
+	}
 
 	// Generate tokens
 	accessToken, err := h.jwtManager.GenerateAccessToken(user.UserID, user.Username, user.Email, string(user.Role), string(user.Status), user.TokenVersion)
@@ -209,7 +214,7 @@ func (h *AuthHandler) RefreshAccessToken(c *fiber.Ctx) error {
 		return responses.BadRequest(c, "Refresh token is required")
 	}
 	
-	newAccessToken, newRefreshToken, err := h.jwtManager.RefreshAccessToken(req.RefreshToken)
+	newAccessToken, newRefreshToken, err := h.jwtManager.RefreshAccessToken(c.Context(), req.RefreshToken)
 	if err != nil {
 		return responses.InternalServerError(c, "Failed to refresh access token: "+err.Error())
 	}
@@ -0,0 +1,684 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go-backend-todo/internal/api/middlewares"
+	"go-backend-todo/internal/models"
+	"go-backend-todo/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// XML namespaces of WebDAV, CalDAV and the calendar server extensions clients rely on
+const (
+	davNamespace          = "DAV:"
+	calDAVNamespace       = "urn:ietf:params:xml:ns:caldav"
+	calendarServerNS      = "http://calendarserver.org/ns/"
+	davBasePath           = "/dav"
+	davCollectionName     = "todos"
+	davObjectSuffix       = ".ics"
+	davObjectContentType  = "text/calendar; charset=utf-8; component=vtodo"
+	davMultistatusContent = "application/xml; charset=utf-8"
+)
+
+// davPrefixes are the prefixes multistatus responses declare for well-known namespaces
+var davPrefixes = map[string]string{
+	davNamespace:     "d",
+	calDAVNamespace:  "c",
+	calendarServerNS: "cs",
+}
+
+// Properties served by the CalDAV endpoints
+var (
+	davResourceType         = xml.Name{Space: davNamespace, Local: "resourcetype"}
+	davDisplayName          = xml.Name{Space: davNamespace, Local: "displayname"}
+	davCurrentUserPrincipal = xml.Name{Space: davNamespace, Local: "current-user-principal"}
+	davPrincipalURL         = xml.Name{Space: davNamespace, Local: "principal-URL"}
+	davOwner                = xml.Name{Space: davNamespace, Local: "owner"}
+	davGetETag              = xml.Name{Space: davNamespace, Local: "getetag"}
+	davGetContentType       = xml.Name{Space: davNamespace, Local: "getcontenttype"}
+	davGetLastModified      = xml.Name{Space: davNamespace, Local: "getlastmodified"}
+	davPrivilegeSet         = xml.Name{Space: davNamespace, Local: "current-user-privilege-set"}
+	davSupportedReportSet   = xml.Name{Space: davNamespace, Local: "supported-report-set"}
+	calHomeSet              = xml.Name{Space: calDAVNamespace, Local: "calendar-home-set"}
+	calUserAddressSet       = xml.Name{Space: calDAVNamespace, Local: "calendar-user-address-set"}
+	calComponentSet         = xml.Name{Space: calDAVNamespace, Local: "supported-calendar-component-set"}
+	calSupportedData        = xml.Name{Space: calDAVNamespace, Local: "supported-calendar-data"}
+	calData                 = xml.Name{Space: calDAVNamespace, Local: "calendar-data"}
+	csGetCTag               = xml.Name{Space: calendarServerNS, Local: "getctag"}
+)
+
+// davPropNames lists the properties named in a PROPFIND or REPORT body
+type davPropNames struct {
+	Names []struct {
+		XMLName xml.Name
+	} `xml:",any"`
+}
+
+// davPropfind is the body of a PROPFIND request
+type davPropfind struct {
+	AllProp  *struct{}     `xml:"DAV: allprop"`
+	PropName *struct{}     `xml:"DAV: propname"`
+	Prop     *davPropNames `xml:"DAV: prop"`
+}
+
+// davCompFilter is a comp-filter of a calendar-query REPORT
+type davCompFilter struct {
+	Name    string          `xml:"name,attr"`
+	Filters []davCompFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+// davReport is the body of a calendar-query or calendar-multiget REPORT
+type davReport struct {
+	XMLName xml.Name
+	Prop    *davPropNames  `xml:"DAV: prop"`
+	Hrefs   []string       `xml:"DAV: href"`
+	Filter  *davCompFilter `xml:"urn:ietf:params:xml:ns:caldav filter>comp-filter"`
+}
+
+// davResource is one response of a multistatus: the properties of a resource, or only a status
+type davResource struct {
+	href   string
+	props  map[xml.Name]string // inner XML of each property
+	status int                 // set instead of props for resources that could not be read
+}
+
+// davRequest describes which properties a PROPFIND or REPORT asked for
+type davRequest struct {
+	names    []xml.Name // nil for allprop
+	propName bool
+}
+
+// wants reports whether a property has to be computed for the request
+func (r davRequest) wants(name xml.Name) bool {
+	if r.names == nil {
+		return name != calData
+	}
+	for _, n := range r.names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// CalDAVHandler serves the todos of a user as a CalDAV task collection
+type CalDAVHandler struct {
+	caldavService service.CalDAVService
+	appName       string
+}
+
+// NewCalDAVHandler creates a new instance of CalDAV handler
+func NewCalDAVHandler(caldavService service.CalDAVService, appName string) *CalDAVHandler {
+	return &CalDAVHandler{
+		caldavService: caldavService,
+		appName:       appName,
+	}
+}
+
+// WellKnown redirects CalDAV service discovery (RFC 6764) to the CalDAV root
+func (h *CalDAVHandler) WellKnown(c *fiber.Ctx) error {
+	return c.Redirect(davBasePath+"/", fiber.StatusMovedPermanently)
+}
+
+// Options advertises the DAV compliance classes and methods, without requiring authentication
+func (h *CalDAVHandler) Options(c *fiber.Ctx) error {
+	c.Set("DAV", "1, 3, calendar-access")
+	c.Set(fiber.HeaderAllow, "OPTIONS, GET, HEAD, PUT, DELETE, PROPFIND, PROPPATCH, REPORT")
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// PropfindRoot answers PROPFIND on the CalDAV root, pointing clients to the user's principal
+func (h *CalDAVHandler) PropfindRoot(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return c.SendStatus(fiber.StatusUnauthorized)
+	}
+
+	req, err := parsePropfind(c.Body())
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	props := map[xml.Name]string{
+		davResourceType:         "<d:collection/>",
+		davDisplayName:          escapeXML(h.appName),
+		davCurrentUserPrincipal: davHref(principalPath(userID)),
+	}
+	return writeMultistatus(c, req, []davResource{{href: davBasePath + "/", props: props}})
+}
+
+// PropfindPrincipal answers PROPFIND on the user's principal, pointing clients to the calendar home
+func (h *CalDAVHandler) PropfindPrincipal(c *fiber.Ctx) error {
+	userID, ok := h.authorizePath(c)
+	if !ok {
+		return nil
+	}
+
+	req, err := parsePropfind(c.Body())
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	props := map[xml.Name]string{
+		davResourceType:         "<d:principal/>",
+		davDisplayName:          escapeXML(localString(c, "username")),
+		davCurrentUserPrincipal: davHref(principalPath(userID)),
+		davPrincipalURL:         davHref(principalPath(userID)),
+		calHomeSet:              davHref(homePath(userID)),
+		calUserAddressSet:       davHref("mailto:" + localString(c, "email")),
+	}
+	return writeMultistatus(c, req, []davResource{{href: principalPath(userID), props: props}})
+}
+
+// PropfindHome answers PROPFIND on the calendar home, which contains the task collection
+func (h *CalDAVHandler) PropfindHome(c *fiber.Ctx) error {
+	userID, ok := h.authorizePath(c)
+	if !ok {
+		return nil
+	}
+
+	req, err := parsePropfind(c.Body())
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	resources := []davResource{{href: homePath(userID), props: map[xml.Name]string{
+		davResourceType:         "<d:collection/>",
+		davCurrentUserPrincipal: davHref(principalPath(userID)),
+		davOwner:                davHref(principalPath(userID)),
+	}}}
+
+	if davDepth(c) > 0 {
+		collection, err := h.collectionProps(c, userID, req)
+		if err != nil {
+			return c.SendStatus(fiber.StatusInternalServerError)
+		}
+		resources = append(resources, collection)
+	}
+
+	return writeMultistatus(c, req, resources)
+}
+
+// PropfindCollection answers PROPFIND on the task collection and, with Depth 1, on its todos
+func (h *CalDAVHandler) PropfindCollection(c *fiber.Ctx) error {
+	userID, ok := h.authorizePath(c)
+	if !ok {
+		return nil
+	}
+
+	req, err := parsePropfind(c.Body())
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	collection, err := h.collectionProps(c, userID, req)
+	if err != nil {
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+	resources := []davResource{collection}
+
+	if davDepth(c) > 0 {
+		todos, err := h.caldavService.GetTodos(c.Context(), userID)
+		if err != nil {
+			return c.SendStatus(fiber.StatusInternalServerError)
+		}
+		for _, todo := range todos {
+			resources = append(resources, h.objectResource(userID, todo, req))
+		}
+	}
+
+	return writeMultistatus(c, req, resources)
+}
+
+// PropfindObject answers PROPFIND on a single todo
+func (h *CalDAVHandler) PropfindObject(c *fiber.Ctx) error {
+	userID, ok := h.authorizePath(c)
+	if !ok {
+		return nil
+	}
+	id, ok := objectID(c.Params("resource"))
+	if !ok {
+		return c.SendStatus(fiber.StatusNotFound)
+	}
+
+	req, err := parsePropfind(c.Body())
+	if err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	todo, err := h.caldavService.GetTodo(c.Context(), userID, id)
+	if errors.Is(err, service.ErrTodoNotFound) {
+		return c.SendStatus(fiber.StatusNotFound)
+	}
+	if err != nil {
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return writeMultistatus(c, req, []davResource{h.objectResource(userID, todo, req)})
+}
+
+// Proppatch refuses property changes, such as a client renaming or recoloring the collection
+func (h *CalDAVHandler) Proppatch(c *fiber.Ctx) error {
+	if _, ok := h.authorizePath(c); !ok {
+		return nil
+	}
+
+	var update struct {
+		Set    []davPropNames `xml:"DAV: set>prop"`
+		Remove []davPropNames `xml:"DAV: remove>prop"`
+	}
+	if err := xml.Unmarshal(c.Body(), &update); err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	var names []xml.Name
+	for _, props := range append(update.Set, update.Remove...) {
+		for _, n := range props.Names {
+			names = append(names, n.XMLName)
+		}
+	}
+
+	var body bytes.Buffer
+	body.WriteString(xml.Header)
+	body.WriteString(`<d:multistatus xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav" xmlns:cs="http://calendarserver.org/ns/">`)
+	body.WriteString("<d:response>" + davHref(c.Path()))
+	writePropstat(&body, names, nil, fiber.StatusForbidden)
+	body.WriteString("</d:response></d:multistatus>")
+
+	c.Set(fiber.HeaderContentType, davMultistatusContent)
+	return c.Status(fiber.StatusMultiStatus).Send(body.Bytes())
+}
+
+// Report answers calendar-query and calendar-multiget REPORTs on the task collection
+func (h *CalDAVHandler) Report(c *fiber.Ctx) error {
+	userID, ok := h.authorizePath(c)
+	if !ok {
+		return nil
+	}
+
+	var report davReport
+	if err := xml.Unmarshal(c.Body(), &report); err != nil {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	req := davRequest{}
+	if report.Prop != nil {
+		req.names = make([]xml.Name, 0, len(report.Prop.Names))
+		for _, n := range report.Prop.Names {
+			req.names = append(req.names, n.XMLName)
+		}
+	}
+
+	var resources []davResource
+	switch report.XMLName {
+	case xml.Name{Space: calDAVNamespace, Local: "calendar-query"}:
+		if !matchesVTODO(report.Filter) {
+			break
+		}
+		todos, err := h.caldavService.GetTodos(c.Context(), userID)
+		if err != nil {
+			return c.SendStatus(fiber.StatusInternalServerError)
+		}
+		for _, todo := range todos {
+			resources = append(resources, h.objectResource(userID, todo, req))
+		}
+	case xml.Name{Space: calDAVNamespace, Local: "calendar-multiget"}:
+		for _, href := range report.Hrefs {
+			href = strings.TrimSpace(href)
+			resource := davResource{href: href, status: fiber.StatusNotFound}
+			if id, ok := objectID(href[strings.LastIndex(href, "/")+1:]); ok && strings.HasPrefix(href, collectionPath(userID)) {
+				todo, err := h.caldavService.GetTodo(c.Context(), userID, id)
+				if err != nil && !errors.Is(err, service.ErrTodoNotFound) {
+					return c.SendStatus(fiber.StatusInternalServerError)
+				}
+				if err == nil {
+					resource = h.objectResource(userID, todo, req)
+				}
+			}
+			resources = append(resources, resource)
+		}
+	default:
+		return davError(c, fiber.StatusForbidden, "<d:supported-report/>")
+	}
+
+	return writeMultistatus(c, req, resources)
+}
+
+// GetObject returns a todo as an iCalendar object
+func (h *CalDAVHandler) GetObject(c *fiber.Ctx) error {
+	userID, ok := h.authorizePath(c)
+	if !ok {
+		return nil
+	}
+	id, ok := objectID(c.Params("resource"))
+	if !ok {
+		return c.SendStatus(fiber.StatusNotFound)
+	}
+
+	todo, err := h.caldavService.GetTodo(c.Context(), userID, id)
+	if errors.Is(err, service.ErrTodoNotFound) {
+		return c.SendStatus(fiber.StatusNotFound)
+	}
+	if err != nil {
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	data, err := h.caldavService.Render(todo)
+	if err != nil {
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	c.Set(fiber.HeaderETag, todo.ETag())
+	c.Set(fiber.HeaderLastModified, todo.UpdatedAt.UTC().Format(http.TimeFormat))
+	c.Set(fiber.HeaderContentType, davObjectContentType)
+	return c.Send(data)
+}
+
+// PutObject creates or updates a todo from an iCalendar object with a VTODO.
+// New resources must be named after a UUID, which becomes the ID of the todo.
+func (h *CalDAVHandler) PutObject(c *fiber.Ctx) error {
+	userID, ok := h.authorizePath(c)
+	if !ok {
+		return nil
+	}
+	id, ok := objectID(c.Params("resource"))
+	if !ok {
+		return davError(c, fiber.StatusForbidden, "<c:valid-calendar-object-resource/>")
+	}
+
+	todo, created, err := h.caldavService.PutTodo(c.Context(), userID, id, c.Body(), c.Get(fiber.HeaderIfMatch), c.Get(fiber.HeaderIfNoneMatch))
+	switch {
+	case errors.Is(err, service.ErrInvalidCalendarData):
+		return davError(c, fiber.StatusForbidden, "<c:valid-calendar-data/>")
+	case errors.Is(err, service.ErrPreconditionFailed):
+		return c.SendStatus(fiber.StatusPreconditionFailed)
+	case errors.Is(err, service.ErrTodoIDTaken):
+		return davError(c, fiber.StatusConflict, "<c:no-uid-conflict/>")
+	case errors.Is(err, service.ErrListForbidden), errors.Is(err, service.ErrTodoNotFound):
+		return c.SendStatus(fiber.StatusForbidden)
+	case err != nil:
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	c.Set(fiber.HeaderETag, todo.ETag())
+	if created {
+		c.Set(fiber.HeaderLocation, objectPath(userID, todo.ID))
+		return c.SendStatus(fiber.StatusCreated)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// DeleteObject moves a todo to the trash
+func (h *CalDAVHandler) DeleteObject(c *fiber.Ctx) error {
+	userID, ok := h.authorizePath(c)
+	if !ok {
+		return nil
+	}
+	id, ok := objectID(c.Params("resource"))
+	if !ok {
+		return c.SendStatus(fiber.StatusNotFound)
+	}
+
+	err := h.caldavService.DeleteTodo(c.Context(), userID, id, c.Get(fiber.HeaderIfMatch))
+	switch {
+	case errors.Is(err, service.ErrTodoNotFound):
+		return c.SendStatus(fiber.StatusNotFound)
+	case errors.Is(err, service.ErrPreconditionFailed):
+		return c.SendStatus(fiber.StatusPreconditionFailed)
+	case errors.Is(err, service.ErrListForbidden):
+		return c.SendStatus(fiber.StatusForbidden)
+	case err != nil:
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// authorizePath checks that the user in the path is the authenticated user, answering the request otherwise
+func (h *CalDAVHandler) authorizePath(c *fiber.Ctx) (uuid.UUID, bool) {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		c.SendStatus(fiber.StatusUnauthorized)
+		return uuid.Nil, false
+	}
+
+	if pathUserID, err := uuid.Parse(c.Params("userId")); err != nil || pathUserID != userID {
+		c.SendStatus(fiber.StatusForbidden)
+		return uuid.Nil, false
+	}
+
+	return userID, true
+}
+
+// collectionProps returns the properties of the task collection
+func (h *CalDAVHandler) collectionProps(c *fiber.Ctx, userID uuid.UUID, req davRequest) (davResource, error) {
+	props := map[xml.Name]string{
+		davResourceType:         "<d:collection/><c:calendar/>",
+		davDisplayName:          "Todos",
+		davCurrentUserPrincipal: davHref(principalPath(userID)),
+		davOwner:                davHref(principalPath(userID)),
+		davPrivilegeSet: "<d:privilege><d:read/></d:privilege><d:privilege><d:write/></d:privilege>" +
+			"<d:privilege><d:write-content/></d:privilege><d:privilege><d:bind/></d:privilege><d:privilege><d:unbind/></d:privilege>",
+		davSupportedReportSet: "<d:supported-report><d:report><c:calendar-query/></d:report></d:supported-report>" +
+			"<d:supported-report><d:report><c:calendar-multiget/></d:report></d:supported-report>",
+		calComponentSet:  `<c:comp name="VTODO"/>`,
+		calSupportedData: `<c:calendar-data content-type="text/calendar" version="2.0"/>`,
+	}
+
+	if req.wants(csGetCTag) {
+		ctag, err := h.caldavService.CollectionTag(c.Context(), userID)
+		if err != nil {
+			return davResource{}, err
+		}
+		props[csGetCTag] = escapeXML(ctag)
+	}
+
+	return davResource{href: collectionPath(userID), props: props}, nil
+}
+
+// objectResource returns the properties of a todo, rendering its calendar data only when requested
+func (h *CalDAVHandler) objectResource(userID uuid.UUID, todo *models.Todo, req davRequest) davResource {
+	props := map[xml.Name]string{
+		davResourceType:    "",
+		davGetETag:         escapeXML(todo.ETag()),
+		davGetContentType:  davObjectContentType,
+		davGetLastModified: todo.UpdatedAt.UTC().Format(http.TimeFormat),
+	}
+
+	if req.wants(calData) {
+		if data, err := h.caldavService.Render(todo); err == nil {
+			props[calData] = escapeXML(string(data))
+		}
+	}
+
+	return davResource{href: objectPath(userID, todo.ID), props: props}
+}
+
+// parsePropfind reads a PROPFIND body, where an empty body asks for all properties
+func parsePropfind(body []byte) (davRequest, error) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return davRequest{}, nil
+	}
+
+	var propfind davPropfind
+	if err := xml.Unmarshal(body, &propfind); err != nil {
+		return davRequest{}, err
+	}
+
+	switch {
+	case propfind.PropName != nil:
+		return davRequest{propName: true}, nil
+	case propfind.Prop != nil:
+		names := make([]xml.Name, 0, len(propfind.Prop.Names))
+		for _, n := range propfind.Prop.Names {
+			names = append(names, n.XMLName)
+		}
+		return davRequest{names: names}, nil
+	default:
+		return davRequest{}, nil
+	}
+}
+
+// writeMultistatus answers with a 207 Multi-Status listing the requested properties of each resource
+func writeMultistatus(c *fiber.Ctx, req davRequest, resources []davResource) error {
+	var body bytes.Buffer
+	body.WriteString(xml.Header)
+	body.WriteString(`<d:multistatus xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav" xmlns:cs="http://calendarserver.org/ns/">`)
+
+	for _, resource := range resources {
+		body.WriteString("<d:response>" + davHref(resource.href))
+
+		if resource.props == nil {
+			fmt.Fprintf(&body, "<d:status>HTTP/1.1 %d %s</d:status>", resource.status, http.StatusText(resource.status))
+			body.WriteString("</d:response>")
+			continue
+		}
+
+		switch {
+		case req.propName:
+			writePropstat(&body, sortedPropNames(resource.props), nil, fiber.StatusOK)
+		case req.names == nil:
+			writePropstat(&body, sortedPropNames(resource.props), resource.props, fiber.StatusOK)
+		default:
+			var found, missing []xml.Name
+			for _, name := range req.names {
+				if _, ok := resource.props[name]; ok {
+					found = append(found, name)
+				} else {
+					missing = append(missing, name)
+				}
+			}
+			writePropstat(&body, found, resource.props, fiber.StatusOK)
+			writePropstat(&body, missing, nil, fiber.StatusNotFound)
+		}
+
+		body.WriteString("</d:response>")
+	}
+
+	body.WriteString("</d:multistatus>")
+
+	c.Set(fiber.HeaderContentType, davMultistatusContent)
+	return c.Status(fiber.StatusMultiStatus).Send(body.Bytes())
+}
+
+// writePropstat writes the given properties with their values, or empty, under one status
+func writePropstat(body *bytes.Buffer, names []xml.Name, values map[xml.Name]string, status int) {
+	if len(names) == 0 {
+		return
+	}
+
+	body.WriteString("<d:propstat><d:prop>")
+	for _, name := range names {
+		open, close := davElement(name)
+		if value := values[name]; value != "" {
+			body.WriteString(open + ">" + value + close)
+		} else {
+			body.WriteString(open + "/>")
+		}
+	}
+	fmt.Fprintf(body, "</d:prop><d:status>HTTP/1.1 %d %s</d:status></d:propstat>", status, http.StatusText(status))
+}
+
+// davElement returns the unterminated start tag and the end tag of a property, declaring unknown namespaces inline
+func davElement(name xml.Name) (string, string) {
+	if prefix, ok := davPrefixes[name.Space]; ok {
+		return "<" + prefix + ":" + name.Local, "</" + prefix + ":" + name.Local + ">"
+	}
+	return fmt.Sprintf(`<x:%s xmlns:x="%s"`, name.Local, escapeXML(name.Space)), "</x:" + name.Local + ">"
+}
+
+// sortedPropNames returns the names of the properties in a stable order, leaving out calendar data as allprop does
+func sortedPropNames(props map[xml.Name]string) []xml.Name {
+	names := make([]xml.Name, 0, len(props))
+	for name := range props {
+		if name == calData {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if names[i].Space != names[j].Space {
+			return names[i].Space < names[j].Space
+		}
+		return names[i].Local < names[j].Local
+	})
+	return names
+}
+
+// matchesVTODO reports whether a calendar-query filter selects VTODO components, the only ones served
+func matchesVTODO(filter *davCompFilter) bool {
+	if filter == nil || len(filter.Filters) == 0 {
+		return true
+	}
+	for _, f := range filter.Filters {
+		if strings.EqualFold(f.Name, "VTODO") {
+			return true
+		}
+	}
+	return false
+}
+
+// davError answers with a WebDAV error body naming the failed precondition
+func davError(c *fiber.Ctx, status int, condition string) error {
+	c.Set(fiber.HeaderContentType, davMultistatusContent)
+	body := xml.Header + `<d:error xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">` + condition + `</d:error>`
+	return c.Status(status).SendString(body)
+}
+
+// davDepth returns the Depth header, treating infinity as 1 since collections hold no nested collections
+func davDepth(c *fiber.Ctx) int {
+	if depth, err := strconv.Atoi(c.Get("Depth")); err == nil {
+		return depth
+	}
+	return 1
+}
+
+// objectID extracts the todo ID from a resource name such as "<uuid>.ics"
+func objectID(resource string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(strings.TrimSuffix(resource, davObjectSuffix))
+	return id, err == nil
+}
+
+// localString returns a string stored in the request context by the authentication middleware
+func localString(c *fiber.Ctx, key string) string {
+	value, _ := c.Locals(key).(string)
+	return value
+}
+
+func principalPath(userID uuid.UUID) string {
+	return davBasePath + "/principals/" + userID.String() + "/"
+}
+
+func homePath(userID uuid.UUID) string {
+	return davBasePath + "/calendars/" + userID.String() + "/"
+}
+
+func collectionPath(userID uuid.UUID) string {
+	return homePath(userID) + davCollectionName + "/"
+}
+
+func objectPath(userID, todoID uuid.UUID) string {
+	return collectionPath(userID) + todoID.String() + davObjectSuffix
+}
+
+// davHref returns an href element
+func davHref(href string) string {
+	return "<d:href>" + escapeXML(href) + "</d:href>"
+}
+
+// escapeXML escapes text for use in XML content and attributes
+func escapeXML(value string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(value))
+	return b.String()
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"errors"
+
+	"go-backend-todo/internal/api/middlewares"
+	"go-backend-todo/internal/api/responses"
+	"go-backend-todo/internal/models"
+	"go-backend-todo/internal/service"
+	"go-backend-todo/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// CommentHandler handles todo comment HTTP requests
+type CommentHandler struct {
+	commentService service.CommentService
+}
+
+// NewCommentHandler creates a new instance of comment handler
+func NewCommentHandler(commentService service.CommentService) *CommentHandler {
+	return &CommentHandler{
+		commentService: commentService,
+	}
+}
+
+// GetComments lists the comments of a todo
+// @Summary Get todo comments
+// @Description Retrieve the comments of a todo, oldest first, with replies nested under the comment they answer
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Param id path string true "Todo ID" format(uuid)
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Comments retrieved successfully"
+// @Failure 401 {object} map[string]string "Unauthorized - missing or invalid token"
+// @Failure 404 {object} map[string]string "Todo not found"
+// @Router /todos/{id}/comments [get]
+func (h *CommentHandler) GetComments(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	todoID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return responses.BadRequest(c, "Invalid todo ID format")
+	}
+
+	comments, err := h.commentService.GetComments(c.Context(), todoID, userID)
+	if err != nil {
+		return commentErrorResponse(c, "Failed to get comments", err)
+	}
+
+	return responses.OK(c, "Comments retrieved successfully", comments)
+}
+
+// CreateComment comments on a todo
+// @Summary Comment on a todo
+// @Description Add a markdown comment to a todo, optionally replying to another comment. Users mentioned with @username who can see the todo are notified by email
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Param id path string true "Todo ID" format(uuid)
+// @Param comment body models.CreateCommentRequest true "Comment data"
+// @Security BearerAuth
+// @Success 201 {object} map[string]interface{} "Comment created successfully"
+// @Failure 400 {object} map[string]string "Invalid request data"
+// @Failure 401 {object} map[string]string "Unauthorized - missing or invalid token"
+// @Failure 404 {object} map[string]string "Todo or parent comment not found"
+// @Router /todos/{id}/comments [post]
+func (h *CommentHandler) CreateComment(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	todoID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return responses.BadRequest(c, "Invalid todo ID format")
+	}
+
+	body := c.Body()
+	var req models.CreateCommentRequest
+	if err := middlewares.RequestValidation(&body, &req)(c); err != nil {
+		return responses.BadRequestWithError(c, "Invalid request body", err)
+	}
+
+	comment, err := h.commentService.CreateComment(c.Context(), todoID, userID, &req)
+	if err != nil {
+		return commentErrorResponse(c, "Failed to create comment", err)
+	}
+
+	return responses.Created(c, "Comment created successfully", comment)
+}
+
+// UpdateComment edits a comment
+// @Summary Edit a comment
+// @Description Edit the body of a comment. Only its author can edit a comment. Users newly mentioned are notified by email
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Param id path string true "Todo ID" format(uuid)
+// @Param commentId path string true "Comment ID" format(uuid)
+// @Param comment body models.UpdateCommentRequest true "Comment update data"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Comment updated successfully"
+// @Failure 400 {object} map[string]string "Invalid request data"
+// @Failure 401 {object} map[string]string "Unauthorized - missing or invalid token"
+// @Failure 403 {object} map[string]string "Not the author of the comment"
+// @Failure 404 {object} map[string]string "Todo or comment not found"
+// @Router /todos/{id}/comments/{commentId} [put]
+func (h *CommentHandler) UpdateComment(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	todoID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return responses.BadRequest(c, "Invalid todo ID format")
+	}
+
+	commentID, err := uuid.Parse(c.Params("commentId"))
+	if err != nil {
+		return responses.BadRequest(c, "Invalid comment ID format")
+	}
+
+	body := c.Body()
+	var req models.UpdateCommentRequest
+	if err := middlewares.RequestValidation(&body, &req)(c); err != nil {
+		return responses.BadRequestWithError(c, "Invalid request body", err)
+	}
+
+	comment, err := h.commentService.UpdateComment(c.Context(), todoID, commentID, userID, &req)
+	if err != nil {
+		return commentErrorResponse(c, "Failed to update comment", err)
+	}
+
+	return responses.OK(c, "Comment updated successfully", comment)
+}
+
+// DeleteComment deletes a comment
+// @Summary Delete a comment
+// @Description Delete a comment. Only its author can delete a comment; its replies move up to the comment it answered
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Param id path string true "Todo ID" format(uuid)
+// @Param commentId path string true "Comment ID" format(uuid)
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Comment deleted successfully"
+// @Failure 401 {object} map[string]string "Unauthorized - missing or invalid token"
+// @Failure 403 {object} map[string]string "Not the author of the comment"
+// @Failure 404 {object} map[string]string "Todo or comment not found"
+// @Router /todos/{id}/comments/{commentId} [delete]
+func (h *CommentHandler) DeleteComment(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	todoID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return responses.BadRequest(c, "Invalid todo ID format")
+	}
+
+	commentID, err := uuid.Parse(c.Params("commentId"))
+	if err != nil {
+		return responses.BadRequest(c, "Invalid comment ID format")
+	}
+
+	if err := h.commentService.DeleteComment(c.Context(), todoID, commentID, userID); err != nil {
+		return commentErrorResponse(c, "Failed to delete comment", err)
+	}
+
+	return responses.OK(c, "Comment deleted successfully", nil)
+}
+
+// commentErrorResponse maps comment service errors to HTTP responses
+func commentErrorResponse(c *fiber.Ctx, message string, err error) error {
+	switch {
+	case errors.Is(err, service.ErrTodoNotFound):
+		return responses.NotFound(c, "Todo not found")
+	case errors.Is(err, service.ErrCommentNotFound):
+		return responses.NotFound(c, "Comment not found")
+	case errors.Is(err, service.ErrCommentForbidden):
+		return responses.Forbidden(c, "Only the author can change this comment")
+	case errors.Is(err, utils.ErrBadInput):
+		return responses.BadRequestWithError(c, message, err)
+	default:
+		return responses.InternalServerErrorWithError(c, message, err)
+	}
+}
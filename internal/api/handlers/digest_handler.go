@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"go-backend-todo/internal/api/responses"
+	"go-backend-todo/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DigestHandler handles daily digest HTTP requests
+type DigestHandler struct {
+	digestService service.DigestService
+}
+
+// NewDigestHandler creates a new instance of digest handler
+func NewDigestHandler(digestService service.DigestService) *DigestHandler {
+	return &DigestHandler{
+		digestService: digestService,
+	}
+}
+
+// Unsubscribe handles the unsubscribe link of the digest email
+// @Summary Unsubscribe from daily digest
+// @Description Disable the daily digest using the signed token from the digest email. No login required
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param token path string true "Signed unsubscribe token"
+// @Router /digest/unsubscribe/{token} [get]
+func (h *DigestHandler) Unsubscribe(c *fiber.Ctx) error {
+	token := c.Params("token")
+	if token == "" {
+		return responses.BadRequest(c, "Token is required")
+	}
+
+	if err := h.digestService.Unsubscribe(c.Context(), token); err != nil {
+		return responses.BadRequest(c, "Invalid or expired unsubscribe link")
+	}
+
+	return responses.OK(c, "You have been unsubscribed from the daily digest", nil)
+}
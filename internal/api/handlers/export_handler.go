@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+
+	"go-backend-todo/internal/api/middlewares"
+	"go-backend-todo/internal/api/responses"
+	"go-backend-todo/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ExportHandler handles account data export HTTP requests
+type ExportHandler struct {
+	exportService service.ExportService
+}
+
+// NewExportHandler creates a new instance of export handler
+func NewExportHandler(exportService service.ExportService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+// RequestExport queues an export of all data stored about the current user
+// @Summary Request account data export
+// @Description Assemble a ZIP archive of the profile, preferences, todos, lists, comments and security events of the current user in the background, and email a download link that expires after a while. Only one export can be pending or downloadable at a time
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} models.DataExport "Export queued"
+// @Failure 401 {object} map[string]string "Unauthorized - missing or invalid token"
+// @Failure 409 {object} map[string]string "An export is already pending or downloadable"
+// @Router /users/export [post]
+func (h *ExportHandler) RequestExport(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	export, err := h.exportService.RequestExport(c.Context(), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrExportInProgress) {
+			return responses.Conflict(c, "A data export is already in progress, check your email for the download link")
+		}
+		return responses.InternalServerErrorWithError(c, "Failed to request data export", err)
+	}
+
+	return responses.Accepted(c, "Data export requested, the download link will be sent by email", export)
+}
+
+// DownloadExport downloads the archive of a data export
+// @Summary Download account data export
+// @Description Download the ZIP archive of a data export using the signed link from the export email. No login required
+// @Tags Users
+// @Produce application/zip
+// @Param token path string true "Signed download token"
+// @Success 200 {file} file "ZIP archive of JSON files"
+// @Failure 404 {object} map[string]string "Invalid or expired link"
+// @Router /exports/{token} [get]
+func (h *ExportHandler) DownloadExport(c *fiber.Ctx) error {
+	export, archive, err := h.exportService.GetArchive(c.Context(), c.Params("token"))
+	if err != nil {
+		if errors.Is(err, service.ErrExportNotFound) {
+			return responses.NotFound(c, "Invalid or expired download link")
+		}
+		return responses.InternalServerErrorWithError(c, "Failed to download data export", err)
+	}
+
+	filename := fmt.Sprintf("data-export-%s.zip", export.CreatedAt.UTC().Format("2006-01-02"))
+	c.Set(fiber.HeaderContentType, "application/zip")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Send(archive)
+}
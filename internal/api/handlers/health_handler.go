@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"go-backend-todo/internal/api/responses"
+	"go-backend-todo/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HealthHandler handles health probe HTTP requests
+type HealthHandler struct {
+	healthService service.HealthService
+}
+
+// NewHealthHandler creates a new instance of health handler
+func NewHealthHandler(healthService service.HealthService) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+	}
+}
+
+// Live reports that the process is up and serving requests. It checks no dependencies, so an outage
+// of the database does not get every instance restarted. Like the CalDAV routes, the probes live outside of the versioned API.
+func (h *HealthHandler) Live(c *fiber.Ctx) error {
+	return responses.OK(c, "Service is alive", nil)
+}
+
+// Ready reports whether the instance should receive traffic, failing while it drains before shutdown
+func (h *HealthHandler) Ready(c *fiber.Ctx) error {
+	readiness := h.healthService.Readiness(c.Context())
+	if !readiness.Ready {
+		return responses.ServiceUnavailable(c, "Service is not ready", readiness)
+	}
+
+	return responses.OK(c, "Service is ready", readiness)
+}
+
+// Version reports the build the instance runs
+func (h *HealthHandler) Version(c *fiber.Ctx) error {
+	return responses.OK(c, "Build info retrieved successfully", h.healthService.BuildInfo())
+}
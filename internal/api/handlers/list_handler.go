@@ -0,0 +1,421 @@
+package handlers
+
+import (
+	"errors"
+
+	"go-backend-todo/internal/api/middlewares"
+	"go-backend-todo/internal/api/responses"
+	"go-backend-todo/internal/models"
+	"go-backend-todo/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ListHandler handles shared list HTTP requests
+type ListHandler struct {
+	listService service.ListService
+}
+
+// NewListHandler creates a new instance of list handler
+func NewListHandler(listService service.ListService) *ListHandler {
+	return &ListHandler{
+		listService: listService,
+	}
+}
+
+// GetLists lists current user's lists
+// @Summary Get user's lists
+// @Description Retrieve the shared lists the currently authenticated user is a member of, with the user's role in each
+// @Tags Lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Router /lists [get]
+func (h *ListHandler) GetLists(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	lists, err := h.listService.GetLists(c.Context(), userID)
+	if err != nil {
+		return responses.InternalServerErrorWithError(c, "Failed to get lists", err)
+	}
+
+	return responses.OK(c, "Lists retrieved successfully", lists)
+}
+
+// CreateList creates a list
+// @Summary Create list
+// @Description Create a shared list owned by the currently authenticated user. Todos are added to it with list_id
+// @Tags Lists
+// @Accept json
+// @Produce json
+// @Param list body models.CreateListRequest true "List data"
+// @Security BearerAuth
+// @Router /lists [post]
+func (h *ListHandler) CreateList(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	body := c.Body()
+	var req models.CreateListRequest
+	if err := middlewares.RequestValidation(&body, &req)(c); err != nil {
+		return responses.BadRequestWithError(c, "Invalid request body", err)
+	}
+
+	list, err := h.listService.CreateList(c.Context(), userID, &req)
+	if err != nil {
+		return responses.BadRequestWithError(c, "Failed to create list", err)
+	}
+
+	return responses.Created(c, "List created successfully", list)
+}
+
+// GetList gets a list by ID
+// @Summary Get list by ID
+// @Description Retrieve a list the currently authenticated user is a member of
+// @Tags Lists
+// @Accept json
+// @Produce json
+// @Param id path string true "List ID" format(uuid)
+// @Security BearerAuth
+// @Router /lists/{id} [get]
+func (h *ListHandler) GetList(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return responses.BadRequest(c, "Invalid list ID format")
+	}
+
+	list, err := h.listService.GetList(c.Context(), id, userID)
+	if err != nil {
+		return listErrorResponse(c, "Failed to get list", err)
+	}
+
+	return responses.OK(c, "List retrieved successfully", list)
+}
+
+// UpdateList renames a list
+// @Summary Update list
+// @Description Rename a list. Only the owner can update a list
+// @Tags Lists
+// @Accept json
+// @Produce json
+// @Param id path string true "List ID" format(uuid)
+// @Param list body models.UpdateListRequest true "List update data"
+// @Security BearerAuth
+// @Router /lists/{id} [put]
+func (h *ListHandler) UpdateList(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return responses.BadRequest(c, "Invalid list ID format")
+	}
+
+	body := c.Body()
+	var req models.UpdateListRequest
+	if err := middlewares.RequestValidation(&body, &req)(c); err != nil {
+		return responses.BadRequestWithError(c, "Invalid request body", err)
+	}
+
+	list, err := h.listService.UpdateList(c.Context(), id, userID, &req)
+	if err != nil {
+		return listErrorResponse(c, "Failed to update list", err)
+	}
+
+	return responses.OK(c, "List updated successfully", list)
+}
+
+// DeleteList deletes a list
+// @Summary Delete list
+// @Description Permanently delete a list together with its todos, members and invitations. Only the owner can delete a list
+// @Tags Lists
+// @Accept json
+// @Produce json
+// @Param id path string true "List ID" format(uuid)
+// @Security BearerAuth
+// @Router /lists/{id} [delete]
+func (h *ListHandler) DeleteList(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return responses.BadRequest(c, "Invalid list ID format")
+	}
+
+	if err := h.listService.DeleteList(c.Context(), id, userID); err != nil {
+		return listErrorResponse(c, "Failed to delete list", err)
+	}
+
+	return responses.OK(c, "List deleted successfully", nil)
+}
+
+// GetMembers lists the members of a list
+// @Summary Get list members
+// @Description Retrieve the members of a list and their roles (owner, editor, viewer)
+// @Tags Lists
+// @Accept json
+// @Produce json
+// @Param id path string true "List ID" format(uuid)
+// @Security BearerAuth
+// @Router /lists/{id}/members [get]
+func (h *ListHandler) GetMembers(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return responses.BadRequest(c, "Invalid list ID format")
+	}
+
+	members, err := h.listService.GetMembers(c.Context(), id, userID)
+	if err != nil {
+		return listErrorResponse(c, "Failed to get list members", err)
+	}
+
+	return responses.OK(c, "List members retrieved successfully", members)
+}
+
+// UpdateMember changes the role of a list member
+// @Summary Update list member role
+// @Description Change the role of a member to editor or viewer. Only the owner can change roles
+// @Tags Lists
+// @Accept json
+// @Produce json
+// @Param id path string true "List ID" format(uuid)
+// @Param userId path string true "Member user ID" format(uuid)
+// @Param member body models.UpdateListMemberRequest true "Member role"
+// @Security BearerAuth
+// @Router /lists/{id}/members/{userId} [put]
+func (h *ListHandler) UpdateMember(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return responses.BadRequest(c, "Invalid list ID format")
+	}
+
+	memberID, err := uuid.Parse(c.Params("userId"))
+	if err != nil {
+		return responses.BadRequest(c, "Invalid user ID format")
+	}
+
+	body := c.Body()
+	var req models.UpdateListMemberRequest
+	if err := middlewares.RequestValidation(&body, &req)(c); err != nil {
+		return responses.BadRequestWithError(c, "Invalid request body", err)
+	}
+
+	if err := h.listService.UpdateMemberRole(c.Context(), id, memberID, userID, req.Role); err != nil {
+		return listErrorResponse(c, "Failed to update member", err)
+	}
+
+	return responses.OK(c, "Member updated successfully", nil)
+}
+
+// RemoveMember removes a member from a list
+// @Summary Remove list member
+// @Description Remove a member from a list. The owner can remove any other member and members can remove themselves to leave the list
+// @Tags Lists
+// @Accept json
+// @Produce json
+// @Param id path string true "List ID" format(uuid)
+// @Param userId path string true "Member user ID" format(uuid)
+// @Security BearerAuth
+// @Router /lists/{id}/members/{userId} [delete]
+func (h *ListHandler) RemoveMember(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return responses.BadRequest(c, "Invalid list ID format")
+	}
+
+	memberID, err := uuid.Parse(c.Params("userId"))
+	if err != nil {
+		return responses.BadRequest(c, "Invalid user ID format")
+	}
+
+	if err := h.listService.RemoveMember(c.Context(), id, memberID, userID); err != nil {
+		return listErrorResponse(c, "Failed to remove member", err)
+	}
+
+	return responses.OK(c, "Member removed successfully", nil)
+}
+
+// GetInvitations lists the pending invitations of a list
+// @Summary Get list invitations
+// @Description Retrieve the pending invitations of a list. Only the owner can see invitations
+// @Tags Lists
+// @Accept json
+// @Produce json
+// @Param id path string true "List ID" format(uuid)
+// @Security BearerAuth
+// @Router /lists/{id}/invitations [get]
+func (h *ListHandler) GetInvitations(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return responses.BadRequest(c, "Invalid list ID format")
+	}
+
+	invitations, err := h.listService.GetInvitations(c.Context(), id, userID)
+	if err != nil {
+		return listErrorResponse(c, "Failed to get invitations", err)
+	}
+
+	return responses.OK(c, "Invitations retrieved successfully", invitations)
+}
+
+// InviteMember invites a user to a list by email
+// @Summary Invite list member
+// @Description Email an invitation to join the list as editor or viewer. The invitation can only be accepted by a user signed in with that email address. Only the owner can invite
+// @Tags Lists
+// @Accept json
+// @Produce json
+// @Param id path string true "List ID" format(uuid)
+// @Param invitation body models.InviteListMemberRequest true "Invitation data"
+// @Security BearerAuth
+// @Router /lists/{id}/invitations [post]
+func (h *ListHandler) InviteMember(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return responses.BadRequest(c, "Invalid list ID format")
+	}
+
+	body := c.Body()
+	var req models.InviteListMemberRequest
+	if err := middlewares.RequestValidation(&body, &req)(c); err != nil {
+		return responses.BadRequestWithError(c, "Invalid request body", err)
+	}
+
+	invitation, err := h.listService.InviteMember(c.Context(), id, userID, &req)
+	if err != nil {
+		return listErrorResponse(c, "Failed to invite member", err)
+	}
+
+	return responses.Created(c, "Invitation sent successfully", invitation)
+}
+
+// RevokeInvitation revokes a pending invitation
+// @Summary Revoke list invitation
+// @Description Revoke a pending invitation of a list. Only the owner can revoke invitations
+// @Tags Lists
+// @Accept json
+// @Produce json
+// @Param id path string true "List ID" format(uuid)
+// @Param invitationId path string true "Invitation ID" format(uuid)
+// @Security BearerAuth
+// @Router /lists/{id}/invitations/{invitationId} [delete]
+func (h *ListHandler) RevokeInvitation(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return responses.BadRequest(c, "Invalid list ID format")
+	}
+
+	invitationID, err := uuid.Parse(c.Params("invitationId"))
+	if err != nil {
+		return responses.BadRequest(c, "Invalid invitation ID format")
+	}
+
+	if err := h.listService.RevokeInvitation(c.Context(), id, invitationID, userID); err != nil {
+		return listErrorResponse(c, "Failed to revoke invitation", err)
+	}
+
+	return responses.OK(c, "Invitation revoked successfully", nil)
+}
+
+// GetInvitation shows an invitation from its emailed token
+// @Summary Get invitation
+// @Description Retrieve the list, role and expiry of an invitation addressed to the currently authenticated user
+// @Tags Lists
+// @Accept json
+// @Produce json
+// @Param token path string true "Invitation token from the email"
+// @Security BearerAuth
+// @Router /lists/invitations/{token} [get]
+func (h *ListHandler) GetInvitation(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	invitation, err := h.listService.GetInvitation(c.Context(), c.Params("token"), userID)
+	if err != nil {
+		return responses.BadRequestWithError(c, "Invalid invitation", err)
+	}
+
+	return responses.OK(c, "Invitation retrieved successfully", invitation)
+}
+
+// AcceptInvitation accepts an invitation from its emailed token
+// @Summary Accept invitation
+// @Description Join the list of an invitation addressed to the currently authenticated user
+// @Tags Lists
+// @Accept json
+// @Produce json
+// @Param token path string true "Invitation token from the email"
+// @Security BearerAuth
+// @Router /lists/invitations/{token}/accept [post]
+func (h *ListHandler) AcceptInvitation(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	list, err := h.listService.AcceptInvitation(c.Context(), c.Params("token"), userID)
+	if err != nil {
+		return responses.BadRequestWithError(c, "Failed to accept invitation", err)
+	}
+
+	return responses.OK(c, "Invitation accepted successfully", list)
+}
+
+// listErrorResponse maps list access errors to 404 and 403 responses and anything else to a 400 response
+func listErrorResponse(c *fiber.Ctx, message string, err error) error {
+	switch {
+	case errors.Is(err, service.ErrListNotFound):
+		return responses.NotFound(c, "List not found")
+	case errors.Is(err, service.ErrListForbidden):
+		return responses.Forbidden(c, "You are not allowed to perform this action on the list")
+	default:
+		return responses.BadRequestWithError(c, message, err)
+	}
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"errors"
+
+	"go-backend-todo/internal/api/middlewares"
+	"go-backend-todo/internal/api/responses"
+	"go-backend-todo/internal/models"
+	"go-backend-todo/internal/service"
+	"go-backend-todo/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PreferenceHandler handles user preference HTTP requests
+type PreferenceHandler struct {
+	preferenceService service.PreferenceService
+}
+
+// NewPreferenceHandler creates a new instance of preference handler
+func NewPreferenceHandler(preferenceService service.PreferenceService) *PreferenceHandler {
+	return &PreferenceHandler{
+		preferenceService: preferenceService,
+	}
+}
+
+// GetPreferences gets current user's preferences
+// @Summary Get user preferences
+// @Description Retrieve the time zone, locale, week start, default todo sort and email notification settings of the currently authenticated user
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Router /users/preferences [get]
+func (h *PreferenceHandler) GetPreferences(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	prefs, err := h.preferenceService.GetPreferences(c.Context(), userID)
+	if err != nil {
+		return responses.InternalServerErrorWithError(c, "Failed to get preferences", err)
+	}
+
+	return responses.OK(c, "Preferences retrieved successfully", prefs)
+}
+
+// UpdatePreferences replaces current user's preferences
+// @Summary Update user preferences
+// @Description Replace the preferences of the currently authenticated user. The time zone must be an IANA name and the locale a BCP 47 tag
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param preferences body models.UpdatePreferencesRequest true "User preferences"
+// @Security BearerAuth
+// @Router /users/preferences [put]
+func (h *PreferenceHandler) UpdatePreferences(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	body := c.Body()
+	var req models.UpdatePreferencesRequest
+	if err := middlewares.RequestValidation(&body, &req)(c); err != nil {
+		return responses.BadRequestWithError(c, "Invalid request body", err)
+	}
+
+	prefs, err := h.preferenceService.UpdatePreferences(c.Context(), userID, &req)
+	if errors.Is(err, utils.ErrBadInput) {
+		return responses.BadRequestWithError(c, "Failed to update preferences", err)
+	}
+	if err != nil {
+		return responses.InternalServerErrorWithError(c, "Failed to update preferences", err)
+	}
+
+	return responses.OK(c, "Preferences updated successfully", prefs)
+}
+
+// GetDigestSettings gets current user's digest subscription
+// @Summary Get daily digest settings
+// @Description Retrieve the daily digest subscription of the currently authenticated user, a subset of the preferences
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Router /users/digest [get]
+func (h *PreferenceHandler) GetDigestSettings(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	settings, err := h.preferenceService.GetDigestSettings(c.Context(), userID)
+	if err != nil {
+		return responses.InternalServerErrorWithError(c, "Failed to get digest settings", err)
+	}
+
+	return responses.OK(c, "Digest settings retrieved successfully", settings)
+}
+
+// UpdateDigestSettings updates current user's digest subscription
+// @Summary Update daily digest settings
+// @Description Opt in or out of the daily digest email and choose the local hour and time zone it is sent at. Other preferences are kept
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param settings body models.UpdateDigestSettingsRequest true "Digest settings"
+// @Security BearerAuth
+// @Router /users/digest [put]
+func (h *PreferenceHandler) UpdateDigestSettings(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	body := c.Body()
+	var req models.UpdateDigestSettingsRequest
+	if err := middlewares.RequestValidation(&body, &req)(c); err != nil {
+		return responses.BadRequestWithError(c, "Invalid request body", err)
+	}
+
+	settings, err := h.preferenceService.UpdateDigestSettings(c.Context(), userID, &req)
+	if errors.Is(err, utils.ErrBadInput) {
+		return responses.BadRequestWithError(c, "Failed to update digest settings", err)
+	}
+	if err != nil {
+		return responses.InternalServerErrorWithError(c, "Failed to update digest settings", err)
+	}
+
+	return responses.OK(c, "Digest settings updated successfully", settings)
+}
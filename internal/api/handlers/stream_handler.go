@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"go-backend-todo/internal/api/middlewares"
+	"go-backend-todo/internal/api/responses"
+	"go-backend-todo/internal/config"
+	"go-backend-todo/internal/realtime"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// streamRetryMillis is the reconnection delay suggested to Server-Sent Events clients
+	streamRetryMillis = 3000
+	// defaultHeartbeat replaces a heartbeat interval that is not positive, which time.NewTicker rejects
+	defaultHeartbeat = 15 * time.Second
+)
+
+// StreamHandler handles real-time event stream HTTP requests
+type StreamHandler struct {
+	hub       *realtime.Hub
+	heartbeat time.Duration
+}
+
+// NewStreamHandler creates a new instance of stream handler
+func NewStreamHandler(hub *realtime.Hub, cfg *config.Config) *StreamHandler {
+	heartbeat := time.Duration(cfg.Stream.HeartbeatInterval) * time.Second
+	if heartbeat <= 0 {
+		log.Printf("Invalid STREAM_HEARTBEAT_INTERVAL_SECONDS %d, using %s", cfg.Stream.HeartbeatInterval, defaultHeartbeat)
+		heartbeat = defaultHeartbeat
+	}
+
+	return &StreamHandler{
+		hub:       hub,
+		heartbeat: heartbeat,
+	}
+}
+
+// Stream pushes current user's todo events as Server-Sent Events
+// @Summary Stream todo events
+// @Description Open a Server-Sent Events stream of the todo.created, todo.updated, todo.completed, todo.deleted and todo.restored events of the currently authenticated user, including changes made from other devices. Browsers may pass the access token as the access_token query parameter. A comment line is sent periodically as a heartbeat; when the stream closes, clients should reconnect and reload their todos
+// @Tags Stream
+// @Produce text/event-stream
+// @Param access_token query string false "Access token, for clients that cannot set the Authorization header"
+// @Security BearerAuth
+// @Router /stream [get]
+func (h *StreamHandler) Stream(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	sub := h.hub.Subscribe(userID)
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer sub.Close()
+
+		ticker := time.NewTicker(h.heartbeat)
+		defer ticker.Stop()
+
+		fmt.Fprintf(w, "retry: %d\n\n", streamRetryMillis)
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		for {
+			select {
+			case event, ok := <-sub.Events():
+				if !ok {
+					return
+				}
+				data, err := json.Marshal(event)
+				if err != nil {
+					continue
+				}
+				fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+			case <-ticker.C:
+				fmt.Fprint(w, ": heartbeat\n\n")
+			}
+
+			// A failed flush means the client went away
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+
+	return nil
+}
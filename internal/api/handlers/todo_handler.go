@@ -1,10 +1,17 @@
 package handlers
 
 import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
 	"strconv"
+	"time"
 
 	"go-backend-todo/internal/api/middlewares"
 	"go-backend-todo/internal/api/responses"
+	"go-backend-todo/internal/config"
 	"go-backend-todo/internal/models"
 	"go-backend-todo/internal/service"
 
@@ -14,25 +21,30 @@ import (
 
 // TodoHandler struct contain dependencies
 type TodoHandler struct {
-	todoService service.TodoService
+	todoService   service.TodoService
+	maxImportSize int
 }
 
 // NewTodoHandler create a new instance of todo handler
-func NewTodoHandler(todoService service.TodoService) *TodoHandler {
+func NewTodoHandler(todoService service.TodoService, cfg *config.Config) *TodoHandler {
 	return &TodoHandler{
-		todoService: todoService,
+		todoService:   todoService,
+		maxImportSize: cfg.Import.MaxFileSize,
 	}
 }
 
 // GetTodos lấy danh sách todos với pagination và filter
 // @Summary Get user's todos with pagination and filters
-// @Description Retrieve a paginated list of todos for the authenticated user with optional filters
+// @Description Retrieve a paginated list of the authenticated user's personal todos and the todos of shared lists they are a member of, with optional filters
 // @Tags Todos
 // @Accept json
 // @Produce json
 // @Param limit query int false "Number of items per page (default: 10)" minimum(1) maximum(100)
 // @Param offset query int false "Number of items to skip (default: 0)" minimum(0)
+// @Param list_id query string false "Only return the todos of this shared list" format(uuid)
+// @Param assignee query string false "Only return the todos assigned to this user ID, or to the authenticated user with \"me\""
 // @Param completed query bool false "Filter by completion status"
+// @Param sort query string false "Sort order (defaults to the user's preference)" Enums(created_at_desc, created_at_asc, deadline_asc, deadline_desc, title_asc)
 // @Security BearerAuth
 // @Success 200 {object} map[string]interface{} "Paginated list of todos"
 // @Failure 400 {object} map[string]string "Invalid query parameters"
@@ -50,6 +62,9 @@ func (h *TodoHandler) GetTodos(c *fiber.Ctx) error {
 	limitStr := c.Query("limit", "10")
 	offsetStr := c.Query("offset", "0")
 	completedStr := c.Query("completed")
+	listIDStr := c.Query("list_id")
+	assigneeStr := c.Query("assignee")
+	sort := models.TodoSortOrder(c.Query("sort"))
 
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil || limit < 1 || limit > 100 {
@@ -70,7 +85,36 @@ func (h *TodoHandler) GetTodos(c *fiber.Ctx) error {
 		completed = &completedBool
 	}
 
-	todos, total, err := h.todoService.GetTodosWithPagination(c.Context(), userID, limit, offset, completed)
+	var listID *uuid.UUID
+	if listIDStr != "" {
+		parsed, err := uuid.Parse(listIDStr)
+		if err != nil {
+			return responses.BadRequest(c, "Invalid list_id parameter")
+		}
+		listID = &parsed
+	}
+
+	var assigneeID *uuid.UUID
+	switch assigneeStr {
+	case "":
+	case "me":
+		assigneeID = &userID
+	default:
+		parsed, err := uuid.Parse(assigneeStr)
+		if err != nil {
+			return responses.BadRequest(c, "Invalid assignee parameter")
+		}
+		assigneeID = &parsed
+	}
+
+	if sort != "" && !sort.IsValid() {
+		return responses.BadRequest(c, "Invalid sort parameter")
+	}
+
+	todos, total, err := h.todoService.GetTodosWithPagination(c.Context(), userID, limit, offset, listID, assigneeID, completed, sort)
+	if errors.Is(err, service.ErrListNotFound) {
+		return responses.NotFound(c, "List not found")
+	}
 	if err != nil {
 		return responses.InternalServerErrorWithError(c, "Failed to get todos", err)
 	}
@@ -110,6 +154,12 @@ func (h *TodoHandler) CreateTodo(c *fiber.Ctx) error {
 	}
 
 	todo, err := h.todoService.CreateTodo(c.Context(), req, userID)
+	if errors.Is(err, service.ErrListForbidden) {
+		return responses.Forbidden(c, "You are not allowed to create todos in this list")
+	}
+	if errors.Is(err, service.ErrInvalidAssignee) {
+		return responses.BadRequestWithError(c, "Invalid assignee", err)
+	}
 	if err != nil {
 		return responses.InternalServerErrorWithError(c, "Failed to create todo", err)
 	}
@@ -181,6 +231,9 @@ func (h *TodoHandler) UpdateTodo(c *fiber.Ctx) error {
 	}
 
 	todo, err := h.todoService.UpdateTodo(c.Context(), id, req, userID)
+	if errors.Is(err, service.ErrListForbidden) {
+		return responses.Forbidden(c, "You are not allowed to edit this todo")
+	}
 	if err != nil {
 		return responses.InternalServerErrorWithError(c, "Failed to update todo", err)
 	}
@@ -190,12 +243,13 @@ func (h *TodoHandler) UpdateTodo(c *fiber.Ctx) error {
 
 // DeleteTodo xóa todo
 // @Summary Delete todo
-// @Description Permanently delete a todo item
+// @Description Move a todo item to the trash. It can be restored until the trash is purged, or undone with the returned undo token within the undo window
 // @Tags Todos
 // @Accept json
 // @Produce json
 // @Param id path string true "Todo ID" format(uuid)
 // @Security BearerAuth
+// @Success 200 {object} models.UndoToken "Todo deleted, with the token to undo it"
 // @Router /todos/{id} [delete]
 func (h *TodoHandler) DeleteTodo(c *fiber.Ctx) error {
 	// Lấy userID từ JWT token
@@ -210,12 +264,494 @@ func (h *TodoHandler) DeleteTodo(c *fiber.Ctx) error {
 		return responses.BadRequest(c, "Invalid todo ID format")
 	}
 
-	err = h.todoService.DeleteTodo(c.Context(), id, userID)
+	undo, err := h.todoService.DeleteTodo(c.Context(), id, userID)
+	if errors.Is(err, service.ErrListForbidden) {
+		return responses.Forbidden(c, "You are not allowed to delete this todo")
+	}
 	if err != nil {
 		return responses.InternalServerErrorWithError(c, "Failed to delete todo", err)
 	}
 
-	return responses.OK(c, "Todo deleted successfully", nil)
+	return responses.OK(c, "Todo deleted successfully", undo)
+}
+
+// CompleteTodos marks several todos as completed
+// @Summary Complete todos
+// @Description Mark several todo items as completed at once. The returned undo token reopens the todos this request completed within the undo window
+// @Tags Todos
+// @Accept json
+// @Produce json
+// @Param request body models.BulkCompleteRequest true "IDs of the todos to complete"
+// @Security BearerAuth
+// @Success 200 {object} models.UndoToken "Todos completed, with the token to undo it (null when no todo changed)"
+// @Failure 400 {object} map[string]string "Invalid request data"
+// @Failure 401 {object} map[string]string "Unauthorized - missing or invalid token"
+// @Failure 403 {object} map[string]string "Not allowed to edit one of the todos"
+// @Router /todos/complete [post]
+func (h *TodoHandler) CompleteTodos(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	var req models.BulkCompleteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return responses.BadRequest(c, "Invalid request body format")
+	}
+
+	if err := middlewares.ValidateStruct(&req); err != nil {
+		return responses.BadRequestWithError(c, "Validation failed", err)
+	}
+
+	undo, err := h.todoService.MarkTodosAsCompleted(c.Context(), req.IDs, userID)
+	if errors.Is(err, service.ErrListForbidden) {
+		return responses.Forbidden(c, "You are not allowed to complete these todos")
+	}
+	if err != nil {
+		return responses.BadRequestWithError(c, "Failed to complete todos", err)
+	}
+
+	return responses.OK(c, "Todos completed successfully", undo)
+}
+
+// DeleteCompletedTodos moves all completed personal todos to the trash
+// @Summary Delete completed todos
+// @Description Move all completed personal todos of the authenticated user to the trash. The returned undo token restores them within the undo window
+// @Tags Todos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.UndoToken "Completed todos deleted, with the token to undo it (null when there were none)"
+// @Failure 401 {object} map[string]string "Unauthorized - missing or invalid token"
+// @Router /todos/completed [delete]
+func (h *TodoHandler) DeleteCompletedTodos(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	undo, err := h.todoService.DeleteCompletedTodos(c.Context(), userID)
+	if err != nil {
+		return responses.InternalServerErrorWithError(c, "Failed to delete completed todos", err)
+	}
+
+	return responses.OK(c, "Completed todos deleted successfully", undo)
+}
+
+// UndoTodoAction reverses a destructive todo action
+// @Summary Undo todo action
+// @Description Reverse a delete, bulk complete or delete completed action using the undo token it returned. Tokens are single use and expire after the undo window. Todos changed since the action, or that the user can no longer edit, are left as they are
+// @Tags Todos
+// @Accept json
+// @Produce json
+// @Param request body models.UndoRequest true "Undo token"
+// @Security BearerAuth
+// @Success 200 {object} models.UndoResult "Todos brought back to their prior state"
+// @Failure 400 {object} map[string]string "Invalid request data"
+// @Failure 401 {object} map[string]string "Unauthorized - missing or invalid token"
+// @Failure 404 {object} map[string]string "Undo token invalid or expired"
+// @Router /todos/undo [post]
+func (h *TodoHandler) UndoTodoAction(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	var req models.UndoRequest
+	if err := c.BodyParser(&req); err != nil {
+		return responses.BadRequest(c, "Invalid request body format")
+	}
+
+	if err := middlewares.ValidateStruct(&req); err != nil {
+		return responses.BadRequestWithError(c, "Validation failed", err)
+	}
+
+	result, err := h.todoService.Undo(c.Context(), req.UndoToken, userID)
+	if errors.Is(err, service.ErrUndoTokenInvalid) {
+		return responses.NotFound(c, "Undo token is invalid or expired")
+	}
+	if err != nil {
+		return responses.InternalServerErrorWithError(c, "Failed to undo action", err)
+	}
+
+	return responses.OK(c, "Action undone successfully", result)
+}
+
+// ExportTodos streams all todos of the user as a file download
+// @Summary Export todos
+// @Description Download all todos visible to the authenticated user as JSON, CSV or iCalendar. The iCalendar export contains one VTODO per todo with its DUE date and STATUS and can be opened in calendar apps. CSV cells starting with =, +, - or @ are prefixed with a quote so spreadsheets do not run them as formulas. Todos are streamed as they are read, so large exports do not need to fit in memory
+// @Tags Todos
+// @Produce json
+// @Produce text/csv
+// @Produce text/calendar
+// @Param format query string false "Export format (default: json)" Enums(json, csv, ics)
+// @Security BearerAuth
+// @Success 200 {file} file "Exported todos"
+// @Failure 400 {object} map[string]string "Invalid format"
+// @Failure 401 {object} map[string]string "Unauthorized - missing or invalid token"
+// @Router /todos/export [get]
+func (h *TodoHandler) ExportTodos(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	format := models.ExportFormat(c.Query("format", string(models.ExportFormatJSON)))
+	if !format.IsValid() {
+		return responses.BadRequest(c, "Invalid format. Use 'json', 'csv' or 'ics'")
+	}
+
+	filename := fmt.Sprintf("todos-%s.%s", time.Now().UTC().Format("20060102"), format)
+	c.Set(fiber.HeaderContentType, format.ContentType())
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	// The status line is sent before the first row is read, so failures past this point can only be logged
+	ctx := c.Context()
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := h.todoService.ExportTodos(ctx, userID, format, w); err != nil {
+			log.Printf("Failed to export todos of user %s: %v", userID, err)
+			return
+		}
+		if err := w.Flush(); err != nil {
+			log.Printf("Failed to export todos of user %s: %v", userID, err)
+		}
+	})
+
+	return nil
+}
+
+// ImportTodos imports todos from an uploaded file
+// @Summary Import todos
+// @Description Import personal todos from our JSON or CSV export, any CSV file with a column mapping, a Todoist CSV export or Microsoft To Do tasks in the JSON of the Microsoft Graph API (msgraph), since To Do has no file export of its own. Every row is validated first and nothing is imported unless all rows are valid, in which case they are inserted in a single transaction. Use dry_run to only get the validation report. Deadlines without a UTC offset are read in the user's time zone and dates without a time are due at the end of that day. Todoist dates written in words, such as "every monday", are ignored with a warning and the default deadline is used. Imported todos get no reminders
+// @Tags Todos
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "File to import"
+// @Param format formData string false "Import format (default: json)" Enums(json, csv, todoist, msgraph)
+// @Param mapping formData string false "csv format only: JSON object mapping the fields title, deadline and completed to CSV column names. Defaults to the columns of our CSV export"
+// @Param default_deadline formData string false "Deadline (RFC 3339) of rows that have none, which are rejected otherwise"
+// @Param dry_run formData bool false "Only validate the file without importing (default: false)"
+// @Security BearerAuth
+// @Success 200 {object} models.ImportResult "Import or dry run report"
+// @Failure 400 {object} map[string]string "Invalid file or parameters"
+// @Failure 401 {object} map[string]string "Unauthorized - missing or invalid token"
+// @Failure 422 {object} map[string]interface{} "Some rows are invalid and nothing was imported, details holds the import report"
+// @Router /todos/import [post]
+func (h *TodoHandler) ImportTodos(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	opts := models.ImportOptions{
+		Format: models.ImportFormat(c.FormValue("format", string(models.ImportFormatJSON))),
+	}
+	if !opts.Format.IsValid() {
+		return responses.BadRequest(c, "Invalid format. Use 'json', 'csv', 'todoist' or 'msgraph'")
+	}
+
+	if mapping := c.FormValue("mapping"); mapping != "" {
+		if err := json.Unmarshal([]byte(mapping), &opts.Mapping); err != nil {
+			return responses.BadRequestWithError(c, "Invalid mapping, expected a JSON object of field to column name", err)
+		}
+	}
+
+	if value := c.FormValue("default_deadline"); value != "" {
+		deadline, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return responses.BadRequest(c, "Invalid default_deadline format. Use RFC 3339")
+		}
+		opts.DefaultDeadline = &deadline
+	}
+
+	if value := c.FormValue("dry_run"); value != "" {
+		opts.DryRun, err = strconv.ParseBool(value)
+		if err != nil {
+			return responses.BadRequest(c, "Invalid dry_run parameter")
+		}
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return responses.BadRequest(c, "A file to import is required")
+	}
+	if fileHeader.Size > int64(h.maxImportSize) {
+		return responses.BadRequest(c, fmt.Sprintf("File is larger than %d bytes", h.maxImportSize))
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return responses.InternalServerErrorWithError(c, "Failed to read file", err)
+	}
+	defer file.Close()
+
+	result, err := h.todoService.ImportTodos(c.Context(), userID, file, opts)
+	if errors.Is(err, service.ErrInvalidImport) {
+		return responses.BadRequestWithError(c, "Invalid import file", err)
+	}
+	if err != nil {
+		return responses.InternalServerErrorWithError(c, "Failed to import todos", err)
+	}
+
+	if len(result.Errors) > 0 && !result.DryRun {
+		return responses.UnprocessableEntity(c, "Some rows are invalid, nothing was imported", result)
+	}
+	if result.DryRun {
+		return responses.OK(c, "Import validated successfully", result)
+	}
+	return responses.OK(c, "Todos imported successfully", result)
+}
+
+// GetTrash lists the deleted todos that can still be restored
+// @Summary Get trash
+// @Description Retrieve a paginated list of deleted todos that can still be restored, most recently deleted first. Todos are purged permanently after the configured retention period
+// @Tags Todos
+// @Accept json
+// @Produce json
+// @Param limit query int false "Number of items per page (default: 10)" minimum(1) maximum(100)
+// @Param offset query int false "Number of items to skip (default: 0)" minimum(0)
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Paginated deleted todos"
+// @Failure 400 {object} map[string]string "Invalid query parameters"
+// @Failure 401 {object} map[string]string "Unauthorized - missing or invalid token"
+// @Router /todos/trash [get]
+func (h *TodoHandler) GetTrash(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		return responses.BadRequest(c, "Invalid limit parameter (1-100)")
+	}
+
+	offset, err := strconv.Atoi(c.Query("offset", "0"))
+	if err != nil || offset < 0 {
+		return responses.BadRequest(c, "Invalid offset parameter")
+	}
+
+	todos, total, err := h.todoService.GetTrash(c.Context(), userID, limit, offset)
+	if err != nil {
+		return responses.InternalServerErrorWithError(c, "Failed to get trash", err)
+	}
+
+	page := (offset / limit) + 1
+	return responses.OKWithPagination(c, "Trash retrieved successfully", todos, page, limit, total)
+}
+
+// RestoreTodo moves a todo out of the trash
+// @Summary Restore todo
+// @Description Restore a deleted todo item from the trash. Reminders whose time has not passed yet are scheduled again
+// @Tags Todos
+// @Accept json
+// @Produce json
+// @Param id path string true "Todo ID" format(uuid)
+// @Security BearerAuth
+// @Success 200 {object} models.Todo "Restored todo"
+// @Failure 400 {object} map[string]string "Invalid todo ID"
+// @Failure 401 {object} map[string]string "Unauthorized - missing or invalid token"
+// @Failure 403 {object} map[string]string "Not allowed to restore this todo"
+// @Failure 404 {object} map[string]string "Todo not found in the trash"
+// @Router /todos/{id}/restore [post]
+func (h *TodoHandler) RestoreTodo(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	idStr := c.Params("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return responses.BadRequest(c, "Invalid todo ID format")
+	}
+
+	todo, err := h.todoService.RestoreTodo(c.Context(), id, userID)
+	if errors.Is(err, service.ErrTodoNotFound) {
+		return responses.NotFound(c, "Todo not found in the trash")
+	}
+	if errors.Is(err, service.ErrListForbidden) {
+		return responses.Forbidden(c, "You are not allowed to restore this todo")
+	}
+	if err != nil {
+		return responses.InternalServerErrorWithError(c, "Failed to restore todo", err)
+	}
+
+	return responses.OK(c, "Todo restored successfully", todo)
+}
+
+// GetTodoReminders lists the reminders of a todo
+// @Summary Get todo reminders
+// @Description Retrieve the deadline reminders scheduled for a todo item
+// @Tags Todos
+// @Accept json
+// @Produce json
+// @Param id path string true "Todo ID" format(uuid)
+// @Security BearerAuth
+// @Router /todos/{id}/reminders [get]
+func (h *TodoHandler) GetTodoReminders(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	idStr := c.Params("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return responses.BadRequest(c, "Invalid todo ID format")
+	}
+
+	reminders, err := h.todoService.GetTodoReminders(c.Context(), id, userID)
+	if err != nil {
+		return responses.NotFound(c, "Todo not found")
+	}
+
+	return responses.OK(c, "Reminders retrieved successfully", reminders)
+}
+
+// UpdateTodoReminders replaces the reminders of a todo
+// @Summary Update todo reminders
+// @Description Replace the reminder offsets (minutes before the deadline) of a todo item. An empty list removes all reminders
+// @Tags Todos
+// @Accept json
+// @Produce json
+// @Param id path string true "Todo ID" format(uuid)
+// @Param reminders body models.UpdateRemindersRequest true "Reminder offsets in minutes"
+// @Security BearerAuth
+// @Router /todos/{id}/reminders [put]
+func (h *TodoHandler) UpdateTodoReminders(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	idStr := c.Params("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return responses.BadRequest(c, "Invalid todo ID format")
+	}
+
+	var req models.UpdateRemindersRequest
+	if err := c.BodyParser(&req); err != nil {
+		return responses.BadRequest(c, "Invalid request body format")
+	}
+
+	// Validate request
+	if err := middlewares.ValidateStruct(&req); err != nil {
+		return responses.BadRequestWithError(c, "Validation failed", err)
+	}
+
+	reminders, err := h.todoService.UpdateTodoReminders(c.Context(), id, req.Offsets, userID)
+	if errors.Is(err, service.ErrTodoNotFound) {
+		return responses.NotFound(c, "Todo not found")
+	}
+	if errors.Is(err, service.ErrListForbidden) {
+		return responses.Forbidden(c, "You are not allowed to edit the reminders of this todo")
+	}
+	if err != nil {
+		return responses.InternalServerErrorWithError(c, "Failed to update reminders", err)
+	}
+
+	return responses.OK(c, "Reminders updated successfully", reminders)
+}
+
+// AssignTodo assigns a todo to a user
+// @Summary Assign a todo
+// @Description Assign a todo to a user who can edit it and notify them by email. A null assignee_id unassigns the todo
+// @Tags Todos
+// @Accept json
+// @Produce json
+// @Param id path string true "Todo ID" format(uuid)
+// @Param assignee body models.AssignTodoRequest true "Assignee"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Todo assigned successfully"
+// @Failure 400 {object} map[string]string "Invalid request data or assignee"
+// @Failure 401 {object} map[string]string "Unauthorized - missing or invalid token"
+// @Failure 403 {object} map[string]string "Not allowed to edit the todo"
+// @Failure 404 {object} map[string]string "Todo not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /todos/{id}/assignee [put]
+func (h *TodoHandler) AssignTodo(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	idStr := c.Params("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return responses.BadRequest(c, "Invalid todo ID format")
+	}
+
+	var req models.AssignTodoRequest
+	if err := c.BodyParser(&req); err != nil {
+		return responses.BadRequest(c, "Invalid request body format")
+	}
+
+	todo, err := h.todoService.AssignTodo(c.Context(), id, req.AssigneeID, userID)
+	if errors.Is(err, service.ErrTodoNotFound) {
+		return responses.NotFound(c, "Todo not found")
+	}
+	if errors.Is(err, service.ErrListForbidden) {
+		return responses.Forbidden(c, "You are not allowed to assign this todo")
+	}
+	if errors.Is(err, service.ErrInvalidAssignee) {
+		return responses.BadRequestWithError(c, "Invalid assignee", err)
+	}
+	if err != nil {
+		return responses.InternalServerErrorWithError(c, "Failed to assign todo", err)
+	}
+
+	return responses.OK(c, "Todo assigned successfully", todo)
+}
+
+// GetTodoHistory gets the change history of a todo
+// @Summary Get todo history
+// @Description Retrieve the append-only history of a todo, newest first: who created, changed or deleted it and when, with the old and new value of every changed field. The history of a todo in the trash can still be read
+// @Tags Todos
+// @Accept json
+// @Produce json
+// @Param id path string true "Todo ID" format(uuid)
+// @Param limit query int false "Number of items per page (default: 20)" minimum(1) maximum(100)
+// @Param offset query int false "Number of items to skip (default: 0)" minimum(0)
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Paginated todo history"
+// @Failure 400 {object} map[string]string "Invalid query parameters"
+// @Failure 401 {object} map[string]string "Unauthorized - missing or invalid token"
+// @Failure 404 {object} map[string]string "Todo not found"
+// @Router /todos/{id}/history [get]
+func (h *TodoHandler) GetTodoHistory(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	idStr := c.Params("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return responses.BadRequest(c, "Invalid todo ID format")
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		return responses.BadRequest(c, "Invalid limit parameter (1-100)")
+	}
+
+	offset, err := strconv.Atoi(c.Query("offset", "0"))
+	if err != nil || offset < 0 {
+		return responses.BadRequest(c, "Invalid offset parameter")
+	}
+
+	events, total, err := h.todoService.GetTodoHistory(c.Context(), id, userID, limit, offset)
+	if errors.Is(err, service.ErrTodoNotFound) {
+		return responses.NotFound(c, "Todo not found")
+	}
+	if err != nil {
+		return responses.InternalServerErrorWithError(c, "Failed to get todo history", err)
+	}
+
+	page := (offset / limit) + 1
+	return responses.OKWithPagination(c, "Todo history retrieved successfully", events, page, limit, total)
 }
 
 // ToggleTodoStatus toggles todo completion status
@@ -295,7 +831,7 @@ func (h *TodoHandler) GetTodosByStatus(c *fiber.Ctx) error {
 		})
 	}
 
-	todos, total, err := h.todoService.GetTodosWithPagination(c.Context(), userID, limit, offset, &completed)
+	todos, total, err := h.todoService.GetTodosWithPagination(c.Context(), userID, limit, offset, nil, nil, &completed, "")
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
@@ -313,26 +849,24 @@ func (h *TodoHandler) GetTodosByStatus(c *fiber.Ctx) error {
 
 // GetTodoStats gets user's todo statistics
 // @Summary Get todo statistics
-// @Description Retrieve statistics about user's todos (total, completed, incomplete)
+// @Description Retrieve statistics about user's todos (total, completed, pending, overdue, due today and due this week)
 // @Tags Todos
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Router /todos/stats [get]
 func (h *TodoHandler) GetTodoStats(c *fiber.Ctx) error {
-	// TODO: Lấy userID từ JWT token hoặc session
-	// userID := uuid.New() // Thay bằng userID thực từ auth
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
 
-	// stats, err := h.todoService.GetTodoStats(c.Context(), userID)
-	// if err != nil {
-	// 	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-	// 		"error": err.Error(),
-	// 	})
-	// }
+	stats, err := h.todoService.GetTodoStats(c.Context(), userID)
+	if err != nil {
+		return responses.InternalServerErrorWithError(c, "Failed to get todo statistics", err)
+	}
 
-	return c.JSON(fiber.Map{
-		"message": "Todo statistics are not implemented yet",
-	})
+	return responses.OK(c, "Todo statistics retrieved successfully", stats)
 }
 
 // Backward compatibility - keep old function signatures for existing routes
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+
+	"go-backend-todo/internal/api/middlewares"
+	"go-backend-todo/internal/api/responses"
+	"go-backend-todo/internal/models"
+	"go-backend-todo/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// TokenHandler handles personal access token HTTP requests
+type TokenHandler struct {
+	tokenService service.TokenService
+}
+
+// NewTokenHandler creates a new instance of token handler
+func NewTokenHandler(tokenService service.TokenService) *TokenHandler {
+	return &TokenHandler{
+		tokenService: tokenService,
+	}
+}
+
+// GetTokens lists current user's personal access tokens
+// @Summary List personal access tokens
+// @Description Retrieve the personal access tokens of the currently authenticated user, with when they were last used. Tokens themselves are never included
+// @Tags Personal Access Tokens
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.PersonalAccessToken "Personal access tokens"
+// @Failure 401 {object} map[string]string "Unauthorized - missing or invalid token"
+// @Router /users/tokens [get]
+func (h *TokenHandler) GetTokens(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	tokens, err := h.tokenService.GetTokens(c.Context(), userID)
+	if err != nil {
+		return responses.InternalServerErrorWithError(c, "Failed to get tokens", err)
+	}
+
+	return responses.OK(c, "Tokens retrieved successfully", tokens)
+}
+
+// CreateToken creates a personal access token
+// @Summary Create personal access token
+// @Description Create a token for clients that cannot log in interactively, such as CalDAV clients, which use it as the password with the username or email. The token is only returned in this response, and changing or resetting the password revokes it
+// @Tags Personal Access Tokens
+// @Accept json
+// @Produce json
+// @Param token body models.CreatePersonalAccessTokenRequest true "Token data"
+// @Security BearerAuth
+// @Success 201 {object} models.PersonalAccessToken "Created token"
+// @Failure 400 {object} map[string]string "Invalid request data"
+// @Failure 401 {object} map[string]string "Unauthorized - missing or invalid token"
+// @Router /users/tokens [post]
+func (h *TokenHandler) CreateToken(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	body := c.Body()
+	var req models.CreatePersonalAccessTokenRequest
+	if err := middlewares.RequestValidation(&body, &req)(c); err != nil {
+		return responses.BadRequestWithError(c, "Invalid request body", err)
+	}
+
+	token, err := h.tokenService.CreateToken(c.Context(), userID, &req)
+	if err != nil {
+		return responses.InternalServerErrorWithError(c, "Failed to create token", err)
+	}
+
+	return responses.Created(c, "Token created successfully", token)
+}
+
+// RevokeToken revokes a personal access token
+// @Summary Revoke personal access token
+// @Description Revoke a personal access token of the currently authenticated user. Clients using it can no longer authenticate
+// @Tags Personal Access Tokens
+// @Accept json
+// @Produce json
+// @Param id path string true "Token ID" format(uuid)
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Token revoked"
+// @Failure 400 {object} map[string]string "Invalid token ID"
+// @Failure 401 {object} map[string]string "Unauthorized - missing or invalid token"
+// @Failure 404 {object} map[string]string "Token not found"
+// @Router /users/tokens/{id} [delete]
+func (h *TokenHandler) RevokeToken(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return responses.BadRequest(c, "Invalid token ID format")
+	}
+
+	err = h.tokenService.RevokeToken(c.Context(), id, userID)
+	if errors.Is(err, service.ErrTokenNotFound) {
+		return responses.NotFound(c, "Token not found")
+	}
+	if err != nil {
+		return responses.InternalServerErrorWithError(c, "Failed to revoke token", err)
+	}
+
+	return responses.OK(c, "Token revoked successfully", nil)
+}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"errors"
+
 	"go-backend-todo/internal/api/middlewares"
 	"go-backend-todo/internal/api/responses"
 	"go-backend-todo/internal/models"
@@ -69,7 +71,7 @@ func (h *UserHandler) UpdateUserProfile(c *fiber.Ctx) error {
 
 // ChangePassword changes user's password
 // @Summary Change user password
-// @Description Change the password for the currently authenticated user
+// @Description Change the password for the currently authenticated user. Personal access tokens are revoked and must be created again
 // @Tags Users
 // @Accept json
 // @Produce json
@@ -96,15 +98,38 @@ func (h *UserHandler) ChangePassword(c *fiber.Ctx) error {
 	return responses.OK(c, "Password changed successfully", nil)
 }
 
-// DeleteUserProfile deletes current user's account
+// DeleteUserProfile schedules the deletion of current user's account
 // @Summary Delete current user account
-// @Description Permanently delete the currently authenticated user's account and all associated data
+// @Description Schedule the permanent deletion of the currently authenticated user's account and all associated data, confirmed with the password. Every session is signed out right away, and logging in again during the grace period cancels the deletion
 // @Tags Users
 // @Accept json
 // @Produce json
+// @Param confirmation body models.DeleteAccountRequest true "Password confirmation"
 // @Security BearerAuth
+// @Success 202 {object} models.AccountDeletion "Deletion scheduled"
+// @Failure 400 {object} map[string]string "Invalid request data"
+// @Failure 401 {object} map[string]string "Unauthorized - missing or invalid token"
+// @Failure 403 {object} map[string]string "Incorrect password"
 // @Router /users/profile [delete]
 func (h *UserHandler) DeleteUserProfile(c *fiber.Ctx) error {
-	// TODO: Implement logic to delete current user's account
-	return responses.OK(c, "User account deleted successfully", nil)
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	body := c.Body()
+	var req models.DeleteAccountRequest
+	if err := middlewares.RequestValidation(&body, &req)(c); err != nil {
+		return responses.BadRequestWithError(c, "Invalid request body", err)
+	}
+
+	deletion, err := h.userService.ScheduleDeletion(c.Context(), userID, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrIncorrectPassword) {
+			return responses.Forbidden(c, "Incorrect password")
+		}
+		return responses.InternalServerErrorWithError(c, "Failed to delete account", err)
+	}
+
+	return responses.Accepted(c, "Account scheduled for deletion, log in again before then to cancel", deletion)
 }
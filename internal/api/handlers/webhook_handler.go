@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"go-backend-todo/internal/api/middlewares"
+	"go-backend-todo/internal/api/responses"
+	"go-backend-todo/internal/models"
+	"go-backend-todo/internal/service"
+	"go-backend-todo/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// WebhookHandler handles webhook HTTP requests
+type WebhookHandler struct {
+	webhookService service.WebhookService
+}
+
+// NewWebhookHandler creates a new instance of webhook handler
+func NewWebhookHandler(webhookService service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// GetWebhooks lists current user's webhooks
+// @Summary List webhooks
+// @Description Retrieve the webhooks registered by the currently authenticated user. Secrets are never included
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Router /users/webhooks [get]
+func (h *WebhookHandler) GetWebhooks(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	webhooks, err := h.webhookService.GetWebhooks(c.Context(), userID)
+	if err != nil {
+		return responses.InternalServerErrorWithError(c, "Failed to get webhooks", err)
+	}
+
+	return responses.OK(c, "Webhooks retrieved successfully", webhooks)
+}
+
+// CreateWebhook registers a webhook
+// @Summary Create webhook
+// @Description Register an endpoint to receive todo events (todo.created, todo.updated, todo.completed, todo.deleted, todo.restored). Deliveries are signed with HMAC-SHA256 over "timestamp.body" in the X-Webhook-Signature header; the secret is only returned in this response
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body models.CreateWebhookRequest true "Webhook data"
+// @Security BearerAuth
+// @Router /users/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	body := c.Body()
+	var req models.CreateWebhookRequest
+	if err := middlewares.RequestValidation(&body, &req)(c); err != nil {
+		return responses.BadRequestWithError(c, "Invalid request body", err)
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(c.Context(), userID, &req)
+	if err != nil {
+		return webhookErrorResponse(c, "Failed to create webhook", err)
+	}
+
+	return responses.Created(c, "Webhook created successfully", webhook)
+}
+
+// GetWebhook gets a webhook by ID
+// @Summary Get webhook by ID
+// @Description Retrieve a webhook registered by the currently authenticated user
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID" format(uuid)
+// @Security BearerAuth
+// @Router /users/webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return responses.BadRequest(c, "Invalid webhook ID format")
+	}
+
+	webhook, err := h.webhookService.GetWebhook(c.Context(), id, userID)
+	if err != nil {
+		return webhookErrorResponse(c, "Failed to get webhook", err)
+	}
+
+	return responses.OK(c, "Webhook retrieved successfully", webhook)
+}
+
+// UpdateWebhook updates a webhook
+// @Summary Update webhook
+// @Description Update the URL, subscribed events, description or active flag of a webhook
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID" format(uuid)
+// @Param webhook body models.UpdateWebhookRequest true "Webhook update data"
+// @Security BearerAuth
+// @Router /users/webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return responses.BadRequest(c, "Invalid webhook ID format")
+	}
+
+	body := c.Body()
+	var req models.UpdateWebhookRequest
+	if err := middlewares.RequestValidation(&body, &req)(c); err != nil {
+		return responses.BadRequestWithError(c, "Invalid request body", err)
+	}
+
+	webhook, err := h.webhookService.UpdateWebhook(c.Context(), id, userID, &req)
+	if err != nil {
+		return webhookErrorResponse(c, "Failed to update webhook", err)
+	}
+
+	return responses.OK(c, "Webhook updated successfully", webhook)
+}
+
+// DeleteWebhook deletes a webhook
+// @Summary Delete webhook
+// @Description Delete a webhook together with its delivery log
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID" format(uuid)
+// @Security BearerAuth
+// @Router /users/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return responses.BadRequest(c, "Invalid webhook ID format")
+	}
+
+	if err := h.webhookService.DeleteWebhook(c.Context(), id, userID); err != nil {
+		return webhookErrorResponse(c, "Failed to delete webhook", err)
+	}
+
+	return responses.OK(c, "Webhook deleted successfully", nil)
+}
+
+// GetWebhookDeliveries lists the delivery log of a webhook
+// @Summary Get webhook deliveries
+// @Description Retrieve the deliveries of a webhook, newest first, including attempts, response status and last error
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID" format(uuid)
+// @Param limit query int false "Number of items per page (default: 20)" minimum(1) maximum(100)
+// @Param offset query int false "Number of items to skip (default: 0)" minimum(0)
+// @Security BearerAuth
+// @Router /users/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) GetWebhookDeliveries(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return responses.BadRequest(c, "Invalid webhook ID format")
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		return responses.BadRequest(c, "Invalid limit parameter (1-100)")
+	}
+
+	offset, err := strconv.Atoi(c.Query("offset", "0"))
+	if err != nil || offset < 0 {
+		return responses.BadRequest(c, "Invalid offset parameter")
+	}
+
+	deliveries, total, err := h.webhookService.GetDeliveries(c.Context(), id, userID, limit, offset)
+	if err != nil {
+		return webhookErrorResponse(c, "Failed to get webhook deliveries", err)
+	}
+
+	page := (offset / limit) + 1
+	return responses.OKWithPagination(c, "Webhook deliveries retrieved successfully", deliveries, page, limit, total)
+}
+
+// SendTestEvent queues a test delivery for a webhook
+// @Summary Send webhook test event
+// @Description Queue a webhook.test event for the webhook regardless of its subscribed events. The result appears in the delivery log
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID" format(uuid)
+// @Security BearerAuth
+// @Router /users/webhooks/{id}/test [post]
+func (h *WebhookHandler) SendTestEvent(c *fiber.Ctx) error {
+	userID, err := middlewares.GetUserIDFromContext(c)
+	if err != nil {
+		return responses.Unauthorized(c, "User not authenticated")
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return responses.BadRequest(c, "Invalid webhook ID format")
+	}
+
+	delivery, err := h.webhookService.SendTestEvent(c.Context(), id, userID)
+	if err != nil {
+		return webhookErrorResponse(c, "Failed to queue test event", err)
+	}
+
+	return responses.Created(c, "Test event queued successfully", delivery)
+}
+
+// webhookErrorResponse maps webhook service errors to HTTP responses
+func webhookErrorResponse(c *fiber.Ctx, message string, err error) error {
+	switch {
+	case errors.Is(err, service.ErrWebhookNotFound):
+		return responses.NotFound(c, "Webhook not found")
+	case errors.Is(err, utils.ErrBadInput):
+		return responses.BadRequestWithError(c, message, err)
+	default:
+		return responses.InternalServerErrorWithError(c, message, err)
+	}
+}
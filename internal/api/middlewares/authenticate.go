@@ -1,6 +1,10 @@
 package middlewares
 
 import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
 	"log"
 	"strings"
 	"time"
@@ -11,6 +15,15 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+// errUnsupportedScheme is returned for Authorization headers that are neither basic nor bearer credentials
+var errUnsupportedScheme = errors.New("unsupported authorization scheme")
+
+// TokenAuthenticator resolves the user of a personal access token or of HTTP basic credentials
+type TokenAuthenticator interface {
+	Authenticate(ctx context.Context, token string) (*models.UserProfile, error)
+	AuthenticateBasic(ctx context.Context, login, secret string, allowPassword bool) (*models.UserProfile, error)
+}
+
 // AuthenticateJWT middleware authenticates JWT token
 func AuthenticateJWT(jwtManager *JWTManager) fiber.Handler {
 	return func(c *fiber.Ctx) error {
@@ -52,6 +65,46 @@ func AuthenticateJWT(jwtManager *JWTManager) fiber.Handler {
 		c.Locals("role", claims.Role)
 		c.Locals("email_validation_status", claims.EmailValidationStatus)
 		c.Locals("claims", claims)
+		pinRecentWriter(c)
+
+		return c.Next()
+	}
+}
+
+// AuthenticateBasicOrToken middleware authenticates clients that cannot log in interactively, such as CalDAV clients.
+// They send HTTP basic credentials, whose password is a personal access token or, when allowPassword is set,
+// the account password, or a personal access token as bearer token. Failures ask the client for basic credentials.
+func AuthenticateBasicOrToken(tokenService TokenAuthenticator, realm string, allowPassword bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var user *models.UserProfile
+		var err error
+
+		scheme, credentials, _ := strings.Cut(c.Get(fiber.HeaderAuthorization), " ")
+		switch {
+		case strings.EqualFold(scheme, "Bearer"):
+			user, err = tokenService.Authenticate(c.Context(), credentials)
+		case strings.EqualFold(scheme, "Basic"):
+			var decoded []byte
+			decoded, err = base64.StdEncoding.DecodeString(credentials)
+			if err == nil {
+				login, secret, _ := strings.Cut(string(decoded), ":")
+				user, err = tokenService.AuthenticateBasic(c.Context(), login, secret, allowPassword)
+			}
+		default:
+			err = errUnsupportedScheme
+		}
+
+		if err != nil {
+			c.Set(fiber.HeaderWWWAuthenticate, fmt.Sprintf(`Basic realm=%q, charset="UTF-8"`, realm))
+			return responses.Unauthorized(c, "Valid credentials or a personal access token are required")
+		}
+
+		c.Locals("user_id", user.UserID.String())
+		c.Locals("username", user.Username)
+		c.Locals("email", user.Email)
+		c.Locals("role", string(user.Role))
+		c.Locals("email_validation_status", string(user.Status))
+		pinRecentWriter(c)
 
 		return c.Next()
 	}
@@ -104,10 +157,33 @@ func OptionalAuth(jwtManager *JWTManager) fiber.Handler {
 		c.Locals("role", claims.Role)
 		c.Locals("email_validation_status", claims.EmailValidationStatus)
 		c.Locals("claims", claims)
+		pinRecentWriter(c)
 
 		return c.Next()
 	}
 }
 
+// TokenFromQuery middleware copies a bearer token from a query parameter into the Authorization header.
+// It is meant for clients such as the browser EventSource API that cannot set request headers.
+func TokenFromQuery(param string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Get("Authorization") == "" {
+			if token := c.Query(param); token != "" {
+				c.Request().Header.Set("Authorization", "Bearer "+token)
+			}
+		}
 
+		return c.Next()
+	}
+}
 
+// ClientInfo middleware stores the IP address and user agent of the client for the audit log
+func ClientInfo() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals(models.ClientInfoKey, models.ClientInfo{
+			IPAddress: c.IP(),
+			UserAgent: strings.Clone(c.Get(fiber.HeaderUserAgent)),
+		})
+		return c.Next()
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"go-backend-todo/internal/config"
+	"go-backend-todo/internal/metrics"
+	"go-backend-todo/internal/models"
 	user_repository "go-backend-todo/internal/repository/user"
 
 	"github.com/gofiber/fiber/v2"
@@ -13,17 +15,24 @@ import (
 	"github.com/google/uuid"
 )
 
+// AuditRecorder records security events in the audit log, logging failures instead of returning them
+type AuditRecorder interface {
+	Record(ctx context.Context, eventType models.AuditEventType, userID *uuid.UUID, email, details string)
+}
+
 // JWTManager handles JWT token operations
 type JWTManager struct {
 	cfg      *config.Config
 	userRepo user_repository.UserRepository
+	audit    AuditRecorder
 }
 
 // NewJWTManager creates a new JWT manager
-func NewJWTManager(cfg *config.Config, userRepo user_repository.UserRepository) *JWTManager {
+func NewJWTManager(cfg *config.Config, userRepo user_repository.UserRepository, audit AuditRecorder) *JWTManager {
 	return &JWTManager{
 		cfg:      cfg,
 		userRepo: userRepo,
+		audit:    audit,
 	}
 }
 
@@ -164,47 +173,65 @@ func (j *JWTManager) ParseRefreshToken(tokenString string) (*RefreshJWTClaims, e
 
 // RefreshAccessToken refreshes the access token, also returning new refresh token
 // This function rotates the refresh token on every refresh
-func (j *JWTManager) RefreshAccessToken(refreshToken string) (string, string, error) {
+func (j *JWTManager) RefreshAccessToken(ctx context.Context, refreshToken string) (string, string, error) {
 	// Parse the refresh token
 	claims, err := j.ParseRefreshToken(refreshToken)
 	if err != nil {
+		j.audit.Record(ctx, models.AuditTokenRefreshFailed, nil, "", "invalid refresh token")
+		metrics.TokenRefreshes.WithLabelValues(metrics.ResultFailure).Inc()
 		return "", "", fiber.NewError(fiber.StatusUnauthorized, "Invalid refresh token: "+err.Error())
 	}
 
 	// Get user ID from claims
-	userID := claims.Subject
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		j.audit.Record(ctx, models.AuditTokenRefreshFailed, nil, "", "invalid refresh token subject")
+		metrics.TokenRefreshes.WithLabelValues(metrics.ResultFailure).Inc()
+		return "", "", fiber.NewError(fiber.StatusUnauthorized, "Invalid refresh token subject")
+	}
 
 	// Check token version
-	currentVersion, err := j.userRepo.GetTokenVersion(context.Background(), uuid.MustParse(userID))
+	currentVersion, err := j.userRepo.GetTokenVersion(context.Background(), userID)
 	if err != nil {
+		metrics.TokenRefreshes.WithLabelValues(metrics.ResultError).Inc()
 		return "", "", fiber.NewError(fiber.StatusUnauthorized, "Failed to get current token version: "+err.Error())
 	}
 	if currentVersion != claims.TokenVersion {
+		// A rotated refresh token being replayed may indicate a stolen token
+		j.audit.Record(ctx, models.AuditTokenRefreshFailed, &userID, "", "token has been revoked or version mismatch")
+		metrics.TokenRefreshes.WithLabelValues(metrics.ResultFailure).Inc()
 		return "", "", fiber.NewError(fiber.StatusUnauthorized, "Token has been revoked or version mismatch")
 	}
 
 	// Increment token version in the database
-	err = j.userRepo.IncrementTokenVersion(context.Background(), uuid.MustParse(userID))
+	err = j.userRepo.IncrementTokenVersion(context.Background(), userID)
 	if err != nil {
+		metrics.TokenRefreshes.WithLabelValues(metrics.ResultError).Inc()
 		return "", "", fiber.NewError(fiber.StatusUnauthorized, "Failed to increment token version: "+err.Error())
 	}
 
-	user, err := j.userRepo.GetByID(context.Background(), uuid.MustParse(userID))
+	user, err := j.userRepo.GetByID(context.Background(), userID)
 	if err != nil {
+		metrics.TokenRefreshes.WithLabelValues(metrics.ResultError).Inc()
 		return "", "", fiber.NewError(fiber.StatusUnauthorized, "Failed to get user: "+err.Error())
 	}
 
 	// Generate new access token
-	newAccessToken, err := j.GenerateAccessToken(uuid.MustParse(userID), claims.Subject, claims.Subject, string(user.Role), string(user.Status), user.TokenVersion)
+	newAccessToken, err := j.GenerateAccessToken(userID, claims.Subject, claims.Subject, string(user.Role), string(user.Status), user.TokenVersion)
 	if err != nil {
+		metrics.TokenRefreshes.WithLabelValues(metrics.ResultError).Inc()
 		return "", "", fiber.NewError(fiber.StatusInternalServerError, "Failed to generate new access token: "+err.Error())
 	}
 
 	// Generate new refresh token (Refresh token rotates on every refresh)
-	newRefreshToken, err := j.GenerateRefreshToken(uuid.MustParse(userID), user.TokenVersion)
+	newRefreshToken, err := j.GenerateRefreshToken(userID, user.TokenVersion)
 	if err != nil {
+		metrics.TokenRefreshes.WithLabelValues(metrics.ResultError).Inc()
 		return "", "", fiber.NewError(fiber.StatusInternalServerError, "Failed to generate new refresh token: "+err.Error())
 	}
 
+	j.audit.Record(ctx, models.AuditTokenRefreshed, &userID, "", "")
+	metrics.TokenRefreshes.WithLabelValues(metrics.ResultSuccess).Inc()
+
 	return newAccessToken, newRefreshToken, nil
 }
\ No newline at end of file
@@ -1,9 +1,110 @@
 package middlewares
 
 import (
+	"strconv"
+	"time"
+
+	"go-backend-todo/internal/db"
+	"go-backend-todo/internal/metrics"
+
 	"github.com/gofiber/fiber/v2"
 )
 
+// readYourWritesCookie holds the end of a user's read-your-writes window in Unix milliseconds, so that it
+// holds in whichever process or instance serves their next request
+const readYourWritesCookie = "read_your_writes_until"
+
+// readYourWritesRouterKey is the key of the router in the locals of requests that pin reads after writes
+type readYourWritesRouterKey struct{}
+
 func NotFound(c *fiber.Ctx) error {
 	return c.SendStatus(404)
 }
+
+// Metrics middleware records the count and latency of requests.
+// Requests are labelled with the template of the route that handled them, like /api/v1/todos/:id, so IDs do not end up in labels.
+func Metrics() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		// The error handler sets the status of returned errors after the middleware chain
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			if e, ok := err.(*fiber.Error); ok {
+				status = e.Code
+			}
+		}
+
+		route := c.Route().Path
+		if status == fiber.StatusNotFound && route == "/" {
+			// Unmatched paths end at the catch-all 404 handler, keep them out of the labels
+			route = "unmatched"
+		}
+
+		labels := []string{c.Method(), route, strconv.Itoa(status)}
+		metrics.HTTPRequests.WithLabelValues(labels...).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+// ReadYourWrites middleware sends the reads of a user to the primary for a while after they changed something,
+// so they never read from a replica that has not caught up with their own write.
+// Writes set a short-lived cookie that pins the following requests wherever they are served, since with prefork
+// or several instances they rarely reach the same process. Clients that drop cookies are still pinned by the
+// process that served their write, once authentication has identified them.
+func ReadYourWrites(router *db.Router) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		pinnedUntil, _ := strconv.ParseInt(c.Cookies(readYourWritesCookie), 10, 64)
+		c.Locals(db.PrimaryKey, time.Now().UnixMilli() < pinnedUntil)
+		c.Locals(readYourWritesRouterKey{}, router)
+
+		err := c.Next()
+
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions, "PROPFIND", "REPORT":
+			return err
+		}
+		if err == nil && c.Response().StatusCode() < fiber.StatusBadRequest {
+			if userID, idErr := GetUserIDFromContext(c); idErr == nil {
+				router.MarkWrite(userID)
+				pinReads(c, router.ReadYourWritesWindow())
+			}
+		}
+		return err
+	}
+}
+
+// pinRecentWriter pins the reads of the authenticated user to the primary when this process served one of
+// their writes recently. Authentication calls it once the user is known, so the decision is made up front
+// and stays valid for reads that outlive the handler, such as streamed responses.
+func pinRecentWriter(c *fiber.Ctx) {
+	router, ok := c.Locals(readYourWritesRouterKey{}).(*db.Router)
+	if !ok {
+		return
+	}
+
+	if userID, err := GetUserIDFromContext(c); err == nil && router.WroteRecently(userID) {
+		c.Locals(db.PrimaryKey, true)
+	}
+}
+
+// pinReads sets the cookie that sends the reads of the client to the primary for window
+func pinReads(c *fiber.Ctx, window time.Duration) {
+	if window <= 0 {
+		return
+	}
+
+	until := time.Now().Add(window)
+	c.Cookie(&fiber.Cookie{
+		Name:     readYourWritesCookie,
+		Value:    strconv.FormatInt(until.UnixMilli(), 10),
+		Path:     "/",
+		Expires:  until,
+		Secure:   c.Protocol() == "https",
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+}
@@ -44,12 +44,20 @@ func getValidationMessage(err validator.FieldError) string {
 		return "must be a valid hex color code"
 	case "uuid":
 		return "must be a valid UUID"
+	case "timezone":
+		return "must be a valid IANA time zone name"
+	case "bcp47_language_tag":
+		return "must be a valid BCP 47 language tag"
+	case "url":
+		return "must be a valid URL"
 	default:
 		return "is invalid"
 	}
 }
 
-// RequestValidation middleware validates request body and query parameters
+// RequestValidation middleware validates request body and query parameters.
+// Handlers call it inline and carry on themselves, so it does not pass the request on to the next matching
+// route, which would run the handlers and middlewares registered after it and relabel the request's route.
 func RequestValidation(body *[]byte, req interface{}) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		if err := json.Unmarshal(*body, req); err != nil {
@@ -60,6 +68,6 @@ func RequestValidation(body *[]byte, req interface{}) fiber.Handler {
 			return fmt.Errorf("validation error: %s", err.Error())
 		}
 
-		return c.Next()
+		return nil
 	}
 }
@@ -6,9 +6,10 @@ import (
 
 // ErrorResponse represents an error API response
 type ErrorResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"message"`
-	Error   string `json:"error,omitempty"`
+	Success bool        `json:"success"`
+	Message string      `json:"message"`
+	Error   string      `json:"error,omitempty"`
+	Details interface{} `json:"details,omitempty"`
 }
 
 // BadRequest returns a 400 Bad Request error
@@ -78,6 +79,30 @@ func Conflict(c *fiber.Ctx, message string) error {
 	})
 }
 
+// UnprocessableEntity returns a 422 Unprocessable Entity error with details of what could not be processed
+func UnprocessableEntity(c *fiber.Ctx, message string, details interface{}) error {
+	if message == "" {
+		message = "Unprocessable Entity"
+	}
+	return c.Status(fiber.StatusUnprocessableEntity).JSON(ErrorResponse{
+		Success: false,
+		Message: message,
+		Details: details,
+	})
+}
+
+// ServiceUnavailable returns a 503 Service Unavailable error with details of what is unavailable
+func ServiceUnavailable(c *fiber.Ctx, message string, details interface{}) error {
+	if message == "" {
+		message = "Service Unavailable"
+	}
+	return c.Status(fiber.StatusServiceUnavailable).JSON(ErrorResponse{
+		Success: false,
+		Message: message,
+		Details: details,
+	})
+}
+
 // InternalServerError returns a 500 Internal Server Error
 func InternalServerError(c *fiber.Ctx, message string) error {
 	if message == "" {
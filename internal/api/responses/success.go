@@ -51,6 +51,18 @@ func Created(c *fiber.Ctx, message string, data interface{}) error {
 	})
 }
 
+// Accepted returns a successful response with 202 status, for work that completes in the background
+func Accepted(c *fiber.Ctx, message string, data interface{}) error {
+	if message == "" {
+		message = "Accepted"
+	}
+	return c.Status(fiber.StatusAccepted).JSON(SuccessResponse{
+		Success: true,
+		Message: message,
+		Data:    data,
+	})
+}
+
 // OKWithPagination returns a paginated successful response
 func OKWithPagination(c *fiber.Ctx, message string, data interface{}, page, limit int, total int64) error {
 	if message == "" {
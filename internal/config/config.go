@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 )
 
 // Config holds all configuration for our application
@@ -15,6 +16,17 @@ type Config struct {
 	CORS     CORSConfig
 	Token    TokenConfig
 	Timeouts TimeoutsConfig
+	Reminder ReminderConfig
+	Digest   DigestConfig
+	Webhook  WebhookConfig
+	Stream   StreamConfig
+	List     ListConfig
+	Trash    TrashConfig
+	Undo     UndoConfig
+	Import   ImportConfig
+	DAV      DAVConfig
+	Export   ExportConfig
+	Account  AccountConfig
 }
 
 // AppConfig holds application-specific configuration
@@ -22,6 +34,7 @@ type AppConfig struct {
 	Name        string
 	Environment string
 	Debug       bool
+	BaseURL     string // public URL used in links sent by email
 }
 
 // DatabaseConfig holds database configuration
@@ -33,12 +46,33 @@ type DatabaseConfig struct {
 	DBName         string
 	SSLMode        string
 	ChannelBinding string
+	AutoMigrate    bool // apply pending migrations when the server starts
+
+	MaxConns          int    // 0 keeps the pgx default of the larger of 4 and the number of CPUs
+	MinConns          int    // idle connections kept open
+	MaxConnLifetime   int    // in seconds, before a connection is closed and replaced
+	MaxConnIdleTime   int    // in seconds, before an idle connection is closed
+	HealthCheckPeriod int    // in seconds, between checks of idle connections
+	ConnectTimeout    int    // in seconds, for each new connection
+	QueryExecMode     string // cache_statement, cache_describe, describe_exec, exec or simple_protocol; use exec or simple_protocol behind a transaction pooler
+	ConnectRetries    int    // extra attempts to reach the database on startup
+	RetryBackoff      int    // in seconds, doubled after every failed attempt
+	RetryBackoffMax   int    // in seconds
+
+	ReplicaURLs          []string // connection strings of read replicas, which serve list, search, stats and export queries
+	ReplicaCheckInterval int      // in seconds, between health checks of the replicas
+	ReadYourWritesWindow int      // in seconds, after a write during which the reads of the same user go to the primary
 }
 
 // ServerConfig holds server configuration
 type ServerConfig struct {
-	Host string
-	Port string
+	Host            string
+	Port            string
+	DrainPeriod     int // in seconds, between failing readiness and shutting down so load balancers stop sending requests
+	ShutdownTimeout int // in seconds, for the whole shutdown including the drain period; keep it below the termination grace period
+	// Prefork runs one process per CPU. Only the parent receives signals and it serves no requests,
+	// so children are killed without draining or waiting for in-flight requests.
+	Prefork bool
 }
 
 // JWTConfig holds JWT configuration
@@ -75,6 +109,89 @@ type TokenConfig struct {
 	VerifyEmailTokenTTL        int // in minutes
 	RecoverPasswordTokenSecret string
 	RecoverPasswordTokenTTL    int // in minutes
+	UnsubscribeTokenSecret     string
+	UnsubscribeTokenTTL        int // in minutes
+	DataExportTokenSecret      string
+}
+
+// ReminderConfig holds deadline reminder configuration
+type ReminderConfig struct {
+	Enabled        bool
+	DefaultOffsets []int // in minutes before the deadline
+	PollInterval   int   // in seconds
+	BatchSize      int
+}
+
+// DigestConfig holds daily digest email configuration
+type DigestConfig struct {
+	Enabled         bool
+	DefaultSendHour int // local hour of day, 0-23
+	SendWindowHours int // hours after the send hour during which a missed digest is still sent
+	PollInterval    int // in seconds
+	BatchSize       int
+}
+
+// WebhookConfig holds webhook delivery configuration
+type WebhookConfig struct {
+	Enabled             bool
+	PollInterval        int // in seconds
+	BatchSize           int
+	RequestTimeout      int // in seconds
+	MaxAttempts         int
+	BackoffBase         int  // in seconds, doubled after every failed attempt
+	BackoffMax          int  // in seconds
+	AllowPrivateTargets bool // allow delivery to loopback and private network addresses
+}
+
+// StreamConfig holds real-time event stream configuration
+type StreamConfig struct {
+	HeartbeatInterval int // in seconds
+	ClientBufferSize  int // events buffered per connection before a slow client is disconnected
+}
+
+// ListConfig holds shared list configuration
+type ListConfig struct {
+	InvitationTTL int // in hours
+	MaxMembers    int
+}
+
+// TrashConfig holds configuration of the trash of deleted todos
+type TrashConfig struct {
+	PurgeEnabled  bool
+	RetentionDays int // days a deleted todo can be restored before it is purged
+	PurgeInterval int // in seconds
+}
+
+// UndoConfig holds configuration of undo for destructive todo actions
+type UndoConfig struct {
+	Window int // in seconds, how long an undo token stays valid
+}
+
+// ImportConfig holds configuration of todo imports
+type ImportConfig struct {
+	MaxFileSize int // in bytes
+	MaxRows     int
+}
+
+// DAVConfig holds configuration of the CalDAV endpoints
+type DAVConfig struct {
+	Enabled           bool
+	AllowPasswordAuth bool // accept account passwords in Basic auth, besides personal access tokens; off by default as nothing throttles them
+}
+
+// ExportConfig holds configuration of account data exports
+type ExportConfig struct {
+	Enabled      bool
+	PollInterval int // in seconds
+	LinkTTL      int // in hours, how long the download link and the archive are kept
+	MaxAttempts  int
+}
+
+// AccountConfig holds configuration of account deletion
+type AccountConfig struct {
+	DeletionGraceDays int // days before a scheduled deletion is carried out, logging in cancels it
+	PurgeEnabled      bool
+	PurgeInterval     int // in seconds
 }
 
 // TimeoutsConfig holds timeout configuration
@@ -94,6 +211,7 @@ func Load() *Config {
 			Name:        GetEnv("APP_NAME", "Go Backend Todo API"),
 			Environment: GetEnv("APP_ENV", "development"),
 			Debug:       getEnvAsBool("APP_DEBUG", true),
+			BaseURL:     GetEnv("APP_BASE_URL", "http://localhost:8080"),
 		},
 		Database: DatabaseConfig{
 			Host:           GetEnv("DB_HOST", "localhost"),
@@ -103,10 +221,30 @@ func Load() *Config {
 			DBName:         GetEnv("DB_NAME", "todo_db"),
 			SSLMode:        GetEnv("DB_SSLMODE", "disable"),
 			ChannelBinding: GetEnv("DB_CHANNEL_BINDING", "prefer"),
+			AutoMigrate:    getEnvAsBool("DB_AUTO_MIGRATE", false),
+
+			MaxConns:          getEnvAsInt("DB_MAX_CONNS", 0),
+			MinConns:          getEnvAsInt("DB_MIN_CONNS", 0),
+			MaxConnLifetime:   getEnvAsInt("DB_MAX_CONN_LIFETIME_SECONDS", 3600),
+			MaxConnIdleTime:   getEnvAsInt("DB_MAX_CONN_IDLE_TIME_SECONDS", 1800),
+			HealthCheckPeriod: getEnvAsInt("DB_HEALTH_CHECK_PERIOD_SECONDS", 60),
+			ConnectTimeout:    getEnvAsInt("DB_CONNECT_TIMEOUT_SECONDS", 5),
+			QueryExecMode:     GetEnv("DB_QUERY_EXEC_MODE", "cache_statement"),
+			ConnectRetries:    getEnvAsInt("DB_CONNECT_RETRIES", 5),
+			RetryBackoff:      getEnvAsInt("DB_RETRY_BACKOFF_SECONDS", 1),
+			RetryBackoffMax:   getEnvAsInt("DB_RETRY_BACKOFF_MAX_SECONDS", 30),
+
+			ReplicaURLs:          getEnvAsSlice("DB_REPLICA_URLS", nil),
+			ReplicaCheckInterval: getEnvAsInt("DB_REPLICA_CHECK_INTERVAL_SECONDS", 10),
+			ReadYourWritesWindow: getEnvAsInt("DB_READ_YOUR_WRITES_SECONDS", 10),
 		},
 		Server: ServerConfig{
 			Host: GetEnv("SERVER_HOST", "localhost"),
 			Port: GetEnv("SERVER_PORT", "8080"),
+
+			DrainPeriod:     getEnvAsInt("SERVER_DRAIN_PERIOD_SECONDS", 5),
+			ShutdownTimeout: getEnvAsInt("SERVER_SHUTDOWN_TIMEOUT_SECONDS", 25),
+			Prefork:         getEnvAsBool("SERVER_PREFORK", false),
 		},
 		JWT: JWTConfig{
 			AccessSecret:       GetEnv("JWT_ACCESS_SECRET", "your-super-secret-access-key"),
@@ -135,6 +273,9 @@ func Load() *Config {
 			VerifyEmailTokenTTL:        getEnvAsInt("VERIFY_EMAIL_TOKEN_TTL_MINUTES", 30), // Default 30 minutes
 			RecoverPasswordTokenSecret: GetEnv("RECOVER_PASSWORD_TOKEN_SECRET", "your-super-secret-recover-password-key"),
 			RecoverPasswordTokenTTL:    getEnvAsInt("RECOVER_PASSWORD_TOKEN_TTL_MINUTES", 30), // Default 30 minutes
+			UnsubscribeTokenSecret:     GetEnv("UNSUBSCRIBE_TOKEN_SECRET", "your-super-secret-unsubscribe-key"),
+			UnsubscribeTokenTTL:        getEnvAsInt("UNSUBSCRIBE_TOKEN_TTL_MINUTES", 60*24*90), // Default 90 days
+			DataExportTokenSecret:      GetEnv("DATA_EXPORT_TOKEN_SECRET", "your-super-secret-data-export-key"),
 		},
 		Timeouts: TimeoutsConfig{
 			AuthTimeout: AuthTimeout{
@@ -155,6 +296,64 @@ func Load() *Config {
 				VerifyEmailTimeout: getEnvAsInt("EMAIL_VERIFY_TIMEOUT", 60),
 			},
 		},
+		Reminder: ReminderConfig{
+			Enabled:        getEnvAsBool("REMINDER_ENABLED", true),
+			DefaultOffsets: getEnvAsIntSlice("REMINDER_DEFAULT_OFFSETS", []int{1440, 60}), // 1 day and 1 hour before
+			PollInterval:   getEnvAsInt("REMINDER_POLL_INTERVAL_SECONDS", 60),
+			BatchSize:      getEnvAsInt("REMINDER_BATCH_SIZE", 100),
+		},
+		Digest: DigestConfig{
+			Enabled:         getEnvAsBool("DIGEST_ENABLED", true),
+			DefaultSendHour: getEnvAsInt("DIGEST_DEFAULT_SEND_HOUR", 7),
+			SendWindowHours: getEnvAsInt("DIGEST_SEND_WINDOW_HOURS", 3),
+			PollInterval:    getEnvAsInt("DIGEST_POLL_INTERVAL_SECONDS", 300),
+			BatchSize:       getEnvAsInt("DIGEST_BATCH_SIZE", 50),
+		},
+		Webhook: WebhookConfig{
+			Enabled:             getEnvAsBool("WEBHOOK_ENABLED", true),
+			PollInterval:        getEnvAsInt("WEBHOOK_POLL_INTERVAL_SECONDS", 5),
+			BatchSize:           getEnvAsInt("WEBHOOK_BATCH_SIZE", 50),
+			RequestTimeout:      getEnvAsInt("WEBHOOK_REQUEST_TIMEOUT_SECONDS", 10),
+			MaxAttempts:         getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 8),
+			BackoffBase:         getEnvAsInt("WEBHOOK_BACKOFF_BASE_SECONDS", 30),
+			BackoffMax:          getEnvAsInt("WEBHOOK_BACKOFF_MAX_SECONDS", 3600),
+			AllowPrivateTargets: getEnvAsBool("WEBHOOK_ALLOW_PRIVATE_TARGETS", false),
+		},
+		Stream: StreamConfig{
+			HeartbeatInterval: getEnvAsInt("STREAM_HEARTBEAT_INTERVAL_SECONDS", 15),
+			ClientBufferSize:  getEnvAsInt("STREAM_CLIENT_BUFFER_SIZE", 64),
+		},
+		List: ListConfig{
+			InvitationTTL: getEnvAsInt("LIST_INVITATION_TTL_HOURS", 168),
+			MaxMembers:    getEnvAsInt("LIST_MAX_MEMBERS", 50),
+		},
+		Trash: TrashConfig{
+			PurgeEnabled:  getEnvAsBool("TRASH_PURGE_ENABLED", true),
+			RetentionDays: getEnvAsInt("TRASH_RETENTION_DAYS", 30),
+			PurgeInterval: getEnvAsInt("TRASH_PURGE_INTERVAL_SECONDS", 3600),
+		},
+		Undo: UndoConfig{
+			Window: getEnvAsInt("UNDO_WINDOW_SECONDS", 30),
+		},
+		Import: ImportConfig{
+			MaxFileSize: getEnvAsInt("IMPORT_MAX_FILE_SIZE_BYTES", 4*1024*1024),
+			MaxRows:     getEnvAsInt("IMPORT_MAX_ROWS", 5000),
+		},
+		DAV: DAVConfig{
+			Enabled:           getEnvAsBool("DAV_ENABLED", true),
+			AllowPasswordAuth: getEnvAsBool("DAV_ALLOW_PASSWORD_AUTH", false),
+		},
+		Export: ExportConfig{
+			Enabled:      getEnvAsBool("EXPORT_ENABLED", true),
+			PollInterval: getEnvAsInt("EXPORT_POLL_INTERVAL_SECONDS", 30),
+			LinkTTL:      getEnvAsInt("EXPORT_LINK_TTL_HOURS", 72),
+			MaxAttempts:  getEnvAsInt("EXPORT_MAX_ATTEMPTS", 3),
+		},
+		Account: AccountConfig{
+			DeletionGraceDays: getEnvAsInt("ACCOUNT_DELETION_GRACE_DAYS", 14),
+			PurgeEnabled:      getEnvAsBool("ACCOUNT_PURGE_ENABLED", true),
+			PurgeInterval:     getEnvAsInt("ACCOUNT_PURGE_INTERVAL_SECONDS", 3600),
+		},
 	}
 }
 
@@ -175,6 +374,38 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsSlice(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var result []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
+}
+
+func getEnvAsIntSlice(key string, defaultValue []int) []int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var result []int
+	for _, part := range strings.Split(value, ",") {
+		intValue, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return defaultValue
+		}
+		result = append(result, intValue)
+	}
+	return result
+}
+
 func getEnvAsBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		if boolValue, err := strconv.ParseBool(value); err == nil {
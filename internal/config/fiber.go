@@ -6,6 +6,10 @@ import "github.com/gofiber/fiber/v2"
 func GetFiberConfig(cfg *Config) fiber.Config {
 	return fiber.Config{
 		AppName: cfg.App.Name,
+		// Leave room for the multipart envelope around the largest accepted import file
+		BodyLimit: max(fiber.DefaultBodyLimit, cfg.Import.MaxFileSize+64*1024),
+		// WebDAV methods used by CalDAV clients
+		RequestMethods: append(append([]string{}, fiber.DefaultMethods...), "PROPFIND", "PROPPATCH", "REPORT"),
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			code := fiber.StatusInternalServerError
 			if e, ok := err.(*fiber.Error); ok {
@@ -16,7 +20,7 @@ func GetFiberConfig(cfg *Config) fiber.Config {
 				"success": false,
 			})
 		},
-		// Enable prefork in production for better performance
-		Prefork: cfg.App.Environment == "production",
+		// Opt-in, as prefork gives up graceful shutdown
+		Prefork: cfg.Server.Prefork,
 	}
 }
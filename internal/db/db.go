@@ -2,7 +2,9 @@ package db
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"time"
 
 	"go-backend-todo/internal/config"
 
@@ -10,111 +12,106 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// Singleton instances
-var DbInstance *pgx.Conn
-var DbPool *pgxpool.Pool
-
-// ConnectPool creates connection pool (RECOMMENDED for production)
-func ConnectPool() (*pgxpool.Pool, error) {
-	cfg := config.Load()
-	connStr := config.GetDatabaseURL(cfg)
-
-	ctx := context.Background()
-	pool, err := pgxpool.New(ctx, connStr)
-	if err != nil {
-		return nil, err
-	}
-
-	poolHealthCheck(pool)
-	return pool, nil
+// queryExecModes maps the DB_QUERY_EXEC_MODE values to pgx modes
+var queryExecModes = map[string]pgx.QueryExecMode{
+	"cache_statement": pgx.QueryExecModeCacheStatement,
+	"cache_describe":  pgx.QueryExecModeCacheDescribe,
+	"describe_exec":   pgx.QueryExecModeDescribeExec,
+	"exec":            pgx.QueryExecModeExec,
+	"simple_protocol": pgx.QueryExecModeSimpleProtocol,
 }
 
-// ConnectPoolWithConfig creates connection pool with specific config
-func ConnectPoolWithConfig(cfg *config.Config) (*pgxpool.Pool, error) {
-	connStr := config.GetDatabaseURL(cfg)
-
-	ctx := context.Background()
-	pool, err := pgxpool.New(ctx, connStr)
+// Open creates a connection pool and waits until the database answers.
+// Startup is retried with exponential backoff, so the server can start alongside the database.
+// The caller owns the pool and must close it.
+func Open(ctx context.Context, cfg *config.Config) (*pgxpool.Pool, error) {
+	poolConfig, err := newPoolConfig(cfg, config.GetDatabaseURL(cfg))
 	if err != nil {
 		return nil, err
 	}
 
-	poolHealthCheck(pool)
-	return pool, nil
-}
-
-// GetPool returns singleton connection pool
-func GetPool() *pgxpool.Pool {
-	if DbPool == nil {
-		var err error
-		DbPool, err = ConnectPool()
-		if err != nil {
-			log.Fatalf("Failed to create connection pool: %v", err)
-		}
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create connection pool: %w", err)
 	}
-	return DbPool
-}
-
-// Connect establishes single connection (use only for testing or simple cases)
-func Connect() (*pgx.Conn, error) {
-	cfg := config.Load()
-	connStr := config.GetDatabaseURL(cfg)
 
-	ctx := context.Background()
-	db, err := pgx.Connect(ctx, connStr)
-	if err != nil {
+	if err := ping(ctx, pool, cfg); err != nil {
+		pool.Close()
 		return nil, err
 	}
 
-	connectHealthCheck(db)
-
-	return db, nil
+	log.Println("Database connection pool is healthy")
+	return pool, nil
 }
 
-// ConnectWithConfig connects using specific configuration
-func ConnectWithConfig(cfg *config.Config) (*pgx.Conn, error) {
-	connStr := config.GetDatabaseURL(cfg)
-
-	ctx := context.Background()
-	db, err := pgx.Connect(ctx, connStr)
+// newPoolConfig builds the pgx pool configuration of a connection string from the database settings
+func newPoolConfig(cfg *config.Config, connString string) (*pgxpool.Config, error) {
+	poolConfig, err := pgxpool.ParseConfig(connString)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("invalid database configuration: %w", err)
 	}
 
-	connectHealthCheck(db)
-	return db, nil
-}
+	dbConfig := cfg.Database
+	if dbConfig.MaxConns > 0 {
+		poolConfig.MaxConns = int32(dbConfig.MaxConns)
+	}
+	if dbConfig.MinConns > 0 {
+		poolConfig.MinConns = int32(dbConfig.MinConns)
+	}
+	if poolConfig.MinConns > poolConfig.MaxConns {
+		return nil, fmt.Errorf("DB_MIN_CONNS (%d) exceeds DB_MAX_CONNS (%d)", poolConfig.MinConns, poolConfig.MaxConns)
+	}
+	if dbConfig.MaxConnLifetime > 0 {
+		poolConfig.MaxConnLifetime = time.Duration(dbConfig.MaxConnLifetime) * time.Second
+	}
+	if dbConfig.MaxConnIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = time.Duration(dbConfig.MaxConnIdleTime) * time.Second
+	}
+	if dbConfig.HealthCheckPeriod > 0 {
+		poolConfig.HealthCheckPeriod = time.Duration(dbConfig.HealthCheckPeriod) * time.Second
+	}
+	if dbConfig.ConnectTimeout > 0 {
+		poolConfig.ConnConfig.ConnectTimeout = time.Duration(dbConfig.ConnectTimeout) * time.Second
+	}
 
-// GetDB returns a singleton instance of the database connection
-func GetDB() *pgx.Conn {
-	if DbInstance == nil {
-		var err error
-		DbInstance, err = Connect()
-		if err != nil {
-			log.Fatalf("Failed to connect to database: %v", err)
+	if dbConfig.QueryExecMode != "" {
+		mode, ok := queryExecModes[dbConfig.QueryExecMode]
+		if !ok {
+			return nil, fmt.Errorf("unknown DB_QUERY_EXEC_MODE %q", dbConfig.QueryExecMode)
 		}
+		poolConfig.ConnConfig.DefaultQueryExecMode = mode
 	}
-	return DbInstance
+
+	return poolConfig, nil
 }
 
-// For connection pool health check
-func poolHealthCheck(pool *pgxpool.Pool) {
-	ctx := context.Background()
-	if err := pool.Ping(ctx); err != nil {
-		log.Fatalf("Failed to ping database: %v", err)
+// ping checks the pool can reach the database, retrying failed attempts with exponential backoff
+func ping(ctx context.Context, pool *pgxpool.Pool, cfg *config.Config) error {
+	backoff := time.Duration(cfg.Database.RetryBackoff) * time.Second
+	maxBackoff := time.Duration(cfg.Database.RetryBackoffMax) * time.Second
+	if backoff <= 0 {
+		backoff = time.Second
 	}
-	log.Println("Database connection pool is healthy")
 
-	DbPool = pool
-}
+	for attempt := 0; ; attempt++ {
+		err := pool.Ping(ctx)
+		if err == nil {
+			return nil
+		}
+		if attempt >= cfg.Database.ConnectRetries {
+			return fmt.Errorf("failed to reach database after %d attempts: %w", attempt+1, err)
+		}
 
-// For single connection
-func connectHealthCheck(db *pgx.Conn) {
-	ctx := context.Background()
-	if err := db.Ping(ctx); err != nil {
-		log.Fatalf("Failed to ping database: %v", err)
-	}
-	log.Println("Database connection is healthy")
+		log.Printf("Database is not reachable yet, retrying in %s: %v", backoff, err)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("gave up reaching database: %w", ctx.Err())
+		case <-time.After(backoff):
+		}
 
-	DbInstance = db
+		backoff *= 2
+		if maxBackoff > 0 && backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
 }
@@ -1,5 +1,4 @@
-BEGIN;
-
+-- Remove the initial schema
 
 DROP INDEX IF EXISTS idx_users_id;
 DROP INDEX IF EXISTS idx_users_username;
@@ -19,6 +18,3 @@ DROP TABLE IF EXISTS user_account;
 DROP TYPE IF EXISTS email_validation_status_enum;
 
 DROP TYPE IF EXISTS user_role_enum;
-
-
-COMMIT;
\ No newline at end of file
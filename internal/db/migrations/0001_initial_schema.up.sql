@@ -77,138 +77,3 @@ CREATE INDEX idx_users_email ON user_account (email_address);
 CREATE INDEX idx_users_username ON user_account (user_name);
 
 CREATE INDEX idx_users_id ON user_account (user_id);
-
--- Create sample data for users
-INSERT INTO
-    user_account (
-        user_name,
-        user_role,
-        password_hash,
-        email_address,
-        email_validation_status
-    )
-VALUES
-    (
-        'BaoNguyxn',
-        'admin',
-        'hashed_password',
-        'admin@example.com',
-        'confirmed'
-    );
-
--- Create sample data for external providers
-INSERT INTO
-    external_providers (provider_name, ws_endpoint)
-VALUES
-    (
-        'Google',
-        'https://accounts.google.com/o/oauth2/auth'
-    ),
-    (
-        'GitHub',
-        'https://github.com/login/oauth/authorize'
-    );
-
--- Create sample data for user_account_external
-INSERT INTO
-    user_account_external (
-        user_id,
-        external_provider_id,
-        external_provider_token
-    )
-VALUES
-    (
-        (
-            SELECT
-                user_id
-            FROM
-                user_account
-            WHERE
-                user_role = 'admin'
-        ),
-        (
-            SELECT
-                external_provider_id
-            FROM
-                external_providers
-            WHERE
-                provider_name = 'Google'
-        ),
-        'google_token_12345'
-    ),
-    (
-        (
-            SELECT
-                user_id
-            FROM
-                user_account
-            WHERE
-                user_role = 'admin'
-        ),
-        (
-            SELECT
-                external_provider_id
-            FROM
-                external_providers
-            WHERE
-                provider_name = 'GitHub'
-        ),
-        'github_token_67890'
-    );
-
--- Create sample data for todos
-INSERT INTO
-    todos (title, deadline, completed, user_id)
-VALUES
-    (
-        'Buy groceries',
-        NOW () + INTERVAL '1 day',
-        FALSE,
-        (
-            SELECT
-                user_id
-            FROM
-                user_account
-            WHERE
-                user_role = 'admin'
-        )
-    ),
-    (
-        'Complete project report',
-        NOW () + INTERVAL '2 days',
-        FALSE,
-        (
-            SELECT
-                user_id
-            FROM
-                user_account
-            WHERE
-                user_role = 'admin'
-        )
-    ),
-    (
-        'Schedule dentist appointment',
-        NOW () + INTERVAL '5 days',
-        TRUE,
-        (
-            SELECT
-                user_id
-            FROM
-                user_account
-            WHERE
-                user_role = 'admin'
-        )
-    ),
-    (
-        'Call mom',
-        NOW () + INTERVAL '3 days',
-        TRUE,
-        (
-            SELECT
-                user_id
-            FROM
-                user_account
-            WHERE
-                user_role = 'admin'
-        )
-    );
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Remove todo_reminders table
+DROP INDEX IF EXISTS idx_todo_reminders_todo_id;
+DROP INDEX IF EXISTS idx_todo_reminders_remind_at;
+DROP TABLE IF EXISTS todo_reminders;
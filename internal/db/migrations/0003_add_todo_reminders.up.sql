@@ -0,0 +1,24 @@
+-- Create todo_reminders table for deadline reminder notifications
+CREATE TABLE
+    todo_reminders (
+        id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+        todo_id UUID NOT NULL REFERENCES todos (id) ON DELETE CASCADE,
+        offset_minutes INTEGER NOT NULL CHECK (offset_minutes > 0),
+        remind_at TIMESTAMP
+        WITH
+            TIME ZONE NOT NULL,
+            sent_at TIMESTAMP
+        WITH
+            TIME ZONE,
+            created_at TIMESTAMP
+        WITH
+            TIME ZONE DEFAULT NOW (),
+            UNIQUE (todo_id, offset_minutes)
+    );
+
+-- Only pending reminders are scanned by the scheduler
+CREATE INDEX idx_todo_reminders_remind_at ON todo_reminders (remind_at)
+WHERE
+    sent_at IS NULL;
+
+CREATE INDEX idx_todo_reminders_todo_id ON todo_reminders (todo_id);
@@ -0,0 +1,4 @@
+-- Remove digest_subscriptions table
+DROP INDEX IF EXISTS idx_todos_user_id_deadline;
+DROP INDEX IF EXISTS idx_digest_subscriptions_enabled;
+DROP TABLE IF EXISTS digest_subscriptions;
@@ -0,0 +1,22 @@
+-- Create digest_subscriptions table for the opt-in daily digest email
+CREATE TABLE
+    digest_subscriptions (
+        user_id UUID PRIMARY KEY REFERENCES user_account (user_id) ON DELETE CASCADE,
+        enabled BOOLEAN NOT NULL DEFAULT FALSE,
+        send_hour SMALLINT NOT NULL DEFAULT 7 CHECK (send_hour BETWEEN 0 AND 23),
+        timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+        last_sent_on DATE,
+        created_at TIMESTAMP
+        WITH
+            TIME ZONE DEFAULT NOW (),
+            updated_at TIMESTAMP
+        WITH
+            TIME ZONE DEFAULT NOW ()
+    );
+
+CREATE INDEX idx_digest_subscriptions_enabled ON digest_subscriptions (user_id)
+WHERE
+    enabled = TRUE;
+
+-- Speed up per-user deadline range queries used by statistics and digests
+CREATE INDEX idx_todos_user_id_deadline ON todos (user_id, deadline);
@@ -0,0 +1,43 @@
+-- Restore digest_subscriptions from user_preferences and remove user_preferences
+CREATE TABLE
+    digest_subscriptions (
+        user_id UUID PRIMARY KEY REFERENCES user_account (user_id) ON DELETE CASCADE,
+        enabled BOOLEAN NOT NULL DEFAULT FALSE,
+        send_hour SMALLINT NOT NULL DEFAULT 7 CHECK (send_hour BETWEEN 0 AND 23),
+        timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+        last_sent_on DATE,
+        created_at TIMESTAMP
+        WITH
+            TIME ZONE DEFAULT NOW (),
+            updated_at TIMESTAMP
+        WITH
+            TIME ZONE DEFAULT NOW ()
+    );
+
+CREATE INDEX idx_digest_subscriptions_enabled ON digest_subscriptions (user_id)
+WHERE
+    enabled = TRUE;
+
+INSERT INTO
+    digest_subscriptions (
+        user_id,
+        enabled,
+        send_hour,
+        timezone,
+        last_sent_on,
+        created_at,
+        updated_at
+    )
+SELECT
+    user_id,
+    email_digest,
+    digest_hour,
+    timezone,
+    digest_last_sent_on,
+    created_at,
+    updated_at
+FROM
+    user_preferences;
+
+DROP INDEX IF EXISTS idx_user_preferences_email_digest;
+DROP TABLE IF EXISTS user_preferences;
@@ -0,0 +1,49 @@
+-- Create user_preferences table
+CREATE TABLE
+    user_preferences (
+        user_id UUID PRIMARY KEY REFERENCES user_account (user_id) ON DELETE CASCADE,
+        timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+        locale VARCHAR(35) NOT NULL DEFAULT 'en',
+        week_start SMALLINT NOT NULL DEFAULT 1 CHECK (week_start BETWEEN 0 AND 6),
+        default_todo_sort VARCHAR(32) NOT NULL DEFAULT 'created_at_desc',
+        email_reminders BOOLEAN NOT NULL DEFAULT TRUE,
+        email_digest BOOLEAN NOT NULL DEFAULT FALSE,
+        digest_hour SMALLINT NOT NULL DEFAULT 7 CHECK (digest_hour BETWEEN 0 AND 23),
+        digest_last_sent_on DATE,
+        created_at TIMESTAMP
+        WITH
+            TIME ZONE DEFAULT NOW (),
+            updated_at TIMESTAMP
+        WITH
+            TIME ZONE DEFAULT NOW ()
+    );
+
+CREATE INDEX idx_user_preferences_email_digest ON user_preferences (user_id)
+WHERE
+    email_digest = TRUE;
+
+-- Digest subscriptions become part of the user preferences
+INSERT INTO
+    user_preferences (
+        user_id,
+        timezone,
+        email_digest,
+        digest_hour,
+        digest_last_sent_on,
+        created_at,
+        updated_at
+    )
+SELECT
+    user_id,
+    timezone,
+    enabled,
+    send_hour,
+    last_sent_on,
+    created_at,
+    updated_at
+FROM
+    digest_subscriptions;
+
+DROP INDEX IF EXISTS idx_digest_subscriptions_enabled;
+
+DROP TABLE IF EXISTS digest_subscriptions;
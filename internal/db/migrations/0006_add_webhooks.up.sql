@@ -0,0 +1,48 @@
+-- Create webhooks table for user-registered todo event subscriptions
+CREATE TABLE
+    webhooks (
+        id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+        user_id UUID NOT NULL REFERENCES user_account (user_id) ON DELETE CASCADE,
+        url VARCHAR(2048) NOT NULL,
+        secret VARCHAR(128) NOT NULL,
+        events TEXT[] NOT NULL,
+        description VARCHAR(255) NOT NULL DEFAULT '',
+        active BOOLEAN NOT NULL DEFAULT TRUE,
+        created_at TIMESTAMP
+        WITH
+            TIME ZONE DEFAULT NOW (),
+            updated_at TIMESTAMP
+        WITH
+            TIME ZONE DEFAULT NOW ()
+    );
+
+CREATE INDEX idx_webhooks_user_id ON webhooks (user_id);
+
+-- Create webhook_deliveries table as the delivery queue and log
+CREATE TABLE
+    webhook_deliveries (
+        id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+        webhook_id UUID NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+        event_id UUID NOT NULL,
+        event_type VARCHAR(50) NOT NULL,
+        payload JSONB NOT NULL,
+        status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed')),
+        attempts INTEGER NOT NULL DEFAULT 0,
+        next_attempt_at TIMESTAMP
+        WITH
+            TIME ZONE NOT NULL DEFAULT NOW (),
+            response_status INTEGER,
+            last_error TEXT,
+            created_at TIMESTAMP
+        WITH
+            TIME ZONE DEFAULT NOW (),
+            delivered_at TIMESTAMP
+        WITH
+            TIME ZONE
+    );
+
+CREATE INDEX idx_webhook_deliveries_next_attempt_at ON webhook_deliveries (next_attempt_at)
+WHERE
+    status = 'pending';
+
+CREATE INDEX idx_webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id, created_at DESC);
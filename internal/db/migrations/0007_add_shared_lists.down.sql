@@ -0,0 +1,8 @@
+-- Remove shared lists
+DROP INDEX IF EXISTS idx_todos_list_id;
+ALTER TABLE todos DROP COLUMN IF EXISTS list_id;
+DROP INDEX IF EXISTS idx_list_invitations_list_id;
+DROP TABLE IF EXISTS list_invitations;
+DROP INDEX IF EXISTS idx_list_members_user_id;
+DROP TABLE IF EXISTS list_members;
+DROP TABLE IF EXISTS lists;
@@ -0,0 +1,55 @@
+-- Create lists table for todo lists shared between users
+CREATE TABLE
+    lists (
+        id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+        name VARCHAR(100) NOT NULL,
+        owner_id UUID NOT NULL REFERENCES user_account (user_id) ON DELETE CASCADE,
+        created_at TIMESTAMP
+        WITH
+            TIME ZONE DEFAULT NOW (),
+            updated_at TIMESTAMP
+        WITH
+            TIME ZONE DEFAULT NOW ()
+    );
+
+-- Create list_members table with the role of each member
+CREATE TABLE
+    list_members (
+        list_id UUID NOT NULL REFERENCES lists (id) ON DELETE CASCADE,
+        user_id UUID NOT NULL REFERENCES user_account (user_id) ON DELETE CASCADE,
+        role VARCHAR(10) NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+        created_at TIMESTAMP
+        WITH
+            TIME ZONE DEFAULT NOW (),
+            PRIMARY KEY (list_id, user_id)
+    );
+
+CREATE INDEX idx_list_members_user_id ON list_members (user_id);
+
+-- Create list_invitations table for invitations sent by email
+CREATE TABLE
+    list_invitations (
+        id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+        list_id UUID NOT NULL REFERENCES lists (id) ON DELETE CASCADE,
+        email_address VARCHAR(100) NOT NULL,
+        role VARCHAR(10) NOT NULL CHECK (role IN ('editor', 'viewer')),
+        token_hash VARCHAR(64) NOT NULL UNIQUE,
+        invited_by UUID NOT NULL REFERENCES user_account (user_id) ON DELETE CASCADE,
+        expires_at TIMESTAMP
+        WITH
+            TIME ZONE NOT NULL,
+            accepted_at TIMESTAMP
+        WITH
+            TIME ZONE,
+            created_at TIMESTAMP
+        WITH
+            TIME ZONE DEFAULT NOW ()
+    );
+
+CREATE INDEX idx_list_invitations_list_id ON list_invitations (list_id);
+
+-- Todos without a list are personal to their user
+ALTER TABLE todos
+ADD COLUMN list_id UUID REFERENCES lists (id) ON DELETE CASCADE;
+
+CREATE INDEX idx_todos_list_id ON todos (list_id);
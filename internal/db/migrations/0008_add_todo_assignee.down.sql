@@ -0,0 +1,3 @@
+-- Remove todo assignment
+DROP INDEX IF EXISTS idx_todos_assignee_id;
+ALTER TABLE todos DROP COLUMN IF EXISTS assignee_id;
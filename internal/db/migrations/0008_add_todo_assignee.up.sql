@@ -0,0 +1,5 @@
+-- Todos can be assigned to a user with access to them
+ALTER TABLE todos
+ADD COLUMN assignee_id UUID REFERENCES user_account (user_id) ON DELETE SET NULL;
+
+CREATE INDEX idx_todos_assignee_id ON todos (assignee_id);
@@ -0,0 +1,4 @@
+-- Remove todo comments
+DROP INDEX IF EXISTS idx_todo_comments_parent_id;
+DROP INDEX IF EXISTS idx_todo_comments_todo_id;
+DROP TABLE IF EXISTS todo_comments;
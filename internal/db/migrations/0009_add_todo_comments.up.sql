@@ -0,0 +1,19 @@
+-- Create todo_comments table for threaded discussions on todos
+CREATE TABLE
+    todo_comments (
+        id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+        todo_id UUID NOT NULL REFERENCES todos (id) ON DELETE CASCADE,
+        parent_id UUID REFERENCES todo_comments (id) ON DELETE CASCADE,
+        author_id UUID NOT NULL REFERENCES user_account (user_id) ON DELETE CASCADE,
+        body TEXT NOT NULL,
+        created_at TIMESTAMP
+        WITH
+            TIME ZONE DEFAULT NOW (),
+            updated_at TIMESTAMP
+        WITH
+            TIME ZONE DEFAULT NOW ()
+    );
+
+CREATE INDEX idx_todo_comments_todo_id ON todo_comments (todo_id, created_at);
+
+CREATE INDEX idx_todo_comments_parent_id ON todo_comments (parent_id);
@@ -0,0 +1,3 @@
+-- Remove todo history
+DROP INDEX IF EXISTS idx_todo_events_todo_id;
+DROP TABLE IF EXISTS todo_events;
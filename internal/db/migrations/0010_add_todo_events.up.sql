@@ -0,0 +1,17 @@
+-- Create todo_events table, an append-only history of the changes made to todos.
+-- Rows are kept when their todo is deleted so that the deletion itself stays on record.
+CREATE TABLE
+    todo_events (
+        id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+        todo_id UUID NOT NULL,
+        actor_id UUID REFERENCES user_account (user_id) ON DELETE SET NULL,
+        action VARCHAR(20) NOT NULL CHECK (action IN ('created', 'updated', 'deleted')),
+        field VARCHAR(50),
+        old_value TEXT,
+        new_value TEXT,
+        created_at TIMESTAMP
+        WITH
+            TIME ZONE DEFAULT NOW ()
+    );
+
+CREATE INDEX idx_todo_events_todo_id ON todo_events (todo_id, created_at DESC);
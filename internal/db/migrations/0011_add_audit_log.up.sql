@@ -0,0 +1,20 @@
+-- Create audit_log table recording security relevant account events
+CREATE TABLE
+    audit_log (
+        id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+        user_id UUID REFERENCES user_account (user_id) ON DELETE SET NULL,
+        event_type VARCHAR(40) NOT NULL,
+        email_address VARCHAR(100),
+        ip_address VARCHAR(45),
+        user_agent TEXT,
+        details TEXT,
+        created_at TIMESTAMP
+        WITH
+            TIME ZONE DEFAULT NOW ()
+    );
+
+CREATE INDEX idx_audit_log_user_id ON audit_log (user_id, created_at DESC);
+
+CREATE INDEX idx_audit_log_event_type ON audit_log (event_type, created_at DESC);
+
+CREATE INDEX idx_audit_log_created_at ON audit_log (created_at DESC);
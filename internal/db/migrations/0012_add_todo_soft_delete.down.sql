@@ -0,0 +1,8 @@
+-- Remove the trash, permanently deleting trashed todos
+DELETE FROM todo_events WHERE action = 'restored';
+ALTER TABLE todo_events
+DROP CONSTRAINT todo_events_action_check,
+ADD CONSTRAINT todo_events_action_check CHECK (action IN ('created', 'updated', 'deleted'));
+DELETE FROM todos WHERE deleted_at IS NOT NULL;
+DROP INDEX IF EXISTS idx_todos_deleted_at;
+ALTER TABLE todos DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,14 @@
+-- Deleted todos are kept in the trash until they are restored or purged
+ALTER TABLE todos
+ADD COLUMN deleted_at TIMESTAMP
+WITH
+    TIME ZONE;
+
+CREATE INDEX idx_todos_deleted_at ON todos (deleted_at)
+WHERE
+    deleted_at IS NOT NULL;
+
+-- Record restores in the todo history
+ALTER TABLE todo_events
+DROP CONSTRAINT todo_events_action_check,
+ADD CONSTRAINT todo_events_action_check CHECK (action IN ('created', 'updated', 'deleted', 'restored'));
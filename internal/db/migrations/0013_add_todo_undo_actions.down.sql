@@ -0,0 +1,3 @@
+-- Remove todo undo actions
+DROP INDEX IF EXISTS idx_todo_undo_actions_user_id;
+DROP TABLE IF EXISTS todo_undo_actions;
@@ -0,0 +1,17 @@
+-- Create todo_undo_actions table recording the todos changed by a destructive action,
+-- so the action can be reversed with its undo token until the token expires.
+CREATE TABLE
+    todo_undo_actions (
+        id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+        user_id UUID NOT NULL REFERENCES user_account (user_id) ON DELETE CASCADE,
+        action VARCHAR(20) NOT NULL CHECK (action IN ('delete', 'complete')),
+        todo_ids UUID[] NOT NULL,
+        expires_at TIMESTAMP
+        WITH
+            TIME ZONE NOT NULL,
+            created_at TIMESTAMP
+        WITH
+            TIME ZONE DEFAULT NOW ()
+    );
+
+CREATE INDEX idx_todo_undo_actions_user_id ON todo_undo_actions (user_id, expires_at);
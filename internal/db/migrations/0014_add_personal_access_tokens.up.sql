@@ -0,0 +1,20 @@
+-- Create personal_access_tokens table for clients that cannot log in interactively, such as CalDAV clients.
+-- Only the SHA-256 hash of each token is stored.
+CREATE TABLE
+    personal_access_tokens (
+        id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+        user_id UUID NOT NULL REFERENCES user_account (user_id) ON DELETE CASCADE,
+        name VARCHAR(100) NOT NULL,
+        token_hash VARCHAR(64) NOT NULL UNIQUE,
+        last_used_at TIMESTAMP
+        WITH
+            TIME ZONE,
+            expires_at TIMESTAMP
+        WITH
+            TIME ZONE,
+            created_at TIMESTAMP
+        WITH
+            TIME ZONE DEFAULT NOW ()
+    );
+
+CREATE INDEX idx_personal_access_tokens_user_id ON personal_access_tokens (user_id);
@@ -0,0 +1,30 @@
+-- Create data_exports table for account data exports, which are assembled in the background
+-- and kept until the download link sent by email expires.
+CREATE TABLE
+    data_exports (
+        id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+        user_id UUID NOT NULL REFERENCES user_account (user_id) ON DELETE CASCADE,
+        status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'ready', 'failed')),
+        archive BYTEA,
+        size_bytes BIGINT,
+        attempts INT NOT NULL DEFAULT 0,
+        last_error TEXT,
+        claimed_until TIMESTAMP
+        WITH
+            TIME ZONE,
+            expires_at TIMESTAMP
+        WITH
+            TIME ZONE,
+            completed_at TIMESTAMP
+        WITH
+            TIME ZONE,
+            created_at TIMESTAMP
+        WITH
+            TIME ZONE DEFAULT NOW ()
+    );
+
+CREATE INDEX idx_data_exports_user_id ON data_exports (user_id);
+
+CREATE INDEX idx_data_exports_pending ON data_exports (created_at)
+WHERE
+    status = 'pending';
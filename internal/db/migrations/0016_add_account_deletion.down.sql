@@ -0,0 +1,9 @@
+-- Remove scheduled account deletion
+ALTER TABLE user_account_external
+DROP CONSTRAINT user_account_external_user_id_fkey,
+ADD CONSTRAINT user_account_external_user_id_fkey FOREIGN KEY (user_id) REFERENCES user_account (user_id);
+
+DROP INDEX IF EXISTS idx_user_account_deletion_scheduled_at;
+
+ALTER TABLE user_account
+DROP COLUMN IF EXISTS deletion_scheduled_at;
@@ -0,0 +1,14 @@
+-- Accounts scheduled for deletion are purged once deletion_scheduled_at has passed, unless the user logs in before
+ALTER TABLE user_account
+ADD COLUMN deletion_scheduled_at TIMESTAMP
+WITH
+    TIME ZONE;
+
+CREATE INDEX idx_user_account_deletion_scheduled_at ON user_account (deletion_scheduled_at)
+WHERE
+    deletion_scheduled_at IS NOT NULL;
+
+-- Linked providers were the only data that kept accounts from being deleted
+ALTER TABLE user_account_external
+DROP CONSTRAINT user_account_external_user_id_fkey,
+ADD CONSTRAINT user_account_external_user_id_fkey FOREIGN KEY (user_id) REFERENCES user_account (user_id) ON DELETE CASCADE;
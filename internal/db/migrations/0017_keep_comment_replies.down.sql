@@ -0,0 +1,4 @@
+-- Delete replies along with their parent comment again
+ALTER TABLE todo_comments
+DROP CONSTRAINT todo_comments_parent_id_fkey,
+ADD CONSTRAINT todo_comments_parent_id_fkey FOREIGN KEY (parent_id) REFERENCES todo_comments (id) ON DELETE CASCADE;
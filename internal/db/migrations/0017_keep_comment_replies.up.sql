@@ -0,0 +1,4 @@
+-- Replies outlive their parent comment, whose deletion re-parents them instead of deleting them
+ALTER TABLE todo_comments
+DROP CONSTRAINT todo_comments_parent_id_fkey,
+ADD CONSTRAINT todo_comments_parent_id_fkey FOREIGN KEY (parent_id) REFERENCES todo_comments (id) ON DELETE SET NULL;
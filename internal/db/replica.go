@@ -0,0 +1,193 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-backend-todo/internal/config"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// replicaPingTimeout bounds each health check of a replica
+const replicaPingTimeout = 2 * time.Second
+
+// primaryKey is the type of PrimaryKey, unexported to avoid collisions
+type primaryKey struct{}
+
+// PrimaryKey is the context key that pins reads to the primary when its value is true.
+// Fiber locals are visible as values of the request context, so it can be set with c.Locals.
+var PrimaryKey = primaryKey{}
+
+// WithPrimary returns a context whose reads go to the primary
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, PrimaryKey, true)
+}
+
+// replica is a read replica with the result of its last health check
+type replica struct {
+	name    string
+	pool    *pgxpool.Pool
+	healthy atomic.Bool
+}
+
+// Router sends reads that tolerate replication lag to healthy replicas and everything else to the primary
+type Router struct {
+	primary  *pgxpool.Pool
+	replicas []*replica
+	next     atomic.Uint64
+
+	window       time.Duration
+	mu           sync.Mutex
+	recentWrites map[uuid.UUID]time.Time
+}
+
+// OpenRouter creates a router over the primary pool and pools for the replicas configured in cfg.
+// Replicas that cannot be reached yet are skipped until a health check succeeds.
+func OpenRouter(ctx context.Context, cfg *config.Config, primary *pgxpool.Pool) (*Router, error) {
+	router := &Router{
+		primary:      primary,
+		window:       time.Duration(cfg.Database.ReadYourWritesWindow) * time.Second,
+		recentWrites: make(map[uuid.UUID]time.Time),
+	}
+
+	for i, connString := range cfg.Database.ReplicaURLs {
+		poolConfig, err := newPoolConfig(cfg, connString)
+		if err != nil {
+			router.Close()
+			return nil, fmt.Errorf("replica %d: %w", i+1, err)
+		}
+
+		pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+		if err != nil {
+			router.Close()
+			return nil, fmt.Errorf("failed to create pool for replica %d: %w", i+1, err)
+		}
+
+		name := fmt.Sprintf("%s:%d", poolConfig.ConnConfig.Host, poolConfig.ConnConfig.Port)
+		router.replicas = append(router.replicas, &replica{name: name, pool: pool})
+	}
+
+	if err := router.CheckReplicas(ctx); err != nil {
+		log.Println("Warning:", err)
+	}
+	return router, nil
+}
+
+// HasReplicas reports whether any replica is configured
+func (r *Router) HasReplicas() bool {
+	return len(r.replicas) > 0
+}
+
+// Pools returns the primary and replica pools by name, for exporting their statistics
+func (r *Router) Pools() map[string]*pgxpool.Pool {
+	pools := map[string]*pgxpool.Pool{"primary": r.primary}
+	for _, replica := range r.replicas {
+		pools[replica.name] = replica.pool
+	}
+	return pools
+}
+
+// Reader returns a connection for a read that tolerates replication lag.
+// That is the transaction of ctx, the primary when ctx is pinned to it, and otherwise a healthy replica in turn.
+func (r *Router) Reader(ctx context.Context) Querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	if len(r.replicas) == 0 || usePrimary(ctx) {
+		return r.primary
+	}
+
+	start := r.next.Add(1)
+	for i := range r.replicas {
+		candidate := r.replicas[(start+uint64(i))%uint64(len(r.replicas))]
+		if candidate.healthy.Load() {
+			return candidate.pool
+		}
+	}
+	return r.primary
+}
+
+// ReadYourWritesWindow returns how long the reads of a user go to the primary after they changed something,
+// zero when there is nothing to pin because no replica is configured
+func (r *Router) ReadYourWritesWindow() time.Duration {
+	if len(r.replicas) == 0 || r.window <= 0 {
+		return 0
+	}
+	return r.window
+}
+
+// MarkWrite records that a user changed something, so their reads in this process go to the primary until replicas caught up
+func (r *Router) MarkWrite(userID uuid.UUID) {
+	if len(r.replicas) == 0 || r.window <= 0 {
+		return
+	}
+
+	r.mu.Lock()
+	r.recentWrites[userID] = time.Now()
+	r.mu.Unlock()
+}
+
+// WroteRecently reports whether a user changed something within the read-your-writes window
+func (r *Router) WroteRecently(userID uuid.UUID) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	wroteAt, ok := r.recentWrites[userID]
+	return ok && time.Since(wroteAt) < r.window
+}
+
+// CheckReplicas pings every replica and routes reads only to those that answer.
+// It also forgets writes that left the read-your-writes window.
+func (r *Router) CheckReplicas(ctx context.Context) error {
+	r.mu.Lock()
+	for userID, wroteAt := range r.recentWrites {
+		if time.Since(wroteAt) >= r.window {
+			delete(r.recentWrites, userID)
+		}
+	}
+	r.mu.Unlock()
+
+	unhealthy := 0
+	for _, replica := range r.replicas {
+		pingCtx, cancel := context.WithTimeout(ctx, replicaPingTimeout)
+		err := replica.pool.Ping(pingCtx)
+		cancel()
+
+		healthy := err == nil
+		if replica.healthy.Swap(healthy) != healthy {
+			if healthy {
+				log.Printf("Replica %s is healthy, routing reads to it", replica.name)
+			} else {
+				log.Printf("Replica %s is unhealthy, routing its reads to the primary: %v", replica.name, err)
+			}
+		}
+		if !healthy {
+			unhealthy++
+		}
+	}
+
+	if unhealthy > 0 {
+		return fmt.Errorf("%d of %d replicas are unhealthy", unhealthy, len(r.replicas))
+	}
+	return nil
+}
+
+// Close closes the replica pools. The primary pool is owned by the caller.
+func (r *Router) Close() {
+	for _, replica := range r.replicas {
+		replica.pool.Close()
+	}
+}
+
+// usePrimary reports whether ctx pins reads to the primary
+func usePrimary(ctx context.Context) bool {
+	pinned, _ := ctx.Value(PrimaryKey).(bool)
+	return pinned
+}
@@ -0,0 +1,111 @@
+-- Sample data for local development, applied with the seed command.
+-- Every statement skips rows that already exist, so seeding twice is harmless.
+
+-- Create sample data for users
+INSERT INTO
+    user_account (
+        user_name,
+        user_role,
+        password_hash,
+        email_address,
+        email_validation_status
+    )
+VALUES
+    (
+        'BaoNguyxn',
+        'admin',
+        'hashed_password',
+        'admin@example.com',
+        'confirmed'
+    ) ON CONFLICT (user_name) DO NOTHING;
+
+-- Create sample data for external providers
+INSERT INTO
+    external_providers (provider_name, ws_endpoint)
+SELECT
+    provider_name,
+    ws_endpoint
+FROM
+    (
+        VALUES
+            (
+                'Google',
+                'https://accounts.google.com/o/oauth2/auth'
+            ),
+            (
+                'GitHub',
+                'https://github.com/login/oauth/authorize'
+            )
+    ) AS p (provider_name, ws_endpoint)
+WHERE
+    NOT EXISTS (
+        SELECT
+            1
+        FROM
+            external_providers e
+        WHERE
+            e.provider_name = p.provider_name
+    );
+
+-- Create sample data for user_account_external
+INSERT INTO
+    user_account_external (
+        user_id,
+        external_provider_id,
+        external_provider_token
+    )
+SELECT
+    u.user_id,
+    e.external_provider_id,
+    t.token
+FROM
+    (
+        VALUES
+            ('Google', 'google_token_12345'),
+            ('GitHub', 'github_token_67890')
+    ) AS t (provider_name, token)
+    JOIN external_providers e ON e.provider_name = t.provider_name
+    JOIN user_account u ON u.user_name = 'BaoNguyxn' ON CONFLICT DO NOTHING;
+
+-- Create sample data for todos
+INSERT INTO
+    todos (title, deadline, completed, user_id)
+SELECT
+    t.title,
+    t.deadline,
+    t.completed,
+    u.user_id
+FROM
+    (
+        VALUES
+            (
+                'Buy groceries',
+                NOW () + INTERVAL '1 day',
+                FALSE
+            ),
+            (
+                'Complete project report',
+                NOW () + INTERVAL '2 days',
+                FALSE
+            ),
+            (
+                'Schedule dentist appointment',
+                NOW () + INTERVAL '5 days',
+                TRUE
+            ),
+            (
+                'Call mom',
+                NOW () + INTERVAL '3 days',
+                TRUE
+            )
+    ) AS t (title, deadline, completed)
+    JOIN user_account u ON u.user_name = 'BaoNguyxn'
+WHERE
+    NOT EXISTS (
+        SELECT
+            1
+        FROM
+            todos
+        WHERE
+            todos.user_id = u.user_id
+    );
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Querier is implemented by both the pool and a transaction, so repositories run the same queries inside and outside of one
+type Querier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+	// Begin starts a transaction on the pool, or a savepoint within a transaction
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// txKey is the context key of the transaction started by WithinTx
+type txKey struct{}
+
+// TxManager runs units of work spanning several repositories in one transaction
+type TxManager interface {
+	// WithinTx runs fn in a transaction carried by the ctx passed to it, committing when fn returns nil and rolling back otherwise.
+	// Called within a transaction, it runs fn in a savepoint of that transaction instead.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// txManager implementation of TxManager interface
+type txManager struct {
+	pool *pgxpool.Pool
+}
+
+// NewTxManager creates a new instance of transaction manager
+func NewTxManager(pool *pgxpool.Pool) TxManager {
+	return &txManager{pool: pool}
+}
+
+// WithinTx begins a transaction, or a savepoint when ctx already carries one
+func (m *txManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return pgx.BeginFunc(ctx, Conn(ctx, m.pool), func(tx pgx.Tx) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// Conn returns the transaction ctx was given by WithinTx, or the pool outside of one
+func Conn(ctx context.Context, pool *pgxpool.Pool) Querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return pool
+}
@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// Values of the result label
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure" // rejected, e.g. wrong credentials or a revoked token
+	ResultError   = "error"   // could not be decided, e.g. a timeout or a database error
+	ResultSkipped = "skipped" // emails are only logged while SMTP is not configured
+)
+
+var (
+	// HTTPRequests counts handled requests by method, route template and status
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Number of handled HTTP requests by method, route template and status.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestDuration observes request latency by method, route template and status
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latency of handled HTTP requests by method, route template and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// Logins counts password logins by result
+	Logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_logins_total",
+		Help: "Number of password logins by result.",
+	}, []string{"result"})
+
+	// TokenRefreshes counts access token refreshes by result
+	TokenRefreshes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_token_refreshes_total",
+		Help: "Number of access token refreshes by result.",
+	}, []string{"result"})
+
+	// EmailsSent counts outgoing emails by result
+	EmailsSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "emails_sent_total",
+		Help: "Number of outgoing emails by result.",
+	}, []string{"result"})
+)
+
+// NewRegistry creates a registry of the metrics above, the statistics of the database pools and the Go runtime
+// and process metrics. Each app owns its registry, so setting up routes more than once registers nothing twice.
+func NewRegistry(pools map[string]*pgxpool.Pool) *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		Logins,
+		TokenRefreshes,
+		EmailsSent,
+		NewPoolCollector(pools),
+	)
+	return registry
+}
+
+// Outcome returns the result label of an operation that either succeeded or failed with err
+func Outcome(err error) string {
+	if err != nil {
+		return ResultFailure
+	}
+	return ResultSuccess
+}
@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	poolAcquiredConns = prometheus.NewDesc("db_pool_acquired_connections",
+		"Number of connections currently acquired from the pool.", []string{"pool"}, nil)
+	poolIdleConns = prometheus.NewDesc("db_pool_idle_connections",
+		"Number of idle connections in the pool.", []string{"pool"}, nil)
+	poolTotalConns = prometheus.NewDesc("db_pool_connections",
+		"Number of connections in the pool, acquired, idle or being opened.", []string{"pool"}, nil)
+	poolMaxConns = prometheus.NewDesc("db_pool_max_connections",
+		"Maximum number of connections of the pool.", []string{"pool"}, nil)
+	poolAcquires = prometheus.NewDesc("db_pool_acquires_total",
+		"Number of successful acquires from the pool.", []string{"pool"}, nil)
+	poolEmptyAcquires = prometheus.NewDesc("db_pool_empty_acquires_total",
+		"Number of acquires that waited for a connection because none was idle.", []string{"pool"}, nil)
+	poolAcquireDuration = prometheus.NewDesc("db_pool_acquire_duration_seconds_total",
+		"Total time spent acquiring connections from the pool.", []string{"pool"}, nil)
+	poolEmptyAcquireWait = prometheus.NewDesc("db_pool_empty_acquire_wait_seconds_total",
+		"Total time acquires waited for a connection because none was idle.", []string{"pool"}, nil)
+)
+
+// poolCollector exports the statistics of connection pools when scraped
+type poolCollector struct {
+	pools map[string]*pgxpool.Pool
+}
+
+// NewPoolCollector creates a collector of the pools, labelled with their names
+func NewPoolCollector(pools map[string]*pgxpool.Pool) prometheus.Collector {
+	return &poolCollector{pools: pools}
+}
+
+// Describe sends the descriptors of the pool metrics
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolAcquiredConns
+	ch <- poolIdleConns
+	ch <- poolTotalConns
+	ch <- poolMaxConns
+	ch <- poolAcquires
+	ch <- poolEmptyAcquires
+	ch <- poolAcquireDuration
+	ch <- poolEmptyAcquireWait
+}
+
+// Collect reads the current statistics of every pool
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	for name, pool := range c.pools {
+		stat := pool.Stat()
+		ch <- prometheus.MustNewConstMetric(poolAcquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()), name)
+		ch <- prometheus.MustNewConstMetric(poolIdleConns, prometheus.GaugeValue, float64(stat.IdleConns()), name)
+		ch <- prometheus.MustNewConstMetric(poolTotalConns, prometheus.GaugeValue, float64(stat.TotalConns()), name)
+		ch <- prometheus.MustNewConstMetric(poolMaxConns, prometheus.GaugeValue, float64(stat.MaxConns()), name)
+		ch <- prometheus.MustNewConstMetric(poolAcquires, prometheus.CounterValue, float64(stat.AcquireCount()), name)
+		ch <- prometheus.MustNewConstMetric(poolEmptyAcquires, prometheus.CounterValue, float64(stat.EmptyAcquireCount()), name)
+		ch <- prometheus.MustNewConstMetric(poolAcquireDuration, prometheus.CounterValue, stat.AcquireDuration().Seconds(), name)
+		ch <- prometheus.MustNewConstMetric(poolEmptyAcquireWait, prometheus.CounterValue, stat.EmptyAcquireWaitTime().Seconds(), name)
+	}
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditEventType enum for security relevant account events
+type AuditEventType string
+
+const (
+	AuditUserRegistered        AuditEventType = "user.registered"
+	AuditLoginSucceeded        AuditEventType = "login.succeeded"
+	AuditLoginFailed           AuditEventType = "login.failed"
+	AuditPasswordChanged       AuditEventType = "password.changed"
+	AuditPasswordChangeFailed  AuditEventType = "password.change_failed"
+	AuditPasswordReset         AuditEventType = "password.reset"
+	AuditTokenRefreshed        AuditEventType = "token.refreshed"
+	AuditTokenRefreshFailed    AuditEventType = "token.refresh_failed"
+	AuditEmailVerified         AuditEventType = "email.verified"
+	AuditSessionsRevoked       AuditEventType = "sessions.revoked"
+	AuditRoleChanged           AuditEventType = "role.changed"
+	AuditDataExportRequested   AuditEventType = "data_export.requested"
+	AuditDataExportDownloaded  AuditEventType = "data_export.downloaded"
+	AuditDeletionScheduled     AuditEventType = "account.deletion_scheduled"
+	AuditDeletionConfirmFailed AuditEventType = "account.deletion_confirm_failed"
+	AuditDeletionCancelled     AuditEventType = "account.deletion_cancelled"
+	AuditAccountDeleted        AuditEventType = "account.deleted"
+)
+
+// AuditEvent represents an entry of the security audit log
+type AuditEvent struct {
+	ID        uuid.UUID      `json:"id" db:"id"`
+	UserID    *uuid.UUID     `json:"user_id,omitempty" db:"user_id"` // nil when the account is unknown or deleted
+	EventType AuditEventType `json:"event_type" db:"event_type"`
+	Email     string         `json:"email,omitempty" db:"email_address"`
+	IPAddress string         `json:"ip_address,omitempty" db:"ip_address"`
+	UserAgent string         `json:"user_agent,omitempty" db:"user_agent"`
+	Details   string         `json:"details,omitempty" db:"details"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+}
+
+// AuditFilter represents the filters of an audit log query
+type AuditFilter struct {
+	UserID    *uuid.UUID
+	EventType AuditEventType
+	Email     string
+	IPAddress string
+	From      *time.Time
+	To        *time.Time
+	Limit     int
+	Offset    int
+}
+
+// ClientInfo identifies the client that sent the current request
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+}
+
+// clientInfoKey is the type of ClientInfoKey, unexported to avoid collisions
+type clientInfoKey struct{}
+
+// ClientInfoKey is the context key of the request's ClientInfo.
+// Fiber locals are visible as values of the request context, so it can be set with c.Locals.
+var ClientInfoKey = clientInfoKey{}
+
+// ClientInfoFromContext returns the client info of the request ctx belongs to, if any
+func ClientInfoFromContext(ctx context.Context) ClientInfo {
+	if info, ok := ctx.Value(ClientInfoKey).(ClientInfo); ok {
+		return info
+	}
+	return ClientInfo{}
+}
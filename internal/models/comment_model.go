@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TodoComment represents a markdown comment on a todo, optionally replying to another comment
+type TodoComment struct {
+	ID             uuid.UUID      `json:"id" db:"id"`
+	TodoID         uuid.UUID      `json:"todo_id" db:"todo_id"`
+	ParentID       *uuid.UUID     `json:"parent_id,omitempty" db:"parent_id"`
+	AuthorID       uuid.UUID      `json:"author_id" db:"author_id"`
+	AuthorUsername string         `json:"author_username" db:"user_name"`
+	Body           string         `json:"body" db:"body"`
+	CreatedAt      time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at" db:"updated_at"`
+	Replies        []*TodoComment `json:"replies,omitempty" db:"-"`
+}
+
+// CreateCommentRequest represents the request to comment on a todo
+type CreateCommentRequest struct {
+	Body     string     `json:"body" validate:"required,min=1,max=5000" example:"Blocked on @alice's review"`
+	ParentID *uuid.UUID `json:"parent_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// UpdateCommentRequest represents the request to edit a comment
+type UpdateCommentRequest struct {
+	Body string `json:"body" validate:"required,min=1,max=5000" example:"Unblocked, thanks @alice"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DataExportStatus represents the state of an account data export
+type DataExportStatus string
+
+const (
+	DataExportPending DataExportStatus = "pending"
+	DataExportReady   DataExportStatus = "ready"
+	DataExportFailed  DataExportStatus = "failed"
+)
+
+// DataExport represents a ZIP archive of everything stored about a user, assembled in the background
+type DataExport struct {
+	ID          uuid.UUID        `json:"id" db:"id"`
+	UserID      uuid.UUID        `json:"user_id" db:"user_id"`
+	Status      DataExportStatus `json:"status" db:"status"`
+	SizeBytes   *int64           `json:"size_bytes,omitempty" db:"size_bytes"`
+	Attempts    int              `json:"-" db:"attempts"`
+	ExpiresAt   *time.Time       `json:"expires_at,omitempty" db:"expires_at"` // set once the archive is ready
+	CompletedAt *time.Time       `json:"completed_at,omitempty" db:"completed_at"`
+	CreatedAt   time.Time        `json:"created_at" db:"created_at"`
+}
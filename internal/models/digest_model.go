@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DigestSettings represents the daily digest part of a user's preferences
+type DigestSettings struct {
+	UserID     uuid.UUID  `json:"user_id"`
+	Enabled    bool       `json:"enabled"`
+	SendHour   int        `json:"send_hour"`
+	Timezone   string     `json:"timezone"`
+	LastSentOn *time.Time `json:"last_sent_on,omitempty"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// UpdateDigestSettingsRequest represents the request to update the daily digest subscription
+type UpdateDigestSettingsRequest struct {
+	Enabled  bool   `json:"enabled" example:"true"`
+	SendHour *int   `json:"send_hour,omitempty" validate:"omitempty,min=0,max=23" example:"7"`
+	Timezone string `json:"timezone,omitempty" validate:"omitempty,timezone" example:"Asia/Ho_Chi_Minh"`
+}
+
+// DueDigest represents a claimed digest together with the recipient details
+type DueDigest struct {
+	UserID    uuid.UUID `db:"user_id"`
+	Username  string    `db:"user_name"`
+	Email     string    `db:"email_address"`
+	Timezone  string    `db:"timezone"`
+	WeekStart int       `db:"week_start"`
+}
+
+// TodoDigest holds the content of a daily digest email
+type TodoDigest struct {
+	Date     time.Time
+	Stats    *TodoStatsResponse
+	Overdue  []*Todo
+	Today    []*Todo
+	ThisWeek []*Todo
+}
+
+// IsEmpty reports whether the digest has nothing due to report
+func (d *TodoDigest) IsEmpty() bool {
+	return len(d.Overdue) == 0 && len(d.Today) == 0 && len(d.ThisWeek) == 0
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TodoEventType enum for todo lifecycle events
+type TodoEventType string
+
+const (
+	TodoCreatedEvent   TodoEventType = "todo.created"
+	TodoUpdatedEvent   TodoEventType = "todo.updated"
+	TodoCompletedEvent TodoEventType = "todo.completed"
+	TodoDeletedEvent   TodoEventType = "todo.deleted"
+	TodoRestoredEvent  TodoEventType = "todo.restored"
+)
+
+// TodoEventTypes lists the todo lifecycle events that can be subscribed to
+var TodoEventTypes = []TodoEventType{TodoCreatedEvent, TodoUpdatedEvent, TodoCompletedEvent, TodoDeletedEvent, TodoRestoredEvent}
+
+// TodoEvent describes a change to a todo, emitted after the change is stored
+type TodoEvent struct {
+	ID           uuid.UUID     `json:"id"`
+	Type         TodoEventType `json:"type"`
+	UserID       uuid.UUID     `json:"user_id"`
+	RecipientIDs []uuid.UUID   `json:"recipient_ids,omitempty"` // members of the todo's shared list
+	Todo         *Todo         `json:"todo"`
+	OccurredAt   time.Time     `json:"occurred_at"`
+}
+
+// Recipients returns the users the event is delivered to: the list members, or else the todo's user
+func (e TodoEvent) Recipients() []uuid.UUID {
+	if len(e.RecipientIDs) > 0 {
+		return e.RecipientIDs
+	}
+	return []uuid.UUID{e.UserID}
+}
+
+// NewTodoEvent creates an event of the given type for a todo
+func NewTodoEvent(eventType TodoEventType, todo *Todo) TodoEvent {
+	return TodoEvent{
+		ID:         uuid.New(),
+		Type:       eventType,
+		UserID:     todo.UserID,
+		Todo:       todo,
+		OccurredAt: time.Now(),
+	}
+}
@@ -0,0 +1,31 @@
+package models
+
+// ExportFormat enum for the file formats todos can be exported to
+type ExportFormat string
+
+const (
+	ExportFormatJSON ExportFormat = "json"
+	ExportFormatCSV  ExportFormat = "csv"
+	ExportFormatICS  ExportFormat = "ics"
+)
+
+// IsValid reports whether the format is supported
+func (f ExportFormat) IsValid() bool {
+	switch f {
+	case ExportFormatJSON, ExportFormatCSV, ExportFormatICS:
+		return true
+	}
+	return false
+}
+
+// ContentType returns the MIME type of an export in this format
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case ExportFormatCSV:
+		return "text/csv; charset=utf-8"
+	case ExportFormatICS:
+		return "text/calendar; charset=utf-8"
+	default:
+		return "application/json"
+	}
+}
@@ -0,0 +1,24 @@
+package models
+
+// HealthCheck is the result of checking one dependency of the service
+type HealthCheck struct {
+	Name  string `json:"name" example:"database"`
+	OK    bool   `json:"ok" example:"true"`
+	Error string `json:"error,omitempty" example:"connection refused"`
+}
+
+// Readiness reports whether an instance can serve requests
+type Readiness struct {
+	Ready    bool          `json:"ready" example:"true"`
+	Draining bool          `json:"draining" example:"false"` // set while the instance shuts down
+	Checks   []HealthCheck `json:"checks"`
+}
+
+// BuildInfo describes the binary an instance runs
+type BuildInfo struct {
+	Version    string `json:"version" example:"v1.4.0"` // module version, "(devel)" for local builds
+	GoVersion  string `json:"go_version" example:"go1.24.3"`
+	Revision   string `json:"revision,omitempty" example:"d352b41c0e5f"`
+	CommitTime string `json:"commit_time,omitempty" example:"2025-06-01T12:00:00Z"`
+	Modified   bool   `json:"modified"` // built from a working tree with uncommitted changes
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TodoHistoryAction enum for the kind of change recorded in a todo's history
+type TodoHistoryAction string
+
+const (
+	TodoHistoryCreated  TodoHistoryAction = "created"
+	TodoHistoryUpdated  TodoHistoryAction = "updated"
+	TodoHistoryDeleted  TodoHistoryAction = "deleted"
+	TodoHistoryRestored TodoHistoryAction = "restored"
+)
+
+// Fields of a todo whose changes are recorded in its history
+const (
+	TodoFieldTitle     = "title"
+	TodoFieldDeadline  = "deadline"
+	TodoFieldCompleted = "completed"
+	TodoFieldAssignee  = "assignee_id"
+	TodoFieldReminders = "reminders"
+)
+
+// TodoHistoryEvent represents one entry of a todo's append-only history.
+// Updates record one entry per changed field with its old and new value.
+type TodoHistoryEvent struct {
+	ID            uuid.UUID         `json:"id" db:"id"`
+	TodoID        uuid.UUID         `json:"todo_id" db:"todo_id"`
+	ActorID       *uuid.UUID        `json:"actor_id,omitempty" db:"actor_id"` // nil once the actor's account is deleted
+	ActorUsername *string           `json:"actor_username,omitempty" db:"user_name"`
+	Action        TodoHistoryAction `json:"action" db:"action"`
+	Field         *string           `json:"field,omitempty" db:"field"`
+	OldValue      *string           `json:"old_value,omitempty" db:"old_value"`
+	NewValue      *string           `json:"new_value,omitempty" db:"new_value"`
+	CreatedAt     time.Time         `json:"created_at" db:"created_at"`
+}
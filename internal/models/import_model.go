@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+)
+
+// ImportFormat enum for the file formats todos can be imported from
+type ImportFormat string
+
+const (
+	ImportFormatJSON    ImportFormat = "json"    // our own JSON export
+	ImportFormatCSV     ImportFormat = "csv"     // our own CSV export, or any CSV with a column mapping
+	ImportFormatTodoist ImportFormat = "todoist" // Todoist CSV template export
+	ImportFormatMSGraph ImportFormat = "msgraph" // Microsoft To Do tasks as returned by the Microsoft Graph API, To Do has no file export
+)
+
+// IsValid reports whether the format is supported
+func (f ImportFormat) IsValid() bool {
+	switch f {
+	case ImportFormatJSON, ImportFormatCSV, ImportFormatTodoist, ImportFormatMSGraph:
+		return true
+	}
+	return false
+}
+
+// Fields of a todo that CSV columns can be mapped to
+const (
+	ImportFieldTitle     = "title"
+	ImportFieldDeadline  = "deadline"
+	ImportFieldCompleted = "completed"
+)
+
+// ImportOptions controls how an uploaded file is imported
+type ImportOptions struct {
+	Format          ImportFormat
+	Mapping         map[string]string // todo field to CSV column, only used by the csv format
+	DefaultDeadline *time.Time        // deadline of rows that have none, which are rejected otherwise
+	DryRun          bool              // validate only, without importing
+}
+
+// ImportRowError describes why a row of an import was rejected, or what was left out of a row that was not
+type ImportRowError struct {
+	Row     int    `json:"row"` // 1-based line of a CSV file including its header, or position in a JSON array
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportResult reports the outcome of an import. Rows are only imported when none of them has errors.
+type ImportResult struct {
+	Format    ImportFormat     `json:"format"`
+	DryRun    bool             `json:"dry_run"`
+	TotalRows int              `json:"total_rows"`
+	ValidRows int              `json:"valid_rows"`
+	Imported  int              `json:"imported"`
+	Errors    []ImportRowError `json:"errors"`
+	Warnings  []ImportRowError `json:"warnings"` // values that were ignored in rows that are still imported
+}
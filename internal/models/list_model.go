@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ListRole enum for the role of a member in a shared list
+type ListRole string
+
+const (
+	ListRoleOwner  ListRole = "owner"
+	ListRoleEditor ListRole = "editor"
+	ListRoleViewer ListRole = "viewer"
+)
+
+// listRoleRanks orders roles from least to most privileged
+var listRoleRanks = map[ListRole]int{
+	ListRoleViewer: 1,
+	ListRoleEditor: 2,
+	ListRoleOwner:  3,
+}
+
+// Allows reports whether the role grants at least the required role
+func (r ListRole) Allows(required ListRole) bool {
+	return listRoleRanks[r] > 0 && listRoleRanks[r] >= listRoleRanks[required]
+}
+
+// TodoList represents a list of todos shared between its members
+type TodoList struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	OwnerID   uuid.UUID `json:"owner_id" db:"owner_id"`
+	Role      ListRole  `json:"role,omitempty" db:"role"` // role of the requesting user
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ListMember represents a user's membership in a list
+type ListMember struct {
+	ListID    uuid.UUID `json:"list_id" db:"list_id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	Username  string    `json:"username" db:"user_name"`
+	Email     string    `json:"email" db:"email_address"`
+	Role      ListRole  `json:"role" db:"role"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// ListInvitation represents an invitation to join a list sent by email
+type ListInvitation struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	ListID     uuid.UUID  `json:"list_id" db:"list_id"`
+	ListName   string     `json:"list_name,omitempty" db:"list_name"`
+	Email      string     `json:"email" db:"email_address"`
+	Role       ListRole   `json:"role" db:"role"`
+	TokenHash  string     `json:"-" db:"token_hash"`
+	InvitedBy  uuid.UUID  `json:"invited_by" db:"invited_by"`
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty" db:"accepted_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// CreateListRequest represents the request to create a list
+type CreateListRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=100" example:"Sprint 42 checklist"`
+}
+
+// UpdateListRequest represents the request to rename a list
+type UpdateListRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=100" example:"Sprint 43 checklist"`
+}
+
+// InviteListMemberRequest represents the request to invite a user to a list by email
+type InviteListMemberRequest struct {
+	Email string   `json:"email" validate:"required,email,max=100" example:"teammate@example.com"`
+	Role  ListRole `json:"role" validate:"required,oneof=editor viewer" example:"editor"`
+}
+
+// UpdateListMemberRequest represents the request to change the role of a member
+type UpdateListMemberRequest struct {
+	Role ListRole `json:"role" validate:"required,oneof=editor viewer" example:"viewer"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TodoSortOrder enum for the ordering of todo lists
+type TodoSortOrder string
+
+const (
+	SortCreatedAtDesc TodoSortOrder = "created_at_desc"
+	SortCreatedAtAsc  TodoSortOrder = "created_at_asc"
+	SortDeadlineAsc   TodoSortOrder = "deadline_asc"
+	SortDeadlineDesc  TodoSortOrder = "deadline_desc"
+	SortTitleAsc      TodoSortOrder = "title_asc"
+)
+
+// IsValid reports whether the sort order is one of the supported values
+func (o TodoSortOrder) IsValid() bool {
+	switch o {
+	case SortCreatedAtDesc, SortCreatedAtAsc, SortDeadlineAsc, SortDeadlineDesc, SortTitleAsc:
+		return true
+	}
+	return false
+}
+
+// UserPreferences represents per-user settings used when computing dates and sending notifications
+type UserPreferences struct {
+	UserID           uuid.UUID     `json:"user_id" db:"user_id"`
+	Timezone         string        `json:"timezone" db:"timezone"`
+	Locale           string        `json:"locale" db:"locale"`
+	WeekStart        int           `json:"week_start" db:"week_start"` // 0 = Sunday, 1 = Monday, ...
+	DefaultTodoSort  TodoSortOrder `json:"default_todo_sort" db:"default_todo_sort"`
+	EmailReminders   bool          `json:"email_reminders" db:"email_reminders"`
+	EmailDigest      bool          `json:"email_digest" db:"email_digest"`
+	DigestHour       int           `json:"digest_hour" db:"digest_hour"`
+	DigestLastSentOn *time.Time    `json:"-" db:"digest_last_sent_on"`
+	UpdatedAt        time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+// Location returns the time zone of the preferences, falling back to UTC
+func (p *UserPreferences) Location() *time.Location {
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// UpdatePreferencesRequest represents the request to replace the user's preferences
+type UpdatePreferencesRequest struct {
+	Timezone        string        `json:"timezone" validate:"required,timezone" example:"Asia/Ho_Chi_Minh"`
+	Locale          string        `json:"locale" validate:"required,bcp47_language_tag" example:"vi-VN"`
+	WeekStart       *int          `json:"week_start" validate:"required,min=0,max=6" example:"1"`
+	DefaultTodoSort TodoSortOrder `json:"default_todo_sort" validate:"required,oneof=created_at_desc created_at_asc deadline_asc deadline_desc title_asc" example:"deadline_asc"`
+	EmailReminders  *bool         `json:"email_reminders" validate:"required" example:"true"`
+	EmailDigest     *bool         `json:"email_digest" validate:"required" example:"true"`
+	DigestHour      *int          `json:"digest_hour" validate:"required,min=0,max=23" example:"7"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TodoReminder represents a scheduled deadline reminder for a todo
+type TodoReminder struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	TodoID        uuid.UUID  `json:"todo_id" db:"todo_id"`
+	OffsetMinutes int        `json:"offset_minutes" db:"offset_minutes"`
+	RemindAt      time.Time  `json:"remind_at" db:"remind_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty" db:"sent_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+}
+
+// DueReminder represents a claimed reminder together with the data needed to deliver it
+type DueReminder struct {
+	ReminderID    uuid.UUID `db:"id"`
+	TodoID        uuid.UUID `db:"todo_id"`
+	OffsetMinutes int       `db:"offset_minutes"`
+	TodoTitle     string    `db:"title"`
+	Deadline      time.Time `db:"deadline"`
+	UserID        uuid.UUID `db:"user_id"`
+	Username      string    `db:"user_name"`
+	Email         string    `db:"email_address"`
+	Timezone      string    `db:"timezone"`
+	EmailEnabled  bool      `db:"email_reminders"`
+}
+
+// UpdateRemindersRequest represents the request to replace a todo's reminder offsets
+type UpdateRemindersRequest struct {
+	Offsets []int `json:"offsets" validate:"max=10,dive,min=1,max=43200" example:"1440,60"`
+}
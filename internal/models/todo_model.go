@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,34 +10,69 @@ import (
 // Todo model represents a todo item
 // It includes fields for ID, title, description, completion status, timestamps, and associated user ID
 type Todo struct {
-	ID        uuid.UUID `json:"id" db:"id"`
-	Title     string    `json:"title" db:"title"`
-	Completed bool      `json:"completed" db:"completed"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
-	Deadline  time.Time `json:"deadline" db:"deadline"`
-	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	ID         uuid.UUID     `json:"id" db:"id"`
+	Title      string        `json:"title" db:"title"`
+	Completed  bool          `json:"completed" db:"completed"`
+	CreatedAt  time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at" db:"updated_at"`
+	Deadline   time.Time     `json:"deadline" db:"deadline"`
+	UserID     uuid.UUID     `json:"user_id" db:"user_id"`
+	ListID     *uuid.UUID    `json:"list_id,omitempty" db:"list_id"` // nil for personal todos
+	AssigneeID *uuid.UUID    `json:"assignee_id,omitempty" db:"assignee_id"`
+	Assignee   *TodoAssignee `json:"assignee,omitempty"`
+	DeletedAt  *time.Time    `json:"deleted_at,omitempty" db:"deleted_at"` // set while the todo is in the trash
+}
+
+// ETag identifies the current version of a todo for HTTP caching and CalDAV sync.
+// It uses microseconds, the precision updated_at is stored with.
+func (t *Todo) ETag() string {
+	return `"` + strconv.FormatInt(t.UpdatedAt.UnixMicro(), 36) + `"`
+}
+
+// TodoSyncState summarizes the todos visible to a user, changing whenever one of them is added, changed or deleted
+type TodoSyncState struct {
+	Count        int64      `json:"count"`
+	LastModified *time.Time `json:"last_modified,omitempty"`
+}
+
+// TodoAssignee represents the user a todo is assigned to
+type TodoAssignee struct {
+	UserID   uuid.UUID `json:"user_id" db:"user_id"`
+	Username string    `json:"username" db:"user_name"`
 }
 
 // CreateTodoRequest struct represents the request to create a new todo
 type CreateTodoRequest struct {
-	Title    string    `json:"title" validate:"required,min=1,max=255"`
-	Deadline time.Time `json:"deadline" validate:"required"`
+	ID              *uuid.UUID `json:"-"` // chosen by CalDAV clients, generated when nil
+	Title           string     `json:"title" validate:"required,min=1,max=255"`
+	Deadline        time.Time  `json:"deadline" validate:"required"`
+	ListID          *uuid.UUID `json:"list_id,omitempty"`                                                           // shared list to add the todo to, personal when omitted
+	AssigneeID      *uuid.UUID `json:"assignee_id,omitempty"`                                                       // must be able to edit the todo
+	ReminderOffsets []int      `json:"reminder_offsets,omitempty" validate:"omitempty,max=10,dive,min=1,max=43200"` // minutes before deadline, defaults apply when omitted
 }
 
 // UpdateTodoRequest struct represents the request to update an existing todo
 type UpdateTodoRequest struct {
-	Title     *string    `json:"title,omitempty" validate:"omitempty,min=1,max=255"`
-	Deadline  *time.Time `json:"deadline,omitempty" validate:"omitempty"`
-	Completed *bool      `json:"completed,omitempty"`
+	Title           *string    `json:"title,omitempty" validate:"omitempty,min=1,max=255"`
+	Deadline        *time.Time `json:"deadline,omitempty" validate:"omitempty"`
+	Completed       *bool      `json:"completed,omitempty"`
+	ReminderOffsets []int      `json:"reminder_offsets,omitempty" validate:"omitempty,max=10,dive,min=1,max=43200"` // replaces existing reminders when provided
+}
+
+// AssignTodoRequest represents the request to assign a todo, or unassign it with a null assignee_id
+type AssignTodoRequest struct {
+	AssigneeID *uuid.UUID `json:"assignee_id"`
 }
 
 // TodoFilter struct represents the filter for querying todos
 type TodoFilter struct {
-	UserID    uuid.UUID `json:"user_id"`
-	Completed *bool     `json:"completed,omitempty"`
-	Limit     int       `json:"limit"`
-	Offset    int       `json:"offset"`
+	UserID     uuid.UUID     `json:"user_id"`
+	ListID     *uuid.UUID    `json:"list_id,omitempty"` // restricts results to one list instead of every visible todo
+	AssigneeID *uuid.UUID    `json:"assignee_id,omitempty"`
+	Completed  *bool         `json:"completed,omitempty"`
+	Sort       TodoSortOrder `json:"sort,omitempty"`
+	Limit      int           `json:"limit"`
+	Offset     int           `json:"offset"`
 }
 
 // TodoListResponse represents paginated todo list response
@@ -58,6 +94,14 @@ type TodoStatsResponse struct {
 	ThisWeekTodos  int64 `json:"this_week_todos"`
 }
 
+// TodoStatsWindow holds the time boundaries used to compute todo statistics
+type TodoStatsWindow struct {
+	Now      time.Time // pending todos with an earlier deadline are overdue
+	DayStart time.Time
+	DayEnd   time.Time
+	WeekEnd  time.Time
+}
+
 // Priority enum for todo priority levels
 type TodoPriority string
 
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PersonalAccessTokenPrefix starts every personal access token, so that tokens can be told apart from passwords
+const PersonalAccessTokenPrefix = "tdp_"
+
+// PersonalAccessToken represents a long-lived token a user created for a client that cannot log in interactively
+type PersonalAccessToken struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	UserID     uuid.UUID  `json:"user_id" db:"user_id"`
+	Name       string     `json:"name" db:"name"`
+	Token      string     `json:"token,omitempty" db:"-"` // only returned when the token is created
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" db:"expires_at"` // nil for tokens that do not expire
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// CreatePersonalAccessTokenRequest represents the request to create a personal access token
+type CreatePersonalAccessTokenRequest struct {
+	Name          string `json:"name" validate:"required,min=1,max=100" example:"Thunderbird on laptop"`
+	ExpiresInDays *int   `json:"expires_in_days,omitempty" validate:"omitempty,min=1,max=3650" example:"365"` // never expires when omitted
+}
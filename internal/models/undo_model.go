@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UndoActionType enum for the destructive todo actions that can be undone
+type UndoActionType string
+
+const (
+	UndoActionDelete   UndoActionType = "delete"
+	UndoActionComplete UndoActionType = "complete"
+)
+
+// UndoAction records the todos changed by a destructive action until its undo window expires.
+// Its ID is the undo token handed to the client.
+type UndoAction struct {
+	ID        uuid.UUID      `json:"id" db:"id"`
+	UserID    uuid.UUID      `json:"user_id" db:"user_id"`
+	Action    UndoActionType `json:"action" db:"action"`
+	TodoIDs   []uuid.UUID    `json:"todo_ids" db:"todo_ids"`
+	ExpiresAt time.Time      `json:"expires_at" db:"expires_at"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+}
+
+// UndoToken is returned by destructive endpoints to reverse the action within the undo window
+type UndoToken struct {
+	UndoToken uuid.UUID `json:"undo_token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// UndoRequest represents the request body to undo a destructive action
+type UndoRequest struct {
+	UndoToken uuid.UUID `json:"undo_token" validate:"required"`
+}
+
+// UndoResult lists the todos brought back to their prior state by an undo
+type UndoResult struct {
+	Action UndoActionType `json:"action"`
+	Todos  []*Todo        `json:"todos"`
+}
+
+// BulkCompleteRequest represents the request body to mark several todos as completed
+type BulkCompleteRequest struct {
+	IDs []uuid.UUID `json:"ids" validate:"required,min=1,max=100"`
+}
@@ -71,6 +71,18 @@ type UserProfile struct {
 	TokenVersion int                       `json:"-" db:"token_version"`
 	CreatedAt    time.Time                 `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time                 `json:"updated_at" db:"updated_at"`
+
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty" db:"deletion_scheduled_at"` // set while the account awaits deletion
+}
+
+// DeleteAccountRequest represents the request to delete the current account, confirmed with its password
+type DeleteAccountRequest struct {
+	Password string `json:"password" validate:"required" example:"Securep@ssword123"`
+}
+
+// AccountDeletion describes a scheduled account deletion
+type AccountDeletion struct {
+	DeletionScheduledAt time.Time `json:"deletion_scheduled_at"`
 }
 
 // UpdateProfileRequest represents profile update request
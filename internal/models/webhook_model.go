@@ -0,0 +1,71 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookTestEvent is the event type sent by the "send test event" endpoint
+const WebhookTestEvent TodoEventType = "webhook.test"
+
+// Webhook represents a user-registered endpoint subscribed to todo events
+type Webhook struct {
+	ID          uuid.UUID       `json:"id" db:"id"`
+	UserID      uuid.UUID       `json:"user_id" db:"user_id"`
+	URL         string          `json:"url" db:"url"`
+	Secret      string          `json:"secret,omitempty" db:"secret"` // only returned when the webhook is created
+	Events      []TodoEventType `json:"events" db:"events"`
+	Description string          `json:"description" db:"description"`
+	Active      bool            `json:"active" db:"active"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// CreateWebhookRequest represents the request to register a webhook
+type CreateWebhookRequest struct {
+	URL         string          `json:"url" validate:"required,url,max=2048" example:"https://example.com/hooks/todos"`
+	Events      []TodoEventType `json:"events" validate:"required,min=1,dive,oneof=todo.created todo.updated todo.completed todo.deleted todo.restored" example:"todo.created,todo.completed"`
+	Description string          `json:"description" validate:"max=255" example:"Post to #team-todos"`
+}
+
+// UpdateWebhookRequest represents the request to update a webhook
+type UpdateWebhookRequest struct {
+	URL         *string         `json:"url,omitempty" validate:"omitempty,url,max=2048"`
+	Events      []TodoEventType `json:"events,omitempty" validate:"omitempty,min=1,dive,oneof=todo.created todo.updated todo.completed todo.deleted todo.restored"`
+	Description *string         `json:"description,omitempty" validate:"omitempty,max=255"`
+	Active      *bool           `json:"active,omitempty"`
+}
+
+// WebhookDeliveryStatus enum for webhook delivery states
+type WebhookDeliveryStatus string
+
+const (
+	DeliveryPending   WebhookDeliveryStatus = "pending"
+	DeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	DeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery represents one event queued for, or delivered to, a webhook
+type WebhookDelivery struct {
+	ID             uuid.UUID             `json:"id" db:"id"`
+	WebhookID      uuid.UUID             `json:"webhook_id" db:"webhook_id"`
+	EventID        uuid.UUID             `json:"event_id" db:"event_id"`
+	EventType      TodoEventType         `json:"event_type" db:"event_type"`
+	Payload        json.RawMessage       `json:"payload" db:"payload"`
+	Status         WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts       int                   `json:"attempts" db:"attempts"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at" db:"next_attempt_at"`
+	ResponseStatus *int                  `json:"response_status,omitempty" db:"response_status"`
+	LastError      *string               `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt      time.Time             `json:"created_at" db:"created_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty" db:"delivered_at"`
+}
+
+// DueWebhookDelivery represents a claimed delivery together with its target endpoint
+type DueWebhookDelivery struct {
+	WebhookDelivery
+	URL    string `db:"url"`
+	Secret string `db:"secret"`
+}
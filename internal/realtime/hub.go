@@ -0,0 +1,112 @@
+package realtime
+
+import (
+	"sync"
+
+	"go-backend-todo/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Hub fans todo events out to the streams opened by each user on this instance
+type Hub struct {
+	mu          sync.RWMutex
+	subscribers map[uuid.UUID]map[*Subscription]struct{}
+	bufferSize  int
+	closed      bool
+}
+
+// Subscription receives the events of one user until it is closed
+type Subscription struct {
+	UserID uuid.UUID
+	events chan models.TodoEvent
+	hub    *Hub
+	once   sync.Once
+}
+
+// NewHub creates a hub whose subscriptions buffer up to bufferSize events
+func NewHub(bufferSize int) *Hub {
+	return &Hub{
+		subscribers: make(map[uuid.UUID]map[*Subscription]struct{}),
+		bufferSize:  bufferSize,
+	}
+}
+
+// Subscribe registers a subscription for the events of a user.
+// The events channel of a subscription created after Close is already closed.
+func (h *Hub) Subscribe(userID uuid.UUID) *Subscription {
+	sub := &Subscription{
+		UserID: userID,
+		events: make(chan models.TodoEvent, h.bufferSize),
+		hub:    h,
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		sub.once.Do(func() { close(sub.events) })
+		return sub
+	}
+
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[*Subscription]struct{})
+	}
+	h.subscribers[userID][sub] = struct{}{}
+	return sub
+}
+
+// Broadcast delivers an event to every subscription of its recipients without blocking.
+// A subscription whose buffer is full is closed so the client reconnects and reloads instead of silently missing events.
+func (h *Hub) Broadcast(event models.TodoEvent) {
+	h.mu.RLock()
+	var slow []*Subscription
+	for _, userID := range event.Recipients() {
+		for sub := range h.subscribers[userID] {
+			select {
+			case sub.events <- event:
+			default:
+				slow = append(slow, sub)
+			}
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, sub := range slow {
+		sub.Close()
+	}
+}
+
+// Close closes every subscription and rejects new ones
+func (h *Hub) Close() {
+	h.mu.Lock()
+	h.closed = true
+	subscribers := h.subscribers
+	h.subscribers = make(map[uuid.UUID]map[*Subscription]struct{})
+	h.mu.Unlock()
+
+	for _, subs := range subscribers {
+		for sub := range subs {
+			sub.once.Do(func() { close(sub.events) })
+		}
+	}
+}
+
+// Events returns the channel of events, closed when the subscription ends
+func (s *Subscription) Events() <-chan models.TodoEvent {
+	return s.events
+}
+
+// Close unregisters the subscription and closes its events channel
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	if subs := s.hub.subscribers[s.UserID]; subs != nil {
+		delete(subs, s)
+		if len(subs) == 0 {
+			delete(s.hub.subscribers, s.UserID)
+		}
+	}
+	s.hub.mu.Unlock()
+
+	s.once.Do(func() { close(s.events) })
+}
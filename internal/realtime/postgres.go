@@ -0,0 +1,131 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"go-backend-todo/internal/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// notifyChannel is the Postgres channel todo events are broadcast on
+	notifyChannel = "todo_stream"
+	// maxNotifyPayload stays below the 8000 byte limit Postgres puts on NOTIFY payloads
+	maxNotifyPayload = 7900
+	// maxListenBackoff caps the delay between reconnection attempts of the listener
+	maxListenBackoff = 30 * time.Second
+)
+
+// NotifyPublisher publishes todo events with pg_notify so they reach the hubs of all instances
+type NotifyPublisher struct {
+	db  *pgxpool.Pool
+	hub *Hub
+}
+
+// NewNotifyPublisher creates a publisher that broadcasts events through Postgres
+func NewNotifyPublisher(db *pgxpool.Pool, hub *Hub) *NotifyPublisher {
+	return &NotifyPublisher{db: db, hub: hub}
+}
+
+// Publish sends the event on the notify channel. The local hub receives it back through the Listener,
+// so it is only broadcast locally when the notification could not be sent.
+func (p *NotifyPublisher) Publish(ctx context.Context, event models.TodoEvent) error {
+	payload, err := encodeEvent(event)
+	if err != nil {
+		return err
+	}
+
+	if _, err := p.db.Exec(ctx, `SELECT pg_notify($1, $2)`, notifyChannel, payload); err != nil {
+		p.hub.Broadcast(event)
+		return fmt.Errorf("failed to notify todo event: %w", err)
+	}
+	return nil
+}
+
+// Listener forwards todo events received on the notify channel to the local hub
+type Listener struct {
+	db  *pgxpool.Pool
+	hub *Hub
+}
+
+// NewListener creates a listener feeding hub
+func NewListener(db *pgxpool.Pool, hub *Hub) *Listener {
+	return &Listener{db: db, hub: hub}
+}
+
+// Name returns the worker name used in logs
+func (l *Listener) Name() string {
+	return "stream-listener"
+}
+
+// Run listens until ctx is cancelled, reconnecting with backoff when the connection is lost.
+// The hub is closed on return so open streams end with the server.
+func (l *Listener) Run(ctx context.Context) error {
+	defer l.hub.Close()
+
+	backoff := time.Second
+	for {
+		started := time.Now()
+		err := l.listen(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if time.Since(started) > maxListenBackoff {
+			backoff = time.Second
+		}
+		log.Printf("Stream listener disconnected, retrying in %s: %v", backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxListenBackoff)
+	}
+}
+
+// listen holds a dedicated connection on the notify channel and broadcasts every notification
+func (l *Listener) listen(ctx context.Context) error {
+	poolConn, err := l.db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// The connection keeps LISTEN state, so it is taken out of the pool instead of being released
+	conn := poolConn.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+notifyChannel); err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var event models.TodoEvent
+		if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+			log.Println("Error decoding todo event notification:", err)
+			continue
+		}
+		l.hub.Broadcast(event)
+	}
+}
+
+// encodeEvent encodes an event as a notify payload
+func encodeEvent(event models.TodoEvent) (string, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+	if len(payload) > maxNotifyPayload {
+		return "", fmt.Errorf("todo event %s exceeds the notify payload limit", event.ID)
+	}
+	return string(payload), nil
+}
@@ -0,0 +1,16 @@
+package audit_repository
+
+import (
+	"context"
+
+	"go-backend-todo/internal/models"
+)
+
+// AuditRepository interface defines methods for the security audit log
+type AuditRepository interface {
+	// Create appends an event. When the event has no user but an email, the user is looked up by email.
+	Create(ctx context.Context, event *models.AuditEvent) error
+	// Query returns the events matching the filter, newest first
+	Query(ctx context.Context, filter models.AuditFilter) ([]*models.AuditEvent, error)
+	Count(ctx context.Context, filter models.AuditFilter) (int64, error)
+}
@@ -0,0 +1,135 @@
+package audit_repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"go-backend-todo/internal/db"
+	"go-backend-todo/internal/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// auditRepository implementation of AuditRepository interface
+type auditRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewAuditRepository create a new instance of audit repository
+func NewAuditRepository(db *pgxpool.Pool) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+// conn returns the transaction of ctx, or the pool outside of one
+func (r *auditRepository) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, r.db)
+}
+
+// Create inserts an audit event and fills in its ID and timestamp
+func (r *auditRepository) Create(ctx context.Context, event *models.AuditEvent) error {
+	query := `
+		INSERT INTO audit_log (user_id, event_type, email_address, ip_address, user_agent, details)
+		VALUES (
+			COALESCE($1, (SELECT user_id FROM user_account WHERE email_address = NULLIF($3, ''))),
+			$2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, '')
+		)
+		RETURNING id, user_id, created_at
+	`
+
+	err := r.conn(ctx).QueryRow(ctx, query,
+		event.UserID, event.EventType, event.Email, event.IPAddress, event.UserAgent, event.Details,
+	).Scan(&event.ID, &event.UserID, &event.CreatedAt)
+	if err != nil {
+		log.Println("Error creating audit event:", err)
+		return err
+	}
+
+	return nil
+}
+
+// Query retrieves the audit events matching the filter, newest first
+func (r *auditRepository) Query(ctx context.Context, filter models.AuditFilter) ([]*models.AuditEvent, error) {
+	where, args := auditWhereClause(filter)
+
+	query := fmt.Sprintf(`
+		SELECT id, user_id, event_type, COALESCE(email_address, ''), COALESCE(ip_address, ''),
+			COALESCE(user_agent, ''), COALESCE(details, ''), created_at
+		FROM audit_log
+		%s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		log.Println("Error querying audit log:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []*models.AuditEvent{}
+	for rows.Next() {
+		var event models.AuditEvent
+		err := rows.Scan(
+			&event.ID, &event.UserID, &event.EventType, &event.Email, &event.IPAddress,
+			&event.UserAgent, &event.Details, &event.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, &event)
+	}
+
+	return events, rows.Err()
+}
+
+// Count counts the audit events matching the filter
+func (r *auditRepository) Count(ctx context.Context, filter models.AuditFilter) (int64, error) {
+	where, args := auditWhereClause(filter)
+
+	var count int64
+	err := r.conn(ctx).QueryRow(ctx, `SELECT COUNT(*) FROM audit_log `+where, args...).Scan(&count)
+	if err != nil {
+		log.Println("Error counting audit log:", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// auditWhereClause builds the WHERE clause and arguments of a filter
+func auditWhereClause(filter models.AuditFilter) (string, []any) {
+	var conditions []string
+	var args []any
+
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.UserID != nil {
+		add("user_id = $%d", *filter.UserID)
+	}
+	if filter.EventType != "" {
+		add("event_type = $%d", filter.EventType)
+	}
+	if filter.Email != "" {
+		add("LOWER(email_address) = LOWER($%d)", filter.Email)
+	}
+	if filter.IPAddress != "" {
+		add("ip_address = $%d", filter.IPAddress)
+	}
+	if filter.From != nil {
+		add("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		add("created_at < $%d", *filter.To)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
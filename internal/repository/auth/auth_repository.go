@@ -4,13 +4,17 @@ import (
 	"context"
 	"time"
 	"go-backend-todo/internal/models"
+
+	"github.com/google/uuid"
 )
 
 type AuthRepository interface {
 	ValidateCredentials(ctx context.Context, email, password string) (*models.UserAccount, error)
-	VerifyEmail(ctx context.Context, token string) error
+	VerifyEmail(ctx context.Context, token string) (uuid.UUID, error)
 	RecoverPassword(ctx context.Context, email string) (*models.UserAccount, error)
-	ResetPassword(ctx context.Context, req *models.ResetPasswordRequest) error
+	ResetPassword(ctx context.Context, req *models.ResetPasswordRequest) (uuid.UUID, error)
 	Login(ctx context.Context, req *models.LoginRequest) (*models.UserProfile, error)
 	GetTokenCreationTime(ctx context.Context, token string, isVerifyToken bool) (time.Time, error)
+	// ClearExpiredTokens removes verification tokens generated before verifyBefore and recovery tokens generated before recoverBefore
+	ClearExpiredTokens(ctx context.Context, verifyBefore, recoverBefore time.Time) (int64, error)
 }
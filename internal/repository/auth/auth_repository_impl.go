@@ -2,6 +2,8 @@ package auth_repository
 
 import (
 	"context"
+	"errors"
+	"go-backend-todo/internal/db"
 	"go-backend-todo/internal/models"
 	"log"
 	"net/url"
@@ -10,6 +12,7 @@ import (
 	"go-backend-todo/internal/utils"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -24,6 +27,11 @@ func NewAuthRepository(db *pgxpool.Pool) AuthRepository {
 	}
 }
 
+// conn returns the transaction of ctx, or the pool outside of one
+func (a *authRepository) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, a.db)
+}
+
 func (a *authRepository) ValidateCredentials(ctx context.Context, email, password string) (*models.UserAccount, error) {
 	// Check if context is already cancelled/timed out
 	if ctx.Err() != nil {
@@ -37,7 +45,7 @@ func (a *authRepository) ValidateCredentials(ctx context.Context, email, passwor
 	var passwordHash string
 	var emailValidationStatus string
 
-	err := a.db.QueryRow(ctx, query, email).Scan(&userID, &userName, &passwordHash, &emailValidationStatus)
+	err := a.conn(ctx).QueryRow(ctx, query, email).Scan(&userID, &userName, &passwordHash, &emailValidationStatus)
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			log.Println("ValidateCredentials operation timed out")
@@ -64,35 +72,35 @@ func (a *authRepository) ValidateCredentials(ctx context.Context, email, passwor
 	}, nil
 }
 
-func (a *authRepository) VerifyEmail(ctx context.Context, token string) error {
+// VerifyEmail confirms the email address of the user the token was sent to and returns their ID
+func (a *authRepository) VerifyEmail(ctx context.Context, token string) (uuid.UUID, error) {
 	// Check if context is already cancelled/timed out
 	if ctx.Err() != nil {
-		return ctx.Err()
+		return uuid.Nil, ctx.Err()
 	}
 
-	query := "UPDATE user_account SET email_validation_status = 'confirmed'::email_validation_status_enum WHERE verification_token = $1;"
-	result, err := a.db.Exec(ctx, query, token)
+	query := "UPDATE user_account SET email_validation_status = 'confirmed'::email_validation_status_enum WHERE verification_token = $1 RETURNING user_id;"
+	var userID uuid.UUID
+	err := a.conn(ctx).QueryRow(ctx, query, token).Scan(&userID)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, utils.ErrInvalidCredentials("Invalid verification token")
+		}
 		// Check if error is due to context timeout/cancellation
 		if ctx.Err() == context.DeadlineExceeded {
 			log.Println("VerifyEmail operation timed out")
-			return utils.ErrTimeout("Email verification timed out")
+			return uuid.Nil, utils.ErrTimeout("Email verification timed out")
 		}
 		if ctx.Err() == context.Canceled {
 			log.Println("VerifyEmail operation was cancelled")
-			return utils.ErrInternalServerError("Email verification was cancelled")
+			return uuid.Nil, utils.ErrInternalServerError("Email verification was cancelled")
 		}
 
 		log.Println("Error verifying email:", err)
-		return utils.ErrInternalServerError("Failed to verify email")
+		return uuid.Nil, utils.ErrInternalServerError("Failed to verify email")
 	}
 
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		return utils.ErrInvalidCredentials("Invalid verification token")
-	}
-
-	return nil
+	return userID, nil
 }
 
 func (a *authRepository) GetTokenCreationTime(ctx context.Context, token string, isVerifyToken bool) (time.Time, error) {
@@ -114,7 +122,7 @@ func (a *authRepository) GetTokenCreationTime(ctx context.Context, token string,
 	}
 
 	var createdAt time.Time
-	err = a.db.QueryRow(ctx, query, decodedToken).Scan(&createdAt)
+	err = a.conn(ctx).QueryRow(ctx, query, decodedToken).Scan(&createdAt)
 	if err != nil {
 		// Check if error is due to context timeout/cancellation
 		if ctx.Err() == context.DeadlineExceeded {
@@ -144,7 +152,7 @@ func (a *authRepository) RecoverPassword(ctx context.Context, email string) (*mo
 	var userID uuid.UUID
 	var userRole string
 
-	err := a.db.QueryRow(ctx, query, email).Scan(&userID, &userRole)
+	err := a.conn(ctx).QueryRow(ctx, query, email).Scan(&userID, &userRole)
 	if err != nil {
 		// Check if error is due to context timeout/cancellation
 		if ctx.Err() == context.DeadlineExceeded {
@@ -166,42 +174,50 @@ func (a *authRepository) RecoverPassword(ctx context.Context, email string) (*mo
 	}, nil
 }
 
-func (a *authRepository) ResetPassword(ctx context.Context, req *models.ResetPasswordRequest) error {
+// ResetPassword sets the password of the user the recovery token was sent to, revokes their personal access tokens
+// and returns their ID
+func (a *authRepository) ResetPassword(ctx context.Context, req *models.ResetPasswordRequest) (uuid.UUID, error) {
 	// Check if context is already cancelled/timed out
 	if ctx.Err() != nil {
-		return ctx.Err()
+		return uuid.Nil, ctx.Err()
 	}
 
 	// Hash the new password first (local operation)
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
 	if err != nil {
 		log.Println("Error hashing password:", err)
-		return utils.ErrInternalServerError("Failed to process password")
+		return uuid.Nil, utils.ErrInternalServerError("Failed to process password")
 	}
 
-	query := "UPDATE user_account SET password_hash = $1 WHERE password_recovery_token = $2;"
-	result, err := a.db.Exec(ctx, query, string(hashedPassword), req.Token)
+	query := `
+		WITH reset AS (
+			UPDATE user_account SET password_hash = $1 WHERE password_recovery_token = $2 RETURNING user_id
+		), revoked AS (
+			DELETE FROM personal_access_tokens WHERE user_id IN (SELECT user_id FROM reset)
+		)
+		SELECT user_id FROM reset
+	`
+	var userID uuid.UUID
+	err = a.conn(ctx).QueryRow(ctx, query, string(hashedPassword), req.Token).Scan(&userID)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, utils.ErrInvalidCredentials("Invalid recovery token or token has expired")
+		}
 		// Check if error is due to context timeout/cancellation
 		if ctx.Err() == context.DeadlineExceeded {
 			log.Println("ResetPassword operation timed out")
-			return utils.ErrTimeout("Password reset timed out")
+			return uuid.Nil, utils.ErrTimeout("Password reset timed out")
 		}
 		if ctx.Err() == context.Canceled {
 			log.Println("ResetPassword operation was cancelled")
-			return utils.ErrInternalServerError("Password reset was cancelled")
+			return uuid.Nil, utils.ErrInternalServerError("Password reset was cancelled")
 		}
 
 		log.Println("Error resetting password:", err)
-		return utils.ErrInternalServerError("Failed to reset password")
+		return uuid.Nil, utils.ErrInternalServerError("Failed to reset password")
 	}
 
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		return utils.ErrInvalidCredentials("Invalid recovery token or token has expired")
-	}
-
-	return nil
+	return userID, nil
 }
 
 func (a *authRepository) Login(ctx context.Context, req *models.LoginRequest) (*models.UserProfile, error) {
@@ -218,7 +234,7 @@ func (a *authRepository) Login(ctx context.Context, req *models.LoginRequest) (*
 	var userRole string
 	var emailValidationStatus string
 
-	err := a.db.QueryRow(ctx, query, req.Email).Scan(&userID, &userName, &userRole, &passwordHash, &emailValidationStatus)
+	err := a.conn(ctx).QueryRow(ctx, query, req.Email).Scan(&userID, &userName, &userRole, &passwordHash, &emailValidationStatus)
 	if err != nil {
 		// Check if error is due to context timeout/cancellation
 		if ctx.Err() == context.DeadlineExceeded {
@@ -230,8 +246,12 @@ func (a *authRepository) Login(ctx context.Context, req *models.LoginRequest) (*
 			return nil, utils.ErrInternalServerError("Login operation was cancelled")
 		}
 
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, utils.ErrInvalidCredentials("Invalid email or password")
+		}
+
 		log.Println("Error during login:", err)
-		return nil, utils.ErrInvalidCredentials("Invalid email or password")
+		return nil, utils.ErrInternalServerError("Failed to log in")
 	}
 
 	// Password comparison (local operation, no need for context timeout check)
@@ -249,3 +269,23 @@ func (a *authRepository) Login(ctx context.Context, req *models.LoginRequest) (*
 		Status:   models.EmailValidationStatusEnum(emailValidationStatus),
 	}, nil
 }
+
+// ClearExpiredTokens removes expired verification and recovery tokens so they cannot be looked up anymore
+func (a *authRepository) ClearExpiredTokens(ctx context.Context, verifyBefore, recoverBefore time.Time) (int64, error) {
+	query := `
+		UPDATE user_account
+		SET verification_token = CASE WHEN verification_token_generation_time < $1 THEN NULL ELSE verification_token END,
+			verification_token_generation_time = CASE WHEN verification_token_generation_time < $1 THEN NULL ELSE verification_token_generation_time END,
+			password_recovery_token = CASE WHEN password_recovery_token_generation_time < $2 THEN NULL ELSE password_recovery_token END,
+			password_recovery_token_generation_time = CASE WHEN password_recovery_token_generation_time < $2 THEN NULL ELSE password_recovery_token_generation_time END
+		WHERE (verification_token IS NOT NULL AND verification_token_generation_time < $1)
+			OR (password_recovery_token IS NOT NULL AND password_recovery_token_generation_time < $2)
+	`
+
+	result, err := a.conn(ctx).Exec(ctx, query, verifyBefore, recoverBefore)
+	if err != nil {
+		log.Println("Error clearing expired tokens:", err)
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,22 @@
+package comment_repository
+
+import (
+	"context"
+
+	"go-backend-todo/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// CommentRepository interface defines methods for interacting with todo comment data
+type CommentRepository interface {
+	Create(ctx context.Context, comment *models.TodoComment) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.TodoComment, error)
+	// GetByTodoID returns every comment of a todo, oldest first
+	GetByTodoID(ctx context.Context, todoID uuid.UUID) ([]*models.TodoComment, error)
+	// GetByAuthorID returns every comment written by a user, oldest first
+	GetByAuthorID(ctx context.Context, authorID uuid.UUID) ([]*models.TodoComment, error)
+	// Update and Delete only affect comments written by authorID and report whether one was found
+	Update(ctx context.Context, id, authorID uuid.UUID, body string) (*models.TodoComment, error)
+	Delete(ctx context.Context, id, authorID uuid.UUID) (bool, error)
+}
@@ -0,0 +1,190 @@
+package comment_repository
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"go-backend-todo/internal/db"
+	"go-backend-todo/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// commentRepository implementation of CommentRepository interface
+type commentRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewCommentRepository create a new instance of comment repository
+func NewCommentRepository(db *pgxpool.Pool) CommentRepository {
+	return &commentRepository{db: db}
+}
+
+// conn returns the transaction of ctx, or the pool outside of one
+func (r *commentRepository) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, r.db)
+}
+
+const commentColumns = `c.id, c.todo_id, c.parent_id, c.author_id, u.user_name, c.body, c.created_at, c.updated_at`
+
+// scanComment scans a row selected with commentColumns
+func scanComment(row pgx.Row) (*models.TodoComment, error) {
+	var comment models.TodoComment
+	err := row.Scan(
+		&comment.ID, &comment.TodoID, &comment.ParentID, &comment.AuthorID, &comment.AuthorUsername,
+		&comment.Body, &comment.CreatedAt, &comment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// Create creates a new comment and fills in its ID, author name and timestamps
+func (r *commentRepository) Create(ctx context.Context, comment *models.TodoComment) error {
+	query := `
+		WITH c AS (
+			INSERT INTO todo_comments (todo_id, parent_id, author_id, body)
+			VALUES ($1, $2, $3, $4)
+			RETURNING *
+		)
+		SELECT ` + commentColumns + ` FROM c JOIN user_account u ON u.user_id = c.author_id
+	`
+
+	created, err := scanComment(r.conn(ctx).QueryRow(ctx, query, comment.TodoID, comment.ParentID, comment.AuthorID, comment.Body))
+	if err != nil {
+		log.Println("Error creating comment:", err)
+		return err
+	}
+
+	*comment = *created
+	return nil
+}
+
+// GetByID retrieves a comment, or nil if it does not exist
+func (r *commentRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.TodoComment, error) {
+	query := `SELECT ` + commentColumns + ` FROM todo_comments c JOIN user_account u ON u.user_id = c.author_id WHERE c.id = $1`
+
+	comment, err := scanComment(r.conn(ctx).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		log.Println("Error fetching comment:", err)
+		return nil, err
+	}
+	return comment, nil
+}
+
+// GetByTodoID retrieves all comments of a todo, oldest first
+func (r *commentRepository) GetByTodoID(ctx context.Context, todoID uuid.UUID) ([]*models.TodoComment, error) {
+	query := `
+		SELECT ` + commentColumns + `
+		FROM todo_comments c JOIN user_account u ON u.user_id = c.author_id
+		WHERE c.todo_id = $1
+		ORDER BY c.created_at ASC
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, todoID)
+	if err != nil {
+		log.Println("Error fetching comments:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []*models.TodoComment{}
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+
+	return comments, rows.Err()
+}
+
+// GetByAuthorID retrieves all comments written by a user, oldest first
+func (r *commentRepository) GetByAuthorID(ctx context.Context, authorID uuid.UUID) ([]*models.TodoComment, error) {
+	query := `
+		SELECT ` + commentColumns + `
+		FROM todo_comments c JOIN user_account u ON u.user_id = c.author_id
+		WHERE c.author_id = $1
+		ORDER BY c.created_at ASC
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, authorID)
+	if err != nil {
+		log.Println("Error fetching comments of author:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []*models.TodoComment{}
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+
+	return comments, rows.Err()
+}
+
+// Update replaces the body of a comment written by authorID, or returns nil if there is none
+func (r *commentRepository) Update(ctx context.Context, id, authorID uuid.UUID, body string) (*models.TodoComment, error) {
+	query := `
+		WITH c AS (
+			UPDATE todo_comments SET body = $3, updated_at = NOW()
+			WHERE id = $1 AND author_id = $2
+			RETURNING *
+		)
+		SELECT ` + commentColumns + ` FROM c JOIN user_account u ON u.user_id = c.author_id
+	`
+
+	comment, err := scanComment(r.conn(ctx).QueryRow(ctx, query, id, authorID, body))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		log.Println("Error updating comment:", err)
+		return nil, err
+	}
+	return comment, nil
+}
+
+// Delete deletes a comment written by authorID. Its replies, which may be written by other users,
+// move up to the parent of the deleted comment, or become top-level comments.
+func (r *commentRepository) Delete(ctx context.Context, id, authorID uuid.UUID) (bool, error) {
+	tx, err := r.conn(ctx).Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE todo_comments r
+		SET parent_id = c.parent_id
+		FROM todo_comments c
+		WHERE c.id = $1 AND c.author_id = $2 AND r.parent_id = c.id
+	`
+	if _, err := tx.Exec(ctx, query, id, authorID); err != nil {
+		log.Println("Error re-parenting comment replies:", err)
+		return false, err
+	}
+
+	result, err := tx.Exec(ctx, `DELETE FROM todo_comments WHERE id = $1 AND author_id = $2`, id, authorID)
+	if err != nil {
+		log.Println("Error deleting comment:", err)
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
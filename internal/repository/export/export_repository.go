@@ -0,0 +1,29 @@
+package export_repository
+
+import (
+	"context"
+	"time"
+
+	"go-backend-todo/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ExportRepository interface defines methods for account data exports
+type ExportRepository interface {
+	Create(ctx context.Context, export *models.DataExport) error
+	// GetActiveByUserID returns the export of a user that is pending or ready and unexpired, or nil if there is none
+	GetActiveByUserID(ctx context.Context, userID uuid.UUID) (*models.DataExport, error)
+
+	// ClaimPending leases up to limit pending exports for leaseFor and increments their attempt count.
+	// An export whose lease expires without being marked is picked up again by the next poll.
+	ClaimPending(ctx context.Context, now time.Time, leaseFor time.Duration, limit int) ([]*models.DataExport, error)
+	MarkReady(ctx context.Context, id uuid.UUID, archive []byte, expiresAt time.Time) error
+	// MarkFailed records a failed attempt, giving up on the export when failed is true
+	MarkFailed(ctx context.Context, id uuid.UUID, lastError string, failed bool) error
+
+	// GetArchive returns a ready, unexpired export with its archive, or nil if there is none
+	GetArchive(ctx context.Context, id uuid.UUID) (*models.DataExport, []byte, error)
+	// DeleteExpired removes exports whose link expired and failed exports older than before
+	DeleteExpired(ctx context.Context, now, before time.Time) (int64, error)
+}
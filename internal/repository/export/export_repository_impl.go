@@ -0,0 +1,194 @@
+package export_repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go-backend-todo/internal/db"
+	"go-backend-todo/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// exportRepository implementation of ExportRepository interface
+type exportRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewExportRepository create a new instance of export repository
+func NewExportRepository(db *pgxpool.Pool) ExportRepository {
+	return &exportRepository{db: db}
+}
+
+// conn returns the transaction of ctx, or the pool outside of one
+func (r *exportRepository) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, r.db)
+}
+
+// exportColumns are the columns scanned by scanExport, leaving out the archive
+const exportColumns = `id, user_id, status, size_bytes, attempts, expires_at, completed_at, created_at`
+
+// scanExport scans a row selected with exportColumns
+func scanExport(row pgx.Row) (*models.DataExport, error) {
+	var export models.DataExport
+	err := row.Scan(
+		&export.ID, &export.UserID, &export.Status, &export.SizeBytes, &export.Attempts,
+		&export.ExpiresAt, &export.CompletedAt, &export.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// Create inserts a pending export and fills in its ID, status and timestamp
+func (r *exportRepository) Create(ctx context.Context, export *models.DataExport) error {
+	query := `INSERT INTO data_exports (user_id) VALUES ($1) RETURNING ` + exportColumns
+
+	created, err := scanExport(r.conn(ctx).QueryRow(ctx, query, export.UserID))
+	if err != nil {
+		log.Println("Error creating data export:", err)
+		return err
+	}
+
+	*export = *created
+	return nil
+}
+
+// GetActiveByUserID retrieves the newest export of a user that is still being assembled or can be downloaded
+func (r *exportRepository) GetActiveByUserID(ctx context.Context, userID uuid.UUID) (*models.DataExport, error) {
+	query := `
+		SELECT ` + exportColumns + `
+		FROM data_exports
+		WHERE user_id = $1 AND (status = 'pending' OR (status = 'ready' AND expires_at > NOW()))
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	export, err := scanExport(r.conn(ctx).QueryRow(ctx, query, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		log.Println("Error fetching active data export:", err)
+		return nil, err
+	}
+	return export, nil
+}
+
+// ClaimPending leases pending exports by pushing claimed_until past the lease.
+// SKIP LOCKED lets concurrent workers pick disjoint batches.
+func (r *exportRepository) ClaimPending(ctx context.Context, now time.Time, leaseFor time.Duration, limit int) ([]*models.DataExport, error) {
+	query := `
+		WITH due AS (
+			SELECT id
+			FROM data_exports
+			WHERE status = 'pending' AND (claimed_until IS NULL OR claimed_until <= $1)
+			ORDER BY created_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE data_exports e
+		SET attempts = e.attempts + 1, claimed_until = $2
+		FROM due
+		WHERE e.id = due.id
+		RETURNING e.id, e.user_id, e.status, e.size_bytes, e.attempts, e.expires_at, e.completed_at, e.created_at
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, now, now.Add(leaseFor), limit)
+	if err != nil {
+		log.Println("Error claiming pending data exports:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var exports []*models.DataExport
+	for rows.Next() {
+		export, err := scanExport(rows)
+		if err != nil {
+			return nil, err
+		}
+		exports = append(exports, export)
+	}
+
+	return exports, rows.Err()
+}
+
+// MarkReady stores the archive of an export and starts its expiry
+func (r *exportRepository) MarkReady(ctx context.Context, id uuid.UUID, archive []byte, expiresAt time.Time) error {
+	query := `
+		UPDATE data_exports
+		SET status = 'ready', archive = $2, size_bytes = $3, expires_at = $4,
+			completed_at = NOW(), claimed_until = NULL, last_error = NULL
+		WHERE id = $1
+	`
+
+	_, err := r.conn(ctx).Exec(ctx, query, id, archive, int64(len(archive)), expiresAt)
+	if err != nil {
+		log.Println("Error marking data export as ready:", err)
+		return err
+	}
+	return nil
+}
+
+// MarkFailed records the error of an attempt and releases the lease, so the export is retried unless failed is set
+func (r *exportRepository) MarkFailed(ctx context.Context, id uuid.UUID, lastError string, failed bool) error {
+	query := `
+		UPDATE data_exports
+		SET last_error = $2, claimed_until = NULL,
+			status = CASE WHEN $3 THEN 'failed' ELSE status END,
+			completed_at = CASE WHEN $3 THEN NOW() ELSE completed_at END
+		WHERE id = $1
+	`
+
+	_, err := r.conn(ctx).Exec(ctx, query, id, lastError, failed)
+	if err != nil {
+		log.Println("Error marking data export as failed:", err)
+		return err
+	}
+	return nil
+}
+
+// GetArchive retrieves a downloadable export together with its archive
+func (r *exportRepository) GetArchive(ctx context.Context, id uuid.UUID) (*models.DataExport, []byte, error) {
+	query := `
+		SELECT ` + exportColumns + `, archive
+		FROM data_exports
+		WHERE id = $1 AND status = 'ready' AND expires_at > NOW()
+	`
+
+	var export models.DataExport
+	var archive []byte
+	err := r.conn(ctx).QueryRow(ctx, query, id).Scan(
+		&export.ID, &export.UserID, &export.Status, &export.SizeBytes, &export.Attempts,
+		&export.ExpiresAt, &export.CompletedAt, &export.CreatedAt, &archive,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, nil
+		}
+		log.Println("Error fetching data export archive:", err)
+		return nil, nil, err
+	}
+
+	return &export, archive, nil
+}
+
+// DeleteExpired deletes expired archives and failed exports completed before the given time
+func (r *exportRepository) DeleteExpired(ctx context.Context, now, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM data_exports
+		WHERE (status = 'ready' AND expires_at <= $1) OR (status = 'failed' AND completed_at < $2)
+	`
+
+	result, err := r.conn(ctx).Exec(ctx, query, now, before)
+	if err != nil {
+		log.Println("Error deleting expired data exports:", err)
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
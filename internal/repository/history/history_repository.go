@@ -0,0 +1,18 @@
+package history_repository
+
+import (
+	"context"
+
+	"go-backend-todo/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// HistoryRepository interface defines methods for the append-only history of todos
+type HistoryRepository interface {
+	// Create appends events to the history; existing events are never changed
+	Create(ctx context.Context, events []*models.TodoHistoryEvent) error
+	// GetByTodoID returns the history of a todo, newest first
+	GetByTodoID(ctx context.Context, todoID uuid.UUID, limit, offset int) ([]*models.TodoHistoryEvent, error)
+	CountByTodoID(ctx context.Context, todoID uuid.UUID) (int64, error)
+}
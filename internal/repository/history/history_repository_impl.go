@@ -0,0 +1,101 @@
+package history_repository
+
+import (
+	"context"
+	"log"
+
+	"go-backend-todo/internal/db"
+	"go-backend-todo/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// historyRepository implementation of HistoryRepository interface
+type historyRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewHistoryRepository create a new instance of history repository
+func NewHistoryRepository(db *pgxpool.Pool) HistoryRepository {
+	return &historyRepository{db: db}
+}
+
+// conn returns the transaction of ctx, or the pool outside of one
+func (r *historyRepository) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, r.db)
+}
+
+// Create inserts the events in a single batch
+func (r *historyRepository) Create(ctx context.Context, events []*models.TodoHistoryEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO todo_events (todo_id, actor_id, action, field, old_value, new_value)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	batch := &pgx.Batch{}
+	for _, event := range events {
+		batch.Queue(query, event.TodoID, event.ActorID, event.Action, event.Field, event.OldValue, event.NewValue).
+			QueryRow(func(row pgx.Row) error {
+				return row.Scan(&event.ID, &event.CreatedAt)
+			})
+	}
+
+	if err := r.conn(ctx).SendBatch(ctx, batch).Close(); err != nil {
+		log.Println("Error recording todo history:", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByTodoID retrieves the history of a todo, newest first
+func (r *historyRepository) GetByTodoID(ctx context.Context, todoID uuid.UUID, limit, offset int) ([]*models.TodoHistoryEvent, error) {
+	query := `
+		SELECT e.id, e.todo_id, e.actor_id, u.user_name, e.action, e.field, e.old_value, e.new_value, e.created_at
+		FROM todo_events e
+		LEFT JOIN user_account u ON u.user_id = e.actor_id
+		WHERE e.todo_id = $1
+		ORDER BY e.created_at DESC, e.id
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, todoID, limit, offset)
+	if err != nil {
+		log.Println("Error fetching todo history:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []*models.TodoHistoryEvent{}
+	for rows.Next() {
+		var event models.TodoHistoryEvent
+		err := rows.Scan(
+			&event.ID, &event.TodoID, &event.ActorID, &event.ActorUsername, &event.Action,
+			&event.Field, &event.OldValue, &event.NewValue, &event.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, &event)
+	}
+
+	return events, rows.Err()
+}
+
+// CountByTodoID counts the history events of a todo
+func (r *historyRepository) CountByTodoID(ctx context.Context, todoID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.conn(ctx).QueryRow(ctx, `SELECT COUNT(*) FROM todo_events WHERE todo_id = $1`, todoID).Scan(&count)
+	if err != nil {
+		log.Println("Error counting todo history:", err)
+		return 0, err
+	}
+	return count, nil
+}
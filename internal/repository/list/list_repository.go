@@ -0,0 +1,40 @@
+package list_repository
+
+import (
+	"context"
+	"time"
+
+	"go-backend-todo/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ListRepository interface defines methods for interacting with shared list, member and invitation data
+type ListRepository interface {
+	// Create stores a list together with its owner's membership
+	Create(ctx context.Context, list *models.TodoList) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.TodoList, error)
+	// GetByUserID retrieves the lists a user is a member of, with the user's role
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*models.TodoList, error)
+	Update(ctx context.Context, list *models.TodoList) error
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// GetMemberRole returns the role of a user in a list, or an empty role if the user is not a member
+	GetMemberRole(ctx context.Context, listID, userID uuid.UUID) (models.ListRole, error)
+	GetMembers(ctx context.Context, listID uuid.UUID) ([]*models.ListMember, error)
+	GetMemberIDs(ctx context.Context, listID uuid.UUID) ([]uuid.UUID, error)
+	CountMembers(ctx context.Context, listID uuid.UUID) (int, error)
+	UpdateMemberRole(ctx context.Context, listID, userID uuid.UUID, role models.ListRole) error
+	// RemoveMember removes a member and unassigns the list's todos from them
+	RemoveMember(ctx context.Context, listID, userID uuid.UUID) error
+
+	CreateInvitation(ctx context.Context, invitation *models.ListInvitation) error
+	// GetInvitationByTokenHash retrieves an invitation with its list name, or nil if none matches
+	GetInvitationByTokenHash(ctx context.Context, tokenHash string) (*models.ListInvitation, error)
+	GetPendingInvitations(ctx context.Context, listID uuid.UUID, now time.Time) ([]*models.ListInvitation, error)
+	DeleteInvitation(ctx context.Context, id, listID uuid.UUID) error
+	// AcceptInvitation marks an invitation as accepted and adds the user to its list in one transaction
+	AcceptInvitation(ctx context.Context, invitation *models.ListInvitation, userID uuid.UUID) error
+	// DeleteExpiredInvitations removes invitations that expired before the given time without being accepted
+	DeleteExpiredInvitations(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,383 @@
+package list_repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go-backend-todo/internal/db"
+	"go-backend-todo/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// listRepository implementation of ListRepository interface
+type listRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewListRepository create a new instance of list repository
+func NewListRepository(db *pgxpool.Pool) ListRepository {
+	return &listRepository{db: db}
+}
+
+// conn returns the transaction of ctx, or the pool outside of one
+func (r *listRepository) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, r.db)
+}
+
+// Create stores a list and makes its owner a member with the owner role
+func (r *listRepository) Create(ctx context.Context, list *models.TodoList) error {
+	tx, err := r.conn(ctx).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO lists (id, name, owner_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, list.ID, list.Name, list.OwnerID, list.CreatedAt, list.UpdatedAt)
+	if err != nil {
+		log.Println("Error creating list:", err)
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO list_members (list_id, user_id, role, created_at)
+		VALUES ($1, $2, $3, $4)
+	`, list.ID, list.OwnerID, string(models.ListRoleOwner), list.CreatedAt)
+	if err != nil {
+		log.Println("Error adding list owner:", err)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetByID retrieves a list by its ID
+func (r *listRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.TodoList, error) {
+	query := `SELECT id, name, owner_id, created_at, updated_at FROM lists WHERE id = $1`
+
+	var list models.TodoList
+	err := r.conn(ctx).QueryRow(ctx, query, id).Scan(&list.ID, &list.Name, &list.OwnerID, &list.CreatedAt, &list.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("list not found")
+		}
+		return nil, err
+	}
+
+	return &list, nil
+}
+
+// GetByUserID retrieves the lists a user is a member of, ordered by name
+func (r *listRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*models.TodoList, error) {
+	query := `
+		SELECT l.id, l.name, l.owner_id, m.role, l.created_at, l.updated_at
+		FROM lists l
+		JOIN list_members m ON m.list_id = l.id
+		WHERE m.user_id = $1
+		ORDER BY l.name, l.created_at
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, userID)
+	if err != nil {
+		log.Println("Error executing query:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	lists := []*models.TodoList{}
+	for rows.Next() {
+		var list models.TodoList
+		err := rows.Scan(&list.ID, &list.Name, &list.OwnerID, &list.Role, &list.CreatedAt, &list.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, &list)
+	}
+
+	return lists, rows.Err()
+}
+
+// Update renames a list
+func (r *listRepository) Update(ctx context.Context, list *models.TodoList) error {
+	list.UpdatedAt = time.Now()
+
+	result, err := r.conn(ctx).Exec(ctx, `UPDATE lists SET name = $2, updated_at = $3 WHERE id = $1`, list.ID, list.Name, list.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("list not found")
+	}
+
+	return nil
+}
+
+// Delete deletes a list with its todos, members and invitations
+func (r *listRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.conn(ctx).Exec(ctx, `DELETE FROM lists WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("list not found")
+	}
+
+	return nil
+}
+
+// GetMemberRole returns the role of a user in a list, or an empty role if the user is not a member.
+// Within a transaction the membership is share-locked, so it cannot be removed or changed before the transaction ends.
+func (r *listRepository) GetMemberRole(ctx context.Context, listID, userID uuid.UUID) (models.ListRole, error) {
+	var role models.ListRole
+	err := r.conn(ctx).QueryRow(ctx, `SELECT role FROM list_members WHERE list_id = $1 AND user_id = $2 FOR SHARE`, listID, userID).Scan(&role)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return role, nil
+}
+
+// GetMembers retrieves the members of a list, owner first
+func (r *listRepository) GetMembers(ctx context.Context, listID uuid.UUID) ([]*models.ListMember, error) {
+	query := `
+		SELECT m.list_id, m.user_id, u.user_name, u.email_address, m.role, m.created_at
+		FROM list_members m
+		JOIN user_account u ON u.user_id = m.user_id
+		WHERE m.list_id = $1
+		ORDER BY m.role = 'owner' DESC, u.user_name
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, listID)
+	if err != nil {
+		log.Println("Error executing query:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []*models.ListMember{}
+	for rows.Next() {
+		var member models.ListMember
+		err := rows.Scan(&member.ListID, &member.UserID, &member.Username, &member.Email, &member.Role, &member.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, &member)
+	}
+
+	return members, rows.Err()
+}
+
+// GetMemberIDs retrieves the user IDs of the members of a list
+func (r *listRepository) GetMemberIDs(ctx context.Context, listID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := r.conn(ctx).Query(ctx, `SELECT user_id FROM list_members WHERE list_id = $1`, listID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// CountMembers counts the members of a list
+func (r *listRepository) CountMembers(ctx context.Context, listID uuid.UUID) (int, error) {
+	var count int
+	err := r.conn(ctx).QueryRow(ctx, `SELECT COUNT(*) FROM list_members WHERE list_id = $1`, listID).Scan(&count)
+	return count, err
+}
+
+// UpdateMemberRole changes the role of a member, never touching the owner
+func (r *listRepository) UpdateMemberRole(ctx context.Context, listID, userID uuid.UUID, role models.ListRole) error {
+	query := `UPDATE list_members SET role = $3 WHERE list_id = $1 AND user_id = $2 AND role <> 'owner'`
+
+	result, err := r.conn(ctx).Exec(ctx, query, listID, userID, string(role))
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("member not found")
+	}
+
+	return nil
+}
+
+// RemoveMember removes a member from a list, never the owner, and unassigns the list's todos from them
+func (r *listRepository) RemoveMember(ctx context.Context, listID, userID uuid.UUID) error {
+	tx, err := r.conn(ctx).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `DELETE FROM list_members WHERE list_id = $1 AND user_id = $2 AND role <> 'owner'`, listID, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("member not found")
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE todos SET assignee_id = NULL WHERE list_id = $1 AND assignee_id = $2`, listID, userID)
+	if err != nil {
+		log.Println("Error unassigning todos of removed member:", err)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// CreateInvitation stores a new invitation
+func (r *listRepository) CreateInvitation(ctx context.Context, invitation *models.ListInvitation) error {
+	query := `
+		INSERT INTO list_invitations (id, list_id, email_address, role, token_hash, invited_by, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.conn(ctx).Exec(ctx, query,
+		invitation.ID, invitation.ListID, invitation.Email, string(invitation.Role), invitation.TokenHash,
+		invitation.InvitedBy, invitation.ExpiresAt, invitation.CreatedAt,
+	)
+	if err != nil {
+		log.Println("Error creating list invitation:", err)
+	}
+	return err
+}
+
+// GetInvitationByTokenHash retrieves an invitation by the hash of its token, or nil if none matches
+func (r *listRepository) GetInvitationByTokenHash(ctx context.Context, tokenHash string) (*models.ListInvitation, error) {
+	query := `
+		SELECT i.id, i.list_id, l.name, i.email_address, i.role, i.token_hash, i.invited_by, i.expires_at, i.accepted_at, i.created_at
+		FROM list_invitations i
+		JOIN lists l ON l.id = i.list_id
+		WHERE i.token_hash = $1
+	`
+
+	var invitation models.ListInvitation
+	err := r.conn(ctx).QueryRow(ctx, query, tokenHash).Scan(
+		&invitation.ID, &invitation.ListID, &invitation.ListName, &invitation.Email, &invitation.Role,
+		&invitation.TokenHash, &invitation.InvitedBy, &invitation.ExpiresAt, &invitation.AcceptedAt, &invitation.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &invitation, nil
+}
+
+// GetPendingInvitations retrieves the unaccepted, unexpired invitations of a list
+func (r *listRepository) GetPendingInvitations(ctx context.Context, listID uuid.UUID, now time.Time) ([]*models.ListInvitation, error) {
+	query := `
+		SELECT id, list_id, email_address, role, invited_by, expires_at, created_at
+		FROM list_invitations
+		WHERE list_id = $1 AND accepted_at IS NULL AND expires_at > $2
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, listID, now)
+	if err != nil {
+		log.Println("Error executing query:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	invitations := []*models.ListInvitation{}
+	for rows.Next() {
+		var invitation models.ListInvitation
+		err := rows.Scan(
+			&invitation.ID, &invitation.ListID, &invitation.Email, &invitation.Role,
+			&invitation.InvitedBy, &invitation.ExpiresAt, &invitation.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, &invitation)
+	}
+
+	return invitations, rows.Err()
+}
+
+// DeleteInvitation revokes an invitation of a list
+func (r *listRepository) DeleteInvitation(ctx context.Context, id, listID uuid.UUID) error {
+	result, err := r.conn(ctx).Exec(ctx, `DELETE FROM list_invitations WHERE id = $1 AND list_id = $2`, id, listID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("invitation not found")
+	}
+
+	return nil
+}
+
+// DeleteExpiredInvitations deletes the unaccepted invitations that expired before the given time
+func (r *listRepository) DeleteExpiredInvitations(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.conn(ctx).Exec(ctx, `DELETE FROM list_invitations WHERE accepted_at IS NULL AND expires_at < $1`, before)
+	if err != nil {
+		log.Println("Error deleting expired list invitations:", err)
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+// AcceptInvitation marks an invitation as accepted and adds the user to its list.
+// The conditional update makes an invitation usable once, even when accepted concurrently.
+func (r *listRepository) AcceptInvitation(ctx context.Context, invitation *models.ListInvitation, userID uuid.UUID) error {
+	tx, err := r.conn(ctx).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	now := time.Now()
+	result, err := tx.Exec(ctx, `
+		UPDATE list_invitations SET accepted_at = $2
+		WHERE id = $1 AND accepted_at IS NULL AND expires_at > $2
+	`, invitation.ID, now)
+	if err != nil {
+		log.Println("Error accepting list invitation:", err)
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("invitation is no longer valid")
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO list_members (list_id, user_id, role, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (list_id, user_id) DO NOTHING
+	`, invitation.ListID, userID, string(invitation.Role), now)
+	if err != nil {
+		log.Println("Error adding list member:", err)
+		return err
+	}
+
+	invitation.AcceptedAt = &now
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,21 @@
+package preference_repository
+
+import (
+	"context"
+	"time"
+
+	"go-backend-todo/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// PreferenceRepository interface defines methods for interacting with user preference data
+type PreferenceRepository interface {
+	GetByUserID(ctx context.Context, userID uuid.UUID) (*models.UserPreferences, error)
+	Upsert(ctx context.Context, prefs *models.UserPreferences) error
+	SetEmailDigest(ctx context.Context, userID uuid.UUID, enabled bool) error
+
+	// ClaimDueDigests marks up to limit digests whose local digest hour has been reached as sent today
+	// and returns their recipients. Each digest is claimed at most once per local day.
+	ClaimDueDigests(ctx context.Context, now time.Time, windowHours, limit int) ([]*models.DueDigest, error)
+}
@@ -0,0 +1,148 @@
+package preference_repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go-backend-todo/internal/db"
+	"go-backend-todo/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// preferenceRepository implementation of PreferenceRepository interface
+type preferenceRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPreferenceRepository create a new instance of preference repository
+func NewPreferenceRepository(db *pgxpool.Pool) PreferenceRepository {
+	return &preferenceRepository{db: db}
+}
+
+// conn returns the transaction of ctx, or the pool outside of one
+func (r *preferenceRepository) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, r.db)
+}
+
+// GetByUserID retrieves the preferences of a user, returning nil if the user never saved any
+func (r *preferenceRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*models.UserPreferences, error) {
+	query := `
+		SELECT user_id, timezone, locale, week_start, default_todo_sort,
+			email_reminders, email_digest, digest_hour, digest_last_sent_on, updated_at
+		FROM user_preferences
+		WHERE user_id = $1
+	`
+
+	var prefs models.UserPreferences
+	err := r.conn(ctx).QueryRow(ctx, query, userID).Scan(
+		&prefs.UserID, &prefs.Timezone, &prefs.Locale, &prefs.WeekStart, &prefs.DefaultTodoSort,
+		&prefs.EmailReminders, &prefs.EmailDigest, &prefs.DigestHour, &prefs.DigestLastSentOn, &prefs.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		log.Println("Error fetching user preferences:", err)
+		return nil, err
+	}
+
+	return &prefs, nil
+}
+
+// Upsert creates or replaces the preferences of a user
+func (r *preferenceRepository) Upsert(ctx context.Context, prefs *models.UserPreferences) error {
+	query := `
+		INSERT INTO user_preferences (
+			user_id, timezone, locale, week_start, default_todo_sort,
+			email_reminders, email_digest, digest_hour, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)
+		ON CONFLICT (user_id) DO UPDATE
+		SET timezone = EXCLUDED.timezone,
+			locale = EXCLUDED.locale,
+			week_start = EXCLUDED.week_start,
+			default_todo_sort = EXCLUDED.default_todo_sort,
+			email_reminders = EXCLUDED.email_reminders,
+			email_digest = EXCLUDED.email_digest,
+			digest_hour = EXCLUDED.digest_hour,
+			updated_at = EXCLUDED.updated_at
+		RETURNING digest_last_sent_on
+	`
+
+	prefs.UpdatedAt = time.Now()
+
+	err := r.conn(ctx).QueryRow(ctx, query,
+		prefs.UserID, prefs.Timezone, prefs.Locale, prefs.WeekStart, prefs.DefaultTodoSort,
+		prefs.EmailReminders, prefs.EmailDigest, prefs.DigestHour, prefs.UpdatedAt,
+	).Scan(&prefs.DigestLastSentOn)
+	if err != nil {
+		log.Println("Error saving user preferences:", err)
+		return err
+	}
+
+	return nil
+}
+
+// SetEmailDigest turns the digest email of a user on or off
+func (r *preferenceRepository) SetEmailDigest(ctx context.Context, userID uuid.UUID, enabled bool) error {
+	query := `
+		INSERT INTO user_preferences (user_id, email_digest, created_at, updated_at)
+		VALUES ($1, $2, $3, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET email_digest = EXCLUDED.email_digest, updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.conn(ctx).Exec(ctx, query, userID, enabled, time.Now())
+	if err != nil {
+		log.Println("Error updating digest preference:", err)
+		return err
+	}
+
+	return nil
+}
+
+// ClaimDueDigests stamps digest_last_sent_on with the local date the digest hour started on before the digest is built,
+// so concurrent schedulers never pick the same subscriber twice for the same day.
+// Hours are compared modulo 24, so a window starting at 23:00 still covers the first hours of the next day,
+// which belong to the digest of the day before.
+func (r *preferenceRepository) ClaimDueDigests(ctx context.Context, now time.Time, windowHours, limit int) ([]*models.DueDigest, error) {
+	query := `
+		WITH due AS (
+			SELECT p.user_id, (t.local_now - make_interval(hours => h.hours_late))::date AS digest_date
+			FROM user_preferences p
+			CROSS JOIN LATERAL (SELECT $1::timestamptz AT TIME ZONE p.timezone AS local_now) t
+			CROSS JOIN LATERAL (SELECT (EXTRACT(HOUR FROM t.local_now)::int - p.digest_hour + 24) % 24 AS hours_late) h
+			WHERE p.email_digest = true
+				AND h.hours_late < $2
+				AND (p.digest_last_sent_on IS NULL OR p.digest_last_sent_on < (t.local_now - make_interval(hours => h.hours_late))::date)
+			LIMIT $3
+			FOR UPDATE OF p SKIP LOCKED
+		)
+		UPDATE user_preferences p
+		SET digest_last_sent_on = due.digest_date
+		FROM due, user_account u
+		WHERE p.user_id = due.user_id AND u.user_id = p.user_id
+		RETURNING p.user_id, u.user_name, u.email_address, p.timezone, p.week_start
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, now, windowHours, limit)
+	if err != nil {
+		log.Println("Error claiming due digests:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var digests []*models.DueDigest
+	for rows.Next() {
+		var digest models.DueDigest
+		if err := rows.Scan(&digest.UserID, &digest.Username, &digest.Email, &digest.Timezone, &digest.WeekStart); err != nil {
+			return nil, err
+		}
+		digests = append(digests, &digest)
+	}
+
+	return digests, rows.Err()
+}
@@ -0,0 +1,21 @@
+package reminder_repository
+
+import (
+	"context"
+	"time"
+
+	"go-backend-todo/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ReminderRepository interface defines methods for interacting with todo reminder data
+type ReminderRepository interface {
+	// ReplaceForTodo removes all reminders of a todo and schedules new ones relative to the deadline
+	ReplaceForTodo(ctx context.Context, todoID uuid.UUID, deadline time.Time, offsets []int) ([]*models.TodoReminder, error)
+	GetByTodoID(ctx context.Context, todoID uuid.UUID) ([]*models.TodoReminder, error)
+
+	// ClaimDue marks up to limit due reminders as sent and returns them for delivery.
+	// A reminder is claimed exactly once, even with several scheduler instances running.
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]*models.DueReminder, error)
+}
@@ -0,0 +1,163 @@
+package reminder_repository
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"go-backend-todo/internal/db"
+	"go-backend-todo/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// reminderRepository implementation of ReminderRepository interface
+type reminderRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewReminderRepository create a new instance of reminder repository
+func NewReminderRepository(db *pgxpool.Pool) ReminderRepository {
+	return &reminderRepository{db: db}
+}
+
+// conn returns the transaction of ctx, or the pool outside of one
+func (r *reminderRepository) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, r.db)
+}
+
+// ReplaceForTodo replaces the reminders of a todo, skipping offsets whose time has already passed
+func (r *reminderRepository) ReplaceForTodo(ctx context.Context, todoID uuid.UUID, deadline time.Time, offsets []int) ([]*models.TodoReminder, error) {
+	tx, err := r.conn(ctx).Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `DELETE FROM todo_reminders WHERE todo_id = $1`, todoID)
+	if err != nil {
+		log.Println("Error deleting reminders:", err)
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO todo_reminders (id, todo_id, offset_minutes, remind_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (todo_id, offset_minutes) DO NOTHING
+	`
+
+	now := time.Now()
+	reminders := []*models.TodoReminder{}
+	for _, offset := range offsets {
+		remindAt := deadline.Add(-time.Duration(offset) * time.Minute)
+		if !remindAt.After(now) {
+			continue
+		}
+
+		reminder := &models.TodoReminder{
+			ID:            uuid.New(),
+			TodoID:        todoID,
+			OffsetMinutes: offset,
+			RemindAt:      remindAt,
+			CreatedAt:     now,
+		}
+		_, err := tx.Exec(ctx, query,
+			reminder.ID, reminder.TodoID, reminder.OffsetMinutes, reminder.RemindAt, reminder.CreatedAt,
+		)
+		if err != nil {
+			log.Println("Error inserting reminder:", err)
+			return nil, err
+		}
+		reminders = append(reminders, reminder)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(reminders, func(i, j int) bool {
+		return reminders[i].RemindAt.Before(reminders[j].RemindAt)
+	})
+	return reminders, nil
+}
+
+// GetByTodoID retrieves all reminders of a todo ordered by reminder time
+func (r *reminderRepository) GetByTodoID(ctx context.Context, todoID uuid.UUID) ([]*models.TodoReminder, error) {
+	query := `
+		SELECT id, todo_id, offset_minutes, remind_at, sent_at, created_at
+		FROM todo_reminders
+		WHERE todo_id = $1
+		ORDER BY remind_at
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, todoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reminders := []*models.TodoReminder{}
+	for rows.Next() {
+		var reminder models.TodoReminder
+		err := rows.Scan(
+			&reminder.ID, &reminder.TodoID, &reminder.OffsetMinutes,
+			&reminder.RemindAt, &reminder.SentAt, &reminder.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, &reminder)
+	}
+
+	return reminders, rows.Err()
+}
+
+// ClaimDue claims due reminders of incomplete todos outside the trash by stamping sent_at before delivery.
+// A reminder goes to the assignee of the todo, who may be another member of its shared list, or else to its creator.
+// SKIP LOCKED lets concurrent schedulers pick disjoint batches, and because the claim is
+// committed before any email goes out a crash can lose a reminder but never send it twice.
+func (r *reminderRepository) ClaimDue(ctx context.Context, now time.Time, limit int) ([]*models.DueReminder, error) {
+	query := `
+		WITH due AS (
+			SELECT r.id
+			FROM todo_reminders r
+			JOIN todos t ON t.id = r.todo_id
+			WHERE r.sent_at IS NULL AND r.remind_at <= $1 AND t.completed = FALSE AND t.deleted_at IS NULL
+			ORDER BY r.remind_at
+			LIMIT $2
+			FOR UPDATE OF r SKIP LOCKED
+		)
+		UPDATE todo_reminders r
+		SET sent_at = $1
+		FROM due, todos t, user_account u
+		LEFT JOIN user_preferences p ON p.user_id = u.user_id
+		WHERE r.id = due.id AND t.id = r.todo_id AND u.user_id = COALESCE(t.assignee_id, t.user_id)
+		RETURNING r.id, r.todo_id, r.offset_minutes, t.title, t.deadline, u.user_id, u.user_name, u.email_address,
+			COALESCE(p.timezone, 'UTC'), COALESCE(p.email_reminders, true)
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, now, limit)
+	if err != nil {
+		log.Println("Error claiming due reminders:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reminders []*models.DueReminder
+	for rows.Next() {
+		var reminder models.DueReminder
+		err := rows.Scan(
+			&reminder.ReminderID, &reminder.TodoID, &reminder.OffsetMinutes, &reminder.TodoTitle,
+			&reminder.Deadline, &reminder.UserID, &reminder.Username, &reminder.Email,
+			&reminder.Timezone, &reminder.EmailEnabled,
+		)
+		if err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, &reminder)
+	}
+
+	return reminders, rows.Err()
+}
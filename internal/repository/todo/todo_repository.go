@@ -2,26 +2,49 @@ package todo_repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"go-backend-todo/internal/models"
 
 	"github.com/google/uuid"
 )
 
+// ErrTodoNotFound is returned when no todo has the given ID
+var ErrTodoNotFound = errors.New("todo not found")
+
 // TodoRepository interface defines methods for interacting with todo data
 type TodoRepository interface {
 	// CRUD operations
 	Create(ctx context.Context, todo *models.Todo) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Todo, error)
 	Update(ctx context.Context, todo *models.Todo) error
+	// Delete moves a todo to the trash, from where it can be restored until it is purged
 	Delete(ctx context.Context, id uuid.UUID) error
 
 	// Query operations
 	GetByUserID(ctx context.Context, filter models.TodoFilter) ([]*models.Todo, error)
 	GetAll(ctx context.Context, filter models.TodoFilter) ([]*models.Todo, error)
 	Count(ctx context.Context, filter models.TodoFilter) (int64, error)
+	GetStats(ctx context.Context, userID uuid.UUID, window models.TodoStatsWindow) (*models.TodoStatsResponse, error)
+	GetPendingByDeadline(ctx context.Context, userID uuid.UUID, from, to *time.Time, limit int) ([]*models.Todo, error)
+	// StreamByUserID calls fn for every todo visible to a user, oldest first, reading rows as they arrive
+	StreamByUserID(ctx context.Context, userID uuid.UUID, fn func(todo *models.Todo) error) error
+	// GetSyncState summarizes the todos visible to a user, including trashed ones so that deletions change it
+	GetSyncState(ctx context.Context, userID uuid.UUID) (*models.TodoSyncState, error)
+
+	// Trash operations
+	GetDeletedByID(ctx context.Context, id uuid.UUID) (*models.Todo, error)
+	// GetByIDIncludingDeleted retrieves a todo by its ID, whether or not it is in the trash
+	GetByIDIncludingDeleted(ctx context.Context, id uuid.UUID) (*models.Todo, error)
+	GetDeleted(ctx context.Context, filter models.TodoFilter) ([]*models.Todo, error)
+	CountDeleted(ctx context.Context, filter models.TodoFilter) (int64, error)
+	Restore(ctx context.Context, id uuid.UUID) (*models.Todo, error)
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
 
 	// Bulk operations
+	// CreateMany inserts todos in a single transaction, all or none
+	CreateMany(ctx context.Context, todos []*models.Todo) (int64, error)
 	MarkAsCompleted(ctx context.Context, ids []uuid.UUID) error
-	DeleteCompleted(ctx context.Context, userID uuid.UUID) error
+	DeleteCompleted(ctx context.Context, userID uuid.UUID) ([]*models.Todo, error)
 }
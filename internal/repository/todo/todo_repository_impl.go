@@ -6,6 +6,7 @@ import (
 	"log"
 	"time"
 
+	"go-backend-todo/internal/db"
 	"go-backend-todo/internal/models"
 
 	"github.com/google/uuid"
@@ -15,69 +16,117 @@ import (
 
 // todoRepository implementation of TodoRepository interface
 type todoRepository struct {
-	db *pgxpool.Pool
+	db     *pgxpool.Pool
+	router *db.Router
 }
 
-// NewTodoRepository create a new instance of todo repository
-func NewTodoRepository(db *pgxpool.Pool) TodoRepository {
-	return &todoRepository{db: db}
+// NewTodoRepository create a new instance of todo repository.
+// List, search, stats and export queries go through router, which may send them to a replica.
+func NewTodoRepository(db *pgxpool.Pool, router *db.Router) TodoRepository {
+	return &todoRepository{db: db, router: router}
+}
+
+// conn returns the transaction of ctx, or the pool outside of one
+func (r *todoRepository) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, r.db)
+}
+
+// reader returns a connection for reads that tolerate replication lag
+func (r *todoRepository) reader(ctx context.Context) db.Querier {
+	return r.router.Reader(ctx)
+}
+
+// todoColumns are the columns scanned by scanTodo, including the assignee's username
+const todoColumns = `id, title, deadline, completed, created_at, updated_at, user_id, list_id, assignee_id, deleted_at,
+		(SELECT u.user_name FROM user_account u WHERE u.user_id = todos.assignee_id)`
+
+// scanTodo scans a row selected with todoColumns
+func scanTodo(row pgx.Row) (*models.Todo, error) {
+	var todo models.Todo
+	var assigneeName *string
+	err := row.Scan(
+		&todo.ID, &todo.Title, &todo.Deadline, &todo.Completed,
+		&todo.CreatedAt, &todo.UpdatedAt, &todo.UserID, &todo.ListID,
+		&todo.AssigneeID, &todo.DeletedAt, &assigneeName,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if todo.AssigneeID != nil && assigneeName != nil {
+		todo.Assignee = &models.TodoAssignee{UserID: *todo.AssigneeID, Username: *assigneeName}
+	}
+	return &todo, nil
 }
 
 // Create a new todo
 func (r *todoRepository) Create(ctx context.Context, todo *models.Todo) error {
 	query := `
-		INSERT INTO todos (id, title, deadline, completed, created_at, updated_at, user_id)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO todos (id, title, deadline, completed, created_at, updated_at, user_id, list_id, assignee_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
 
-	todo.ID = uuid.New()
+	if todo.ID == uuid.Nil {
+		todo.ID = uuid.New()
+	}
 	todo.CreatedAt = time.Now()
 	todo.UpdatedAt = time.Now()
 
-	_, err := r.db.Exec(ctx, query,
+	_, err := r.conn(ctx).Exec(ctx, query,
 		todo.ID, todo.Title, todo.Deadline, todo.Completed,
-		todo.CreatedAt, todo.UpdatedAt, todo.UserID,
+		todo.CreatedAt, todo.UpdatedAt, todo.UserID, todo.ListID, todo.AssigneeID,
 	)
 
 	return err
 }
 
-// GetByID retrieves a todo by its ID
+// GetByID retrieves a todo by its ID, unless it is in the trash
 func (r *todoRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Todo, error) {
+	return r.getByID(ctx, id, "deleted_at IS NULL")
+}
+
+// GetDeletedByID retrieves a todo in the trash by its ID
+func (r *todoRepository) GetDeletedByID(ctx context.Context, id uuid.UUID) (*models.Todo, error) {
+	return r.getByID(ctx, id, "deleted_at IS NOT NULL")
+}
+
+// GetByIDIncludingDeleted retrieves a todo by its ID, in the trash or not
+func (r *todoRepository) GetByIDIncludingDeleted(ctx context.Context, id uuid.UUID) (*models.Todo, error) {
+	return r.getByID(ctx, id, "TRUE")
+}
+
+// getByID retrieves a todo by its ID that also satisfies condition
+func (r *todoRepository) getByID(ctx context.Context, id uuid.UUID, condition string) (*models.Todo, error) {
 	query := `
-		SELECT id, title, deadline, completed, created_at, updated_at, user_id
+		SELECT ` + todoColumns + `
 		FROM todos
-		WHERE id = $1
+		WHERE id = $1 AND ` + condition + `
 	`
 
-	var todo models.Todo
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&todo.ID, &todo.Title, &todo.Deadline, &todo.Completed,
-		&todo.CreatedAt, &todo.UpdatedAt, &todo.UserID,
-	)
+	todo, err := scanTodo(r.conn(ctx).QueryRow(ctx, query, id))
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("todo not found")
+			return nil, ErrTodoNotFound
 		}
 		return nil, err
 	}
 
-	return &todo, nil
+	return todo, nil
 }
 
 // Update a todo
 func (r *todoRepository) Update(ctx context.Context, todo *models.Todo) error {
 	query := `
 		UPDATE todos
-		SET title = $2, deadline = $3, completed = $4, updated_at = $5
-		WHERE id = $1
+		SET title = $2, deadline = $3, completed = $4, assignee_id = $5, updated_at = $6
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	todo.UpdatedAt = time.Now()
 
-	result, err := r.db.Exec(ctx, query,
-		todo.ID, todo.Title, todo.Deadline, todo.Completed, todo.UpdatedAt,
+	result, err := r.conn(ctx).Exec(ctx, query,
+		todo.ID, todo.Title, todo.Deadline, todo.Completed, todo.AssigneeID, todo.UpdatedAt,
 	)
 
 	if err != nil {
@@ -85,37 +134,57 @@ func (r *todoRepository) Update(ctx context.Context, todo *models.Todo) error {
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("todo not found")
+		return ErrTodoNotFound
 	}
 
 	return nil
 }
 
-// Delete a todo
+// Delete moves a todo to the trash
 func (r *todoRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM todos WHERE id = $1`
+	query := `UPDATE todos SET deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL`
 
-	result, err := r.db.Exec(ctx, query, id)
+	result, err := r.conn(ctx).Exec(ctx, query, id, time.Now())
 	if err != nil {
 		return err
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("todo not found")
+		return ErrTodoNotFound
 	}
 
 	return nil
 }
 
-// GetByUserID retrieves todos by user ID with filter
+// Restore moves a todo out of the trash and returns it
+func (r *todoRepository) Restore(ctx context.Context, id uuid.UUID) (*models.Todo, error) {
+	query := `
+		UPDATE todos
+		SET deleted_at = NULL, updated_at = $2
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		RETURNING ` + todoColumns + `
+	`
+
+	todo, err := scanTodo(r.conn(ctx).QueryRow(ctx, query, id, time.Now()))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrTodoNotFound
+		}
+		return nil, err
+	}
+
+	return todo, nil
+}
+
+// GetByUserID retrieves the todos visible to a user with filter: personal todos and todos of lists the user is a member of
 func (r *todoRepository) GetByUserID(ctx context.Context, filter models.TodoFilter) ([]*models.Todo, error) {
 	query := `
-		SELECT id, title, deadline, completed, created_at, updated_at, user_id
+		SELECT ` + todoColumns + `
 		FROM todos
-		WHERE user_id = $1
+		WHERE deleted_at IS NULL AND ` + visibilityClause(filter) + `
 	`
-	args := []interface{}{filter.UserID}
-	argIndex := 2
+	args := visibilityArgs(filter)
+	argIndex := len(args) + 1
 
 	// Filter by completion status if provided
 	if filter.Completed != nil {
@@ -124,7 +193,14 @@ func (r *todoRepository) GetByUserID(ctx context.Context, filter models.TodoFilt
 		argIndex++
 	}
 
-	query += " ORDER BY created_at DESC"
+	// Filter by assignee if provided
+	if filter.AssigneeID != nil {
+		query += fmt.Sprintf(" AND assignee_id = $%d", argIndex)
+		args = append(args, *filter.AssigneeID)
+		argIndex++
+	}
+
+	query += " ORDER BY " + orderByClause(filter.Sort)
 
 	// Add limit and offset
 	if filter.Limit > 0 {
@@ -138,7 +214,7 @@ func (r *todoRepository) GetByUserID(ctx context.Context, filter models.TodoFilt
 		args = append(args, filter.Offset)
 	}
 
-	rows, err := r.db.Query(ctx, query, args...)
+	rows, err := r.reader(ctx).Query(ctx, query, args...)
 	if err != nil {
 		log.Println("Error executing query:", err)
 		return nil, err
@@ -147,26 +223,71 @@ func (r *todoRepository) GetByUserID(ctx context.Context, filter models.TodoFilt
 
 	var todos []*models.Todo
 	for rows.Next() {
-		var todo models.Todo
-		err := rows.Scan(
-			&todo.ID, &todo.Title, &todo.Deadline, &todo.Completed,
-			&todo.CreatedAt, &todo.UpdatedAt, &todo.UserID,
-		)
+		todo, err := scanTodo(rows)
 		if err != nil {
 			return nil, err
 		}
-		todos = append(todos, &todo)
+		todos = append(todos, todo)
 	}
 	
 	return todos, nil
 }
 
+// StreamByUserID calls fn for every todo visible to a user without loading them all into memory.
+// Iteration stops at the first error returned by fn.
+func (r *todoRepository) StreamByUserID(ctx context.Context, userID uuid.UUID, fn func(todo *models.Todo) error) error {
+	filter := models.TodoFilter{UserID: userID}
+	query := `
+		SELECT ` + todoColumns + `
+		FROM todos
+		WHERE deleted_at IS NULL AND ` + visibilityClause(filter) + `
+		ORDER BY created_at ASC, id ASC
+	`
+
+	rows, err := r.reader(ctx).Query(ctx, query, visibilityArgs(filter)...)
+	if err != nil {
+		log.Println("Error executing query:", err)
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		todo, err := scanTodo(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(todo); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// GetSyncState counts the todos visible to a user and finds when the last of them was changed or deleted
+func (r *todoRepository) GetSyncState(ctx context.Context, userID uuid.UUID) (*models.TodoSyncState, error) {
+	filter := models.TodoFilter{UserID: userID}
+	query := `
+		SELECT COUNT(*) FILTER (WHERE deleted_at IS NULL), MAX(GREATEST(updated_at, deleted_at))
+		FROM todos
+		WHERE ` + visibilityClause(filter)
+
+	var state models.TodoSyncState
+	err := r.conn(ctx).QueryRow(ctx, query, visibilityArgs(filter)...).Scan(&state.Count, &state.LastModified)
+	if err != nil {
+		log.Println("Error getting todo sync state:", err)
+		return nil, err
+	}
+
+	return &state, nil
+}
+
 // GetAll retrieves all todos with optional filters
 func (r *todoRepository) GetAll(ctx context.Context, filter models.TodoFilter) ([]*models.Todo, error) {
 	query := `
-		SELECT id, title, deadline, completed, created_at, updated_at, user_id
+		SELECT ` + todoColumns + `
 		FROM todos
-		WHERE 1=1 
+		WHERE deleted_at IS NULL 
 	`
 	args := []interface{}{}
 	argIndex := 1
@@ -178,7 +299,7 @@ func (r *todoRepository) GetAll(ctx context.Context, filter models.TodoFilter) (
 		argIndex++
 	}
 
-	query += " ORDER BY created_at DESC"
+	query += " ORDER BY " + orderByClause(filter.Sort)
 
 	// Add limit and offset
 	if filter.Limit > 0 {
@@ -192,7 +313,7 @@ func (r *todoRepository) GetAll(ctx context.Context, filter models.TodoFilter) (
 		args = append(args, filter.Offset)
 	}
 
-	rows, err := r.db.Query(ctx, query, args...)
+	rows, err := r.reader(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -200,52 +321,274 @@ func (r *todoRepository) GetAll(ctx context.Context, filter models.TodoFilter) (
 
 	var todos []*models.Todo
 	for rows.Next() {
-		var todo models.Todo
-		err := rows.Scan(
-			&todo.ID, &todo.Title, &todo.Deadline, &todo.Completed,
-			&todo.CreatedAt, &todo.UpdatedAt, &todo.UserID,
-		)
+		todo, err := scanTodo(rows)
 		if err != nil {
 			return nil, err
 		}
-		todos = append(todos, &todo)
+		todos = append(todos, todo)
 	}
 
 	return todos, nil
 }
 
-// Count counts the number of todos for a user with optional filters
+// Count counts the number of todos visible to a user with optional filters
 func (r *todoRepository) Count(ctx context.Context, filter models.TodoFilter) (int64, error) {
-	query := `SELECT COUNT(*) FROM todos WHERE user_id = $1`
-	args := []interface{}{filter.UserID}
-	argIndex := 2
+	query := `SELECT COUNT(*) FROM todos WHERE deleted_at IS NULL AND ` + visibilityClause(filter)
+	args := visibilityArgs(filter)
+	argIndex := len(args) + 1
 
 	if filter.Completed != nil {
 		query += fmt.Sprintf(" AND completed = $%d", argIndex)
 		args = append(args, *filter.Completed)
+		argIndex++
+	}
+
+	if filter.AssigneeID != nil {
+		query += fmt.Sprintf(" AND assignee_id = $%d", argIndex)
+		args = append(args, *filter.AssigneeID)
 	}
 
 	var count int64
-	err := r.db.QueryRow(ctx, query, args...).Scan(&count)
+	err := r.reader(ctx).QueryRow(ctx, query, args...).Scan(&count)
 	return count, err
 }
 
+// GetStats computes statistics of the todos visible to a user, including those of shared lists, within the given time window
+func (r *todoRepository) GetStats(ctx context.Context, userID uuid.UUID, window models.TodoStatsWindow) (*models.TodoStatsResponse, error) {
+	query := `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE completed = true),
+			COUNT(*) FILTER (WHERE completed = false),
+			COUNT(*) FILTER (WHERE completed = false AND deadline < $2),
+			COUNT(*) FILTER (WHERE completed = false AND deadline >= $3 AND deadline < $4),
+			COUNT(*) FILTER (WHERE completed = false AND deadline >= $3 AND deadline < $5)
+		FROM todos
+		WHERE deleted_at IS NULL AND ` + visibilityClause(models.TodoFilter{UserID: userID}) + `
+	`
+
+	var stats models.TodoStatsResponse
+	err := r.reader(ctx).QueryRow(ctx, query, userID, window.Now, window.DayStart, window.DayEnd, window.WeekEnd).Scan(
+		&stats.TotalTodos, &stats.CompletedTodos, &stats.PendingTodos,
+		&stats.OverdueTodos, &stats.TodayTodos, &stats.ThisWeekTodos,
+	)
+	if err != nil {
+		log.Println("Error computing todo stats:", err)
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// GetPendingByDeadline retrieves incomplete todos visible to a user with a deadline in [from, to), ordered by deadline.
+// A nil bound leaves that side of the range open.
+func (r *todoRepository) GetPendingByDeadline(ctx context.Context, userID uuid.UUID, from, to *time.Time, limit int) ([]*models.Todo, error) {
+	filter := models.TodoFilter{UserID: userID}
+	query := `
+		SELECT ` + todoColumns + `
+		FROM todos
+		WHERE completed = false AND deleted_at IS NULL AND ` + visibilityClause(filter) + `
+	`
+	args := visibilityArgs(filter)
+	argIndex := 2
+
+	if from != nil {
+		query += fmt.Sprintf(" AND deadline >= $%d", argIndex)
+		args = append(args, *from)
+		argIndex++
+	}
+
+	if to != nil {
+		query += fmt.Sprintf(" AND deadline < $%d", argIndex)
+		args = append(args, *to)
+		argIndex++
+	}
+
+	query += " ORDER BY deadline ASC"
+
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d", argIndex)
+		args = append(args, limit)
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		log.Println("Error executing query:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var todos []*models.Todo
+	for rows.Next() {
+		todo, err := scanTodo(rows)
+		if err != nil {
+			return nil, err
+		}
+		todos = append(todos, todo)
+	}
+
+	return todos, rows.Err()
+}
+
+// CreateMany inserts todos with COPY in a single transaction and fills in their IDs and timestamps
+func (r *todoRepository) CreateMany(ctx context.Context, todos []*models.Todo) (int64, error) {
+	now := time.Now()
+	rows := make([][]interface{}, len(todos))
+	for i, todo := range todos {
+		todo.ID = uuid.New()
+		todo.CreatedAt = now
+		todo.UpdatedAt = now
+		rows[i] = []interface{}{
+			todo.ID, todo.Title, todo.Deadline, todo.Completed,
+			todo.CreatedAt, todo.UpdatedAt, todo.UserID, todo.ListID, todo.AssigneeID,
+		}
+	}
+
+	tx, err := r.conn(ctx).Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	count, err := tx.CopyFrom(ctx,
+		pgx.Identifier{"todos"},
+		[]string{"id", "title", "deadline", "completed", "created_at", "updated_at", "user_id", "list_id", "assignee_id"},
+		pgx.CopyFromRows(rows),
+	)
+	if err != nil {
+		log.Println("Error copying todos:", err)
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
 // MarkAsCompleted marks multiple todos as completed
 func (r *todoRepository) MarkAsCompleted(ctx context.Context, ids []uuid.UUID) error {
 	query := `
 		UPDATE todos
 		SET completed = true, updated_at = $1
-		WHERE id = ANY($2)
+		WHERE id = ANY($2) AND deleted_at IS NULL
 	`
 
-	_, err := r.db.Exec(ctx, query, time.Now(), ids)
+	_, err := r.conn(ctx).Exec(ctx, query, time.Now(), ids)
 	return err
 }
 
-// DeleteCompleted deletes all completed todos for a user
-func (r *todoRepository) DeleteCompleted(ctx context.Context, userID uuid.UUID) error {
-	query := `DELETE FROM todos WHERE user_id = $1 AND completed = true`
+// DeleteCompleted moves all completed personal todos for a user to the trash and returns them
+func (r *todoRepository) DeleteCompleted(ctx context.Context, userID uuid.UUID) ([]*models.Todo, error) {
+	query := `
+		UPDATE todos
+		SET deleted_at = $2
+		WHERE user_id = $1 AND list_id IS NULL AND completed = true AND deleted_at IS NULL
+		RETURNING ` + todoColumns + `
+	`
 
-	_, err := r.db.Exec(ctx, query, userID)
-	return err
+	rows, err := r.conn(ctx).Query(ctx, query, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var todos []*models.Todo
+	for rows.Next() {
+		todo, err := scanTodo(rows)
+		if err != nil {
+			return nil, err
+		}
+		todos = append(todos, todo)
+	}
+
+	return todos, rows.Err()
+}
+
+// GetDeleted retrieves the todos in the trash visible to a user, most recently deleted first
+func (r *todoRepository) GetDeleted(ctx context.Context, filter models.TodoFilter) ([]*models.Todo, error) {
+	query := `
+		SELECT ` + todoColumns + `
+		FROM todos
+		WHERE deleted_at IS NOT NULL AND ` + visibilityClause(filter) + `
+		ORDER BY deleted_at DESC
+	`
+	args := visibilityArgs(filter)
+
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+		args = append(args, filter.Limit, filter.Offset)
+	}
+
+	rows, err := r.reader(ctx).Query(ctx, query, args...)
+	if err != nil {
+		log.Println("Error executing query:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	todos := []*models.Todo{}
+	for rows.Next() {
+		todo, err := scanTodo(rows)
+		if err != nil {
+			return nil, err
+		}
+		todos = append(todos, todo)
+	}
+
+	return todos, rows.Err()
+}
+
+// CountDeleted counts the todos in the trash visible to a user
+func (r *todoRepository) CountDeleted(ctx context.Context, filter models.TodoFilter) (int64, error) {
+	query := `SELECT COUNT(*) FROM todos WHERE deleted_at IS NOT NULL AND ` + visibilityClause(filter)
+
+	var count int64
+	err := r.reader(ctx).QueryRow(ctx, query, visibilityArgs(filter)...).Scan(&count)
+	return count, err
+}
+
+// PurgeDeleted permanently deletes the todos that were moved to the trash before the given time
+func (r *todoRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.conn(ctx).Exec(ctx, `DELETE FROM todos WHERE deleted_at < $1`, before)
+	if err != nil {
+		log.Println("Error purging deleted todos:", err)
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// visibilityClause restricts todos to one list when filter.ListID is set, or else to the personal todos
+// of the user and the todos of every list the user is a member of. Membership of filter.ListID is checked by the caller.
+func visibilityClause(filter models.TodoFilter) string {
+	if filter.ListID != nil {
+		return "list_id = $1"
+	}
+	return "((list_id IS NULL AND user_id = $1) OR list_id IN (SELECT list_id FROM list_members WHERE user_id = $1))"
+}
+
+// visibilityArgs returns the arguments of visibilityClause
+func visibilityArgs(filter models.TodoFilter) []interface{} {
+	if filter.ListID != nil {
+		return []interface{}{*filter.ListID}
+	}
+	return []interface{}{filter.UserID}
+}
+
+// orderByClause maps a sort order to its ORDER BY expression, defaulting to newest first
+func orderByClause(sort models.TodoSortOrder) string {
+	switch sort {
+	case models.SortCreatedAtAsc:
+		return "created_at ASC"
+	case models.SortDeadlineAsc:
+		return "deadline ASC, created_at DESC"
+	case models.SortDeadlineDesc:
+		return "deadline DESC, created_at DESC"
+	case models.SortTitleAsc:
+		return "title ASC, created_at DESC"
+	default:
+		return "created_at DESC"
+	}
 }
@@ -0,0 +1,23 @@
+package token_repository
+
+import (
+	"context"
+	"time"
+
+	"go-backend-todo/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// TokenRepository interface defines methods for personal access tokens
+type TokenRepository interface {
+	Create(ctx context.Context, token *models.PersonalAccessToken, tokenHash string) error
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*models.PersonalAccessToken, error)
+	Delete(ctx context.Context, id, userID uuid.UUID) (bool, error)
+	// DeleteByUserID revokes every token of a user
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+	// Use returns the unexpired token with the given hash and records that it was used, or nil if there is none
+	Use(ctx context.Context, tokenHash string) (*models.PersonalAccessToken, error)
+	// DeleteExpired removes tokens that expired before the given time
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
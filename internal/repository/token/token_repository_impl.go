@@ -0,0 +1,134 @@
+package token_repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go-backend-todo/internal/db"
+	"go-backend-todo/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// tokenRepository implementation of TokenRepository interface
+type tokenRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewTokenRepository create a new instance of token repository
+func NewTokenRepository(db *pgxpool.Pool) TokenRepository {
+	return &tokenRepository{db: db}
+}
+
+// conn returns the transaction of ctx, or the pool outside of one
+func (r *tokenRepository) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, r.db)
+}
+
+// tokenColumns are the columns scanned by scanToken
+const tokenColumns = `id, user_id, name, last_used_at, expires_at, created_at`
+
+// scanToken scans a row selected with tokenColumns
+func scanToken(row pgx.Row) (*models.PersonalAccessToken, error) {
+	var token models.PersonalAccessToken
+	err := row.Scan(&token.ID, &token.UserID, &token.Name, &token.LastUsedAt, &token.ExpiresAt, &token.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Create inserts a personal access token and fills in its ID and timestamp
+func (r *tokenRepository) Create(ctx context.Context, token *models.PersonalAccessToken, tokenHash string) error {
+	query := `
+		INSERT INTO personal_access_tokens (user_id, name, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	err := r.conn(ctx).QueryRow(ctx, query, token.UserID, token.Name, tokenHash, token.ExpiresAt).Scan(&token.ID, &token.CreatedAt)
+	if err != nil {
+		log.Println("Error creating personal access token:", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByUserID retrieves the personal access tokens of a user, newest first
+func (r *tokenRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*models.PersonalAccessToken, error) {
+	query := `SELECT ` + tokenColumns + ` FROM personal_access_tokens WHERE user_id = $1 ORDER BY created_at DESC`
+
+	rows, err := r.conn(ctx).Query(ctx, query, userID)
+	if err != nil {
+		log.Println("Error fetching personal access tokens:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []*models.PersonalAccessToken{}
+	for rows.Next() {
+		token, err := scanToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens, rows.Err()
+}
+
+// Delete revokes a personal access token of a user, reporting whether it existed
+func (r *tokenRepository) Delete(ctx context.Context, id, userID uuid.UUID) (bool, error) {
+	result, err := r.conn(ctx).Exec(ctx, `DELETE FROM personal_access_tokens WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		log.Println("Error deleting personal access token:", err)
+		return false, err
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// DeleteByUserID deletes every personal access token of a user
+func (r *tokenRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result, err := r.conn(ctx).Exec(ctx, `DELETE FROM personal_access_tokens WHERE user_id = $1`, userID)
+	if err != nil {
+		log.Println("Error deleting personal access tokens:", err)
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// Use looks up an unexpired token by its hash and updates its last use time
+func (r *tokenRepository) Use(ctx context.Context, tokenHash string) (*models.PersonalAccessToken, error) {
+	query := `
+		UPDATE personal_access_tokens
+		SET last_used_at = NOW()
+		WHERE token_hash = $1 AND (expires_at IS NULL OR expires_at > NOW())
+		RETURNING ` + tokenColumns
+
+	token, err := scanToken(r.conn(ctx).QueryRow(ctx, query, tokenHash))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		log.Println("Error using personal access token:", err)
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// DeleteExpired deletes the tokens that expired before the given time
+func (r *tokenRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.conn(ctx).Exec(ctx, `DELETE FROM personal_access_tokens WHERE expires_at < $1`, before)
+	if err != nil {
+		log.Println("Error deleting expired personal access tokens:", err)
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,17 @@
+package undo_repository
+
+import (
+	"context"
+
+	"go-backend-todo/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// UndoRepository interface defines methods for the undo actions of destructive todo operations
+type UndoRepository interface {
+	// Create stores an undo action, dropping the expired actions of the same user
+	Create(ctx context.Context, action *models.UndoAction) error
+	// Consume deletes and returns an unexpired undo action of the user, or nil if there is none
+	Consume(ctx context.Context, id, userID uuid.UUID) (*models.UndoAction, error)
+}
@@ -0,0 +1,75 @@
+package undo_repository
+
+import (
+	"context"
+	"log"
+
+	"go-backend-todo/internal/db"
+	"go-backend-todo/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// undoRepository implementation of UndoRepository interface
+type undoRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewUndoRepository create a new instance of undo repository
+func NewUndoRepository(db *pgxpool.Pool) UndoRepository {
+	return &undoRepository{db: db}
+}
+
+// conn returns the transaction of ctx, or the pool outside of one
+func (r *undoRepository) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, r.db)
+}
+
+// Create inserts an undo action and fills in its ID and timestamp
+func (r *undoRepository) Create(ctx context.Context, action *models.UndoAction) error {
+	_, err := r.conn(ctx).Exec(ctx, `DELETE FROM todo_undo_actions WHERE user_id = $1 AND expires_at <= NOW()`, action.UserID)
+	if err != nil {
+		log.Println("Error deleting expired undo actions:", err)
+		return err
+	}
+
+	query := `
+		INSERT INTO todo_undo_actions (user_id, action, todo_ids, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	err = r.conn(ctx).QueryRow(ctx, query, action.UserID, action.Action, action.TodoIDs, action.ExpiresAt).
+		Scan(&action.ID, &action.CreatedAt)
+	if err != nil {
+		log.Println("Error creating undo action:", err)
+		return err
+	}
+
+	return nil
+}
+
+// Consume deletes an unexpired undo action so that it can only be used once
+func (r *undoRepository) Consume(ctx context.Context, id, userID uuid.UUID) (*models.UndoAction, error) {
+	query := `
+		DELETE FROM todo_undo_actions
+		WHERE id = $1 AND user_id = $2 AND expires_at > NOW()
+		RETURNING id, user_id, action, todo_ids, expires_at, created_at
+	`
+
+	var action models.UndoAction
+	err := r.conn(ctx).QueryRow(ctx, query, id, userID).Scan(
+		&action.ID, &action.UserID, &action.Action, &action.TodoIDs, &action.ExpiresAt, &action.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		log.Println("Error consuming undo action:", err)
+		return nil, err
+	}
+
+	return &action, nil
+}
@@ -2,6 +2,7 @@ package user_repository
 
 import (
 	"context"
+	"time"
 
 	"go-backend-todo/internal/models"
 
@@ -16,13 +17,28 @@ type UserRepository interface {
 	GetByEmail(ctx context.Context, email string) (*models.UserProfile, error)
 	GetByUsername(ctx context.Context, username string) (*models.UserProfile, error)
 	Update(ctx context.Context, user *models.UserAccount) error
+	// Delete removes an account with everything it owns. Todos the user added to lists of other users stay in them.
 	Delete(ctx context.Context, id uuid.UUID) error
 	UpdatePassword(ctx context.Context, userID uuid.UUID, newPassword string) error
 
+	// Administrative operations
+	SetRole(ctx context.Context, userID uuid.UUID, role models.UserRoleEnum) error
+	// ConfirmEmail marks the email address of a user as confirmed without a verification token, reporting whether it was unconfirmed
+	ConfirmEmail(ctx context.Context, userID uuid.UUID) (bool, error)
+
 	// Token version operations
 	IncrementTokenVersion(ctx context.Context, userID uuid.UUID) error
 	GetTokenVersion(ctx context.Context, userID uuid.UUID) (int, error)
 
+	// Deletion operations
+	ScheduleDeletion(ctx context.Context, userID uuid.UUID, at time.Time) error
+	// CancelDeletion clears a scheduled deletion, reporting whether one was scheduled
+	CancelDeletion(ctx context.Context, userID uuid.UUID) (bool, error)
+	// GetDueDeletions returns up to limit accounts whose scheduled deletion has passed
+	GetDueDeletions(ctx context.Context, now time.Time, limit int) ([]*models.UserProfile, error)
+	// DeleteIfDue deletes an account like Delete, unless its deletion was cancelled meanwhile
+	DeleteIfDue(ctx context.Context, userID uuid.UUID, now time.Time) (bool, error)
+
 	// Query operations
 	GetAll(ctx context.Context, limit, offset int) ([]*models.UserProfile, error)
 	Count(ctx context.Context) (int64, error)
@@ -2,6 +2,7 @@ package user_repository
 
 import (
 	"context"
+	"go-backend-todo/internal/db"
 	"go-backend-todo/internal/models"
 	"go-backend-todo/internal/utils"
 	"log"
@@ -9,6 +10,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -21,6 +23,11 @@ func NewUserRepository(db *pgxpool.Pool) UserRepository {
 	return &userRepository{db: db}
 }
 
+// conn returns the transaction of ctx, or the pool outside of one
+func (u *userRepository) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, u.db)
+}
+
 // CRUD operations
 func (u *userRepository) Create(ctx context.Context, req *models.RegisterRequest, verificationToken string) error {
 	pw_hash, err := u.HashPassword(req.Password)
@@ -40,7 +47,7 @@ func (u *userRepository) Create(ctx context.Context, req *models.RegisterRequest
 			email_validation_status)
 		VALUES ($1, $2, $3, $4, $5, $6, $7);
 	`
-	_, err = u.db.Exec(ctx, query, req.Username, "user", pw_hash, req.Email, verificationToken, time.Now(), "pending")
+	_, err = u.conn(ctx).Exec(ctx, query, req.Username, "user", pw_hash, req.Email, verificationToken, time.Now(), "pending")
 	if err != nil {
 		log.Println(err)
 		return err
@@ -50,8 +57,8 @@ func (u *userRepository) Create(ctx context.Context, req *models.RegisterRequest
 }
 
 func (u *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.UserProfile, error) {
-	query := "SELECT user_id, user_name, password_hash, email_address, user_role, email_validation_status, COALESCE(token_version, 1), created_at, updated_at FROM user_account WHERE user_id = $1;"
-	row := u.db.QueryRow(ctx, query, id)
+	query := "SELECT user_id, user_name, password_hash, email_address, user_role, email_validation_status, COALESCE(token_version, 1), created_at, updated_at, deletion_scheduled_at FROM user_account WHERE user_id = $1;"
+	row := u.conn(ctx).QueryRow(ctx, query, id)
 	var user models.UserProfile
 	err := row.Scan(
 		&user.UserID,
@@ -63,6 +70,7 @@ func (u *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Use
 		&user.TokenVersion,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.DeletionScheduledAt,
 	)
 	if err != nil {
 		if err.Error() == "no rows in result set" {
@@ -74,18 +82,201 @@ func (u *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Use
 	return &user, nil
 }
 func (u *userRepository) GetByEmail(ctx context.Context, email string) (*models.UserProfile, error) {
-	return nil, nil
+	query := "SELECT user_id, user_name, password_hash, email_address, user_role, email_validation_status, COALESCE(token_version, 1), created_at, updated_at, deletion_scheduled_at FROM user_account WHERE email_address = $1;"
+	row := u.conn(ctx).QueryRow(ctx, query, email)
+	var user models.UserProfile
+	err := row.Scan(
+		&user.UserID,
+		&user.Username,
+		&user.PasswordHash,
+		&user.Email,
+		&user.Role,
+		&user.Status,
+		&user.TokenVersion,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.DeletionScheduledAt,
+	)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, utils.ErrUserNotFound("User not found")
+		}
+		log.Println("Error fetching user by email:", err)
+		return nil, err
+	}
+	return &user, nil
 }
 func (u *userRepository) GetByUsername(ctx context.Context, username string) (*models.UserProfile, error) {
-	return nil, nil
+	query := "SELECT user_id, user_name, password_hash, email_address, user_role, email_validation_status, COALESCE(token_version, 1), created_at, updated_at, deletion_scheduled_at FROM user_account WHERE user_name = $1;"
+	row := u.conn(ctx).QueryRow(ctx, query, username)
+	var user models.UserProfile
+	err := row.Scan(
+		&user.UserID,
+		&user.Username,
+		&user.PasswordHash,
+		&user.Email,
+		&user.Role,
+		&user.Status,
+		&user.TokenVersion,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.DeletionScheduledAt,
+	)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, utils.ErrUserNotFound("User not found")
+		}
+		log.Println("Error fetching user by username:", err)
+		return nil, err
+	}
+	return &user, nil
 }
 func (u *userRepository) Update(ctx context.Context, user *models.UserAccount) error {
 	return nil
 }
 func (u *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	deleted, err := u.deleteAccount(ctx, id, nil)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return utils.ErrUserNotFound("User not found")
+	}
+	return nil
+}
+
+// Administrative operations
+func (u *userRepository) SetRole(ctx context.Context, userID uuid.UUID, role models.UserRoleEnum) error {
+	query := `UPDATE user_account SET user_role = $2, updated_at = NOW() WHERE user_id = $1`
+	result, err := u.conn(ctx).Exec(ctx, query, userID, string(role))
+	if err != nil {
+		log.Println("Error setting user role:", err)
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return utils.ErrUserNotFound("User not found")
+	}
 	return nil
 }
 
+func (u *userRepository) ConfirmEmail(ctx context.Context, userID uuid.UUID) (bool, error) {
+	query := `
+		UPDATE user_account
+		SET email_validation_status = 'confirmed'::email_validation_status_enum,
+			verification_token = NULL, verification_token_generation_time = NULL, updated_at = NOW()
+		WHERE user_id = $1 AND email_validation_status <> 'confirmed'
+	`
+	result, err := u.conn(ctx).Exec(ctx, query, userID)
+	if err != nil {
+		log.Println("Error confirming email:", err)
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// Deletion operations
+func (u *userRepository) ScheduleDeletion(ctx context.Context, userID uuid.UUID, at time.Time) error {
+	query := `UPDATE user_account SET deletion_scheduled_at = $2, updated_at = NOW() WHERE user_id = $1`
+	result, err := u.conn(ctx).Exec(ctx, query, userID, at)
+	if err != nil {
+		log.Println("Error scheduling account deletion:", err)
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return utils.ErrUserNotFound("User not found")
+	}
+	return nil
+}
+
+func (u *userRepository) CancelDeletion(ctx context.Context, userID uuid.UUID) (bool, error) {
+	query := `
+		UPDATE user_account SET deletion_scheduled_at = NULL, updated_at = NOW()
+		WHERE user_id = $1 AND deletion_scheduled_at IS NOT NULL
+	`
+	result, err := u.conn(ctx).Exec(ctx, query, userID)
+	if err != nil {
+		log.Println("Error cancelling account deletion:", err)
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+func (u *userRepository) GetDueDeletions(ctx context.Context, now time.Time, limit int) ([]*models.UserProfile, error) {
+	query := `
+		SELECT user_id, user_name, email_address, deletion_scheduled_at
+		FROM user_account
+		WHERE deletion_scheduled_at <= $1
+		ORDER BY deletion_scheduled_at
+		LIMIT $2
+	`
+	rows, err := u.conn(ctx).Query(ctx, query, now, limit)
+	if err != nil {
+		log.Println("Error fetching due account deletions:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*models.UserProfile
+	for rows.Next() {
+		var user models.UserProfile
+		if err := rows.Scan(&user.UserID, &user.Username, &user.Email, &user.DeletionScheduledAt); err != nil {
+			return nil, err
+		}
+		users = append(users, &user)
+	}
+	return users, rows.Err()
+}
+
+func (u *userRepository) DeleteIfDue(ctx context.Context, userID uuid.UUID, now time.Time) (bool, error) {
+	return u.deleteAccount(ctx, userID, &now)
+}
+
+// deleteAccount deletes an account in one transaction, only if its deletion is due at dueAt when given.
+// Foreign keys cascade to everything the account owns, including its lists with their todos. Todos it
+// added to lists owned by others are handed to the list owner first, so shared lists keep their items.
+func (u *userRepository) deleteAccount(ctx context.Context, userID uuid.UUID, dueAt *time.Time) (bool, error) {
+	tx, err := u.conn(ctx).Begin(ctx)
+	if err != nil {
+		log.Println("Error starting account deletion:", err)
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the account so a concurrent login cannot cancel the deletion halfway
+	lockQuery := `SELECT 1 FROM user_account WHERE user_id = $1 AND ($2::timestamptz IS NULL OR deletion_scheduled_at <= $2) FOR UPDATE`
+	var found int
+	if err := tx.QueryRow(ctx, lockQuery, userID, dueAt).Scan(&found); err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+		log.Println("Error locking account for deletion:", err)
+		return false, err
+	}
+
+	reassignQuery := `
+		UPDATE todos t SET user_id = l.owner_id, updated_at = NOW()
+		FROM lists l
+		WHERE t.list_id = l.id AND t.user_id = $1 AND l.owner_id <> $1
+	`
+	if _, err := tx.Exec(ctx, reassignQuery, userID); err != nil {
+		log.Println("Error handing over shared todos:", err)
+		return false, err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM user_account WHERE user_id = $1`, userID); err != nil {
+		log.Println("Error deleting account:", err)
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Println("Error committing account deletion:", err)
+		return false, err
+	}
+	return true, nil
+}
+
 // Query operations
 func (u *userRepository) GetAll(ctx context.Context, limit, offset int) ([]*models.UserProfile, error) {
 	return nil, nil
@@ -98,7 +289,7 @@ func (u *userRepository) Count(ctx context.Context) (int64, error) {
 func (u *userRepository) EmailExists(ctx context.Context, email string) (bool, error) {
 	query := "SELECT EXISTS(SELECT 1 FROM user_account WHERE email_address = $1);"
 	var exists bool
-	err := u.db.QueryRow(ctx, query, email).Scan(&exists)
+	err := u.conn(ctx).QueryRow(ctx, query, email).Scan(&exists)
 	if err != nil {
 		log.Println("Error checking email existence:", err)
 		return false, err
@@ -109,7 +300,7 @@ func (u *userRepository) EmailExists(ctx context.Context, email string) (bool, e
 func (u *userRepository) UsernameExists(ctx context.Context, username string) (bool, error) {
 	query := "SELECT EXISTS(SELECT 1 FROM user_account WHERE user_name = $1);"
 	var exists bool
-	err := u.db.QueryRow(ctx, query, username).Scan(&exists)
+	err := u.conn(ctx).QueryRow(ctx, query, username).Scan(&exists)
 	if err != nil {
 		log.Println("Error checking username existence:", err)
 		return false, err
@@ -120,7 +311,7 @@ func (u *userRepository) UsernameExists(ctx context.Context, username string) (b
 func (u *userRepository) AccountStatusValidation(ctx context.Context, userID uuid.UUID) (bool, error) {
 	query := "SELECT email_validation_status FROM user_account WHERE user_id = $1;"
 	var status string
-	err := u.db.QueryRow(ctx, query, userID).Scan(&status)
+	err := u.conn(ctx).QueryRow(ctx, query, userID).Scan(&status)
 	if err != nil {
 		log.Println("Error checking account status:", err)
 		return false, err
@@ -133,7 +324,7 @@ func (u *userRepository) AccountStatusValidation(ctx context.Context, userID uui
 }
 
 func (u *userRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, newPassword string) error {
-	tx, err := u.db.Begin(ctx)
+	tx, err := u.conn(ctx).Begin(ctx)
 	if err != nil {
 		return utils.ErrInternalServerError("Failed to start transaction")
 	}
@@ -156,13 +347,20 @@ func (u *userRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, n
 		return utils.ErrUserNotFound("User not found")
 	}
 
+	// Personal access tokens were granted with the old password and do not outlive it
+	_, err = tx.Exec(ctx, `DELETE FROM personal_access_tokens WHERE user_id = $1`, userID)
+	if err != nil {
+		log.Println("Error revoking personal access tokens:", err)
+		return utils.ErrInternalServerError("Failed to update password")
+	}
+
 	return tx.Commit(ctx)
 }
 
 // Token version operations
 func (u *userRepository) IncrementTokenVersion(ctx context.Context, userID uuid.UUID) error {
 	query := `UPDATE user_account SET token_version = token_version + 1 WHERE user_id = $1`
-	result, err := u.db.Exec(ctx, query, userID)
+	result, err := u.conn(ctx).Exec(ctx, query, userID)
 	if err != nil {
 		log.Println("Error incrementing token version:", err)
 		return utils.ErrInternalServerError("Failed to increment token version")
@@ -178,7 +376,7 @@ func (u *userRepository) IncrementTokenVersion(ctx context.Context, userID uuid.
 func (u *userRepository) GetTokenVersion(ctx context.Context, userID uuid.UUID) (int, error) {
 	query := `SELECT COALESCE(token_version, 1) FROM user_account WHERE user_id = $1`
 	var version int
-	err := u.db.QueryRow(ctx, query, userID).Scan(&version)
+	err := u.conn(ctx).QueryRow(ctx, query, userID).Scan(&version)
 	if err != nil {
 		log.Println("Error getting token version:", err)
 		return 0, utils.ErrInternalServerError("Failed to get token version")
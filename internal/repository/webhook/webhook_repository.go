@@ -0,0 +1,33 @@
+package webhook_repository
+
+import (
+	"context"
+	"time"
+
+	"go-backend-todo/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// WebhookRepository interface defines methods for interacting with webhook and delivery data
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *models.Webhook) error
+	GetByID(ctx context.Context, id, userID uuid.UUID) (*models.Webhook, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Webhook, error)
+	Update(ctx context.Context, webhook *models.Webhook) error
+	Delete(ctx context.Context, id, userID uuid.UUID) error
+
+	// GetActiveForEvent returns the active webhooks of the users subscribed to the event type
+	GetActiveForEvent(ctx context.Context, userIDs []uuid.UUID, eventType models.TodoEventType) ([]*models.Webhook, error)
+
+	CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	GetDeliveries(ctx context.Context, webhookID uuid.UUID, limit, offset int) ([]*models.WebhookDelivery, error)
+	CountDeliveries(ctx context.Context, webhookID uuid.UUID) (int64, error)
+
+	// ClaimDueDeliveries leases up to limit pending deliveries for leaseFor and increments their attempt count.
+	// A delivery whose lease expires without being marked is picked up again by the next poll.
+	ClaimDueDeliveries(ctx context.Context, now time.Time, leaseFor time.Duration, limit int) ([]*models.DueWebhookDelivery, error)
+	MarkSucceeded(ctx context.Context, id uuid.UUID, responseStatus int) error
+	// MarkFailed records a failed attempt, either scheduling a retry at nextAttemptAt or giving up when nextAttemptAt is nil
+	MarkFailed(ctx context.Context, id uuid.UUID, responseStatus *int, lastError string, nextAttemptAt *time.Time) error
+}
@@ -0,0 +1,285 @@
+package webhook_repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go-backend-todo/internal/db"
+	"go-backend-todo/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// webhookRepository implementation of WebhookRepository interface
+type webhookRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewWebhookRepository create a new instance of webhook repository
+func NewWebhookRepository(db *pgxpool.Pool) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+// conn returns the transaction of ctx, or the pool outside of one
+func (r *webhookRepository) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, r.db)
+}
+
+const webhookColumns = `id, user_id, url, secret, events, description, active, created_at, updated_at`
+
+// scanWebhook scans a row selected with webhookColumns
+func scanWebhook(row pgx.Row) (*models.Webhook, error) {
+	var webhook models.Webhook
+	var events []string
+	err := row.Scan(
+		&webhook.ID, &webhook.UserID, &webhook.URL, &webhook.Secret, &events,
+		&webhook.Description, &webhook.Active, &webhook.CreatedAt, &webhook.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.Events = make([]models.TodoEventType, len(events))
+	for i, event := range events {
+		webhook.Events[i] = models.TodoEventType(event)
+	}
+	return &webhook, nil
+}
+
+// eventStrings converts event types to a text array parameter
+func eventStrings(events []models.TodoEventType) []string {
+	result := make([]string, len(events))
+	for i, event := range events {
+		result[i] = string(event)
+	}
+	return result
+}
+
+// Create creates a new webhook
+func (r *webhookRepository) Create(ctx context.Context, webhook *models.Webhook) error {
+	query := `
+		INSERT INTO webhooks (id, user_id, url, secret, events, description, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := r.conn(ctx).Exec(ctx, query,
+		webhook.ID, webhook.UserID, webhook.URL, webhook.Secret, eventStrings(webhook.Events),
+		webhook.Description, webhook.Active, webhook.CreatedAt, webhook.UpdatedAt,
+	)
+	if err != nil {
+		log.Println("Error creating webhook:", err)
+	}
+	return err
+}
+
+// GetByID retrieves a webhook owned by a user, or nil if it does not exist
+func (r *webhookRepository) GetByID(ctx context.Context, id, userID uuid.UUID) (*models.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE id = $1 AND user_id = $2`
+
+	webhook, err := scanWebhook(r.conn(ctx).QueryRow(ctx, query, id, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// GetByUserID retrieves all webhooks of a user
+func (r *webhookRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE user_id = $1 ORDER BY created_at DESC`
+	return r.queryWebhooks(ctx, query, userID)
+}
+
+// GetActiveForEvent retrieves the active webhooks of the users subscribed to an event type
+func (r *webhookRepository) GetActiveForEvent(ctx context.Context, userIDs []uuid.UUID, eventType models.TodoEventType) ([]*models.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE user_id = ANY($1) AND active = TRUE AND $2 = ANY(events)`
+	return r.queryWebhooks(ctx, query, userIDs, string(eventType))
+}
+
+// queryWebhooks runs a webhook query and scans all rows
+func (r *webhookRepository) queryWebhooks(ctx context.Context, query string, args ...any) ([]*models.Webhook, error) {
+	rows, err := r.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []*models.Webhook{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+// Update updates the url, events, description and active flag of a webhook
+func (r *webhookRepository) Update(ctx context.Context, webhook *models.Webhook) error {
+	query := `
+		UPDATE webhooks
+		SET url = $3, events = $4, description = $5, active = $6, updated_at = $7
+		WHERE id = $1 AND user_id = $2
+	`
+
+	_, err := r.conn(ctx).Exec(ctx, query,
+		webhook.ID, webhook.UserID, webhook.URL, eventStrings(webhook.Events),
+		webhook.Description, webhook.Active, webhook.UpdatedAt,
+	)
+	if err != nil {
+		log.Println("Error updating webhook:", err)
+	}
+	return err
+}
+
+// Delete deletes a webhook owned by a user together with its deliveries
+func (r *webhookRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	_, err := r.conn(ctx).Exec(ctx, `DELETE FROM webhooks WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		log.Println("Error deleting webhook:", err)
+	}
+	return err
+}
+
+// CreateDelivery queues a delivery for its first attempt
+func (r *webhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (id, webhook_id, event_id, event_type, payload, status, attempts, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := r.conn(ctx).Exec(ctx, query,
+		delivery.ID, delivery.WebhookID, delivery.EventID, string(delivery.EventType), delivery.Payload,
+		string(delivery.Status), delivery.Attempts, delivery.NextAttemptAt, delivery.CreatedAt,
+	)
+	if err != nil {
+		log.Println("Error creating webhook delivery:", err)
+	}
+	return err
+}
+
+// GetDeliveries retrieves the deliveries of a webhook, newest first
+func (r *webhookRepository) GetDeliveries(ctx context.Context, webhookID uuid.UUID, limit, offset int) ([]*models.WebhookDelivery, error) {
+	query := `
+		SELECT id, webhook_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+			response_status, last_error, created_at, delivered_at
+		FROM webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, webhookID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []*models.WebhookDelivery{}
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+		err := rows.Scan(
+			&delivery.ID, &delivery.WebhookID, &delivery.EventID, &delivery.EventType, &delivery.Payload,
+			&delivery.Status, &delivery.Attempts, &delivery.NextAttemptAt, &delivery.ResponseStatus,
+			&delivery.LastError, &delivery.CreatedAt, &delivery.DeliveredAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// CountDeliveries counts the deliveries of a webhook
+func (r *webhookRepository) CountDeliveries(ctx context.Context, webhookID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.conn(ctx).QueryRow(ctx, `SELECT COUNT(*) FROM webhook_deliveries WHERE webhook_id = $1`, webhookID).Scan(&count)
+	return count, err
+}
+
+// ClaimDueDeliveries leases due deliveries of active webhooks by pushing next_attempt_at past the lease.
+// SKIP LOCKED lets concurrent dispatchers pick disjoint batches; a dispatcher that crashes mid-delivery
+// only delays the retry until the lease expires.
+func (r *webhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, leaseFor time.Duration, limit int) ([]*models.DueWebhookDelivery, error) {
+	query := `
+		WITH due AS (
+			SELECT d.id
+			FROM webhook_deliveries d
+			JOIN webhooks w ON w.id = d.webhook_id
+			WHERE d.status = 'pending' AND d.next_attempt_at <= $1 AND w.active = TRUE
+			ORDER BY d.next_attempt_at
+			LIMIT $3
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET attempts = d.attempts + 1, next_attempt_at = $2
+		FROM due, webhooks w
+		WHERE d.id = due.id AND w.id = d.webhook_id
+		RETURNING d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+			d.next_attempt_at, d.created_at, w.url, w.secret
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, now, now.Add(leaseFor), limit)
+	if err != nil {
+		log.Println("Error claiming due webhook deliveries:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*models.DueWebhookDelivery
+	for rows.Next() {
+		var delivery models.DueWebhookDelivery
+		err := rows.Scan(
+			&delivery.ID, &delivery.WebhookID, &delivery.EventID, &delivery.EventType, &delivery.Payload,
+			&delivery.Status, &delivery.Attempts, &delivery.NextAttemptAt, &delivery.CreatedAt,
+			&delivery.URL, &delivery.Secret,
+		)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// MarkSucceeded marks a delivery as delivered
+func (r *webhookRepository) MarkSucceeded(ctx context.Context, id uuid.UUID, responseStatus int) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'succeeded', response_status = $2, last_error = NULL, delivered_at = NOW()
+		WHERE id = $1
+	`
+
+	_, err := r.conn(ctx).Exec(ctx, query, id, responseStatus)
+	if err != nil {
+		log.Println("Error marking webhook delivery as succeeded:", err)
+	}
+	return err
+}
+
+// MarkFailed records a failed attempt and either schedules the next one or marks the delivery as failed
+func (r *webhookRepository) MarkFailed(ctx context.Context, id uuid.UUID, responseStatus *int, lastError string, nextAttemptAt *time.Time) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = CASE WHEN $4::timestamptz IS NULL THEN 'failed' ELSE 'pending' END,
+			response_status = $2, last_error = $3, next_attempt_at = COALESCE($4, next_attempt_at)
+		WHERE id = $1
+	`
+
+	_, err := r.conn(ctx).Exec(ctx, query, id, responseStatus, lastError, nextAttemptAt)
+	if err != nil {
+		log.Println("Error marking webhook delivery as failed:", err)
+	}
+	return err
+}
@@ -1,56 +1,215 @@
 package routes
 
 import (
+	"context"
+	"time"
+
 	"go-backend-todo/internal/api/handlers"
 	"go-backend-todo/internal/api/middlewares"
 	"go-backend-todo/internal/config"
+	"go-backend-todo/internal/db"
+	"go-backend-todo/internal/metrics"
+	"go-backend-todo/internal/realtime"
+	audit_repository "go-backend-todo/internal/repository/audit"
 	auth_repository "go-backend-todo/internal/repository/auth"
+	comment_repository "go-backend-todo/internal/repository/comment"
+	export_repository "go-backend-todo/internal/repository/export"
+	history_repository "go-backend-todo/internal/repository/history"
+	list_repository "go-backend-todo/internal/repository/list"
+	preference_repository "go-backend-todo/internal/repository/preference"
+	reminder_repository "go-backend-todo/internal/repository/reminder"
 	todo_repository "go-backend-todo/internal/repository/todo"
+	token_repository "go-backend-todo/internal/repository/token"
+	undo_repository "go-backend-todo/internal/repository/undo"
 	user_repository "go-backend-todo/internal/repository/user"
+	webhook_repository "go-backend-todo/internal/repository/webhook"
 	"go-backend-todo/internal/service"
+	"go-backend-todo/internal/worker"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	fiberSwagger "github.com/swaggo/fiber-swagger"
 )
 
-// SetupRoutes sets up all routes for the application
-func SetupRoutes(app *fiber.App, cfg *config.Config, pool *pgxpool.Pool) {
+// quietPaths are polled by load balancers, orchestrators and Prometheus, so their requests are not logged
+var quietPaths = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+	"/version": true,
+	"/metrics": true,
+}
+
+// SetupRoutes sets up all routes for the application and starts background workers under supervisor.
+// Reads that tolerate replication lag go through router, and readiness compares the schema with migrator.
+// The returned health service reports readiness.
+func SetupRoutes(app *fiber.App, cfg *config.Config, pool *pgxpool.Pool, router *db.Router, migrator *db.Migrator, supervisor *worker.Supervisor) service.HealthService {
 	// Global middleware
-	app.Use(logger.New())
+	app.Use(middlewares.Metrics())
+	app.Use(logger.New(logger.Config{
+		Next: func(c *fiber.Ctx) bool {
+			return quietPaths[c.Path()]
+		},
+	}))
 	app.Use(recover.New())
 	app.Use(cors.New(config.GetCORSConfig(cfg)))
+	app.Use(middlewares.ClientInfo())
+	if router.HasReplicas() {
+		app.Use(middlewares.ReadYourWrites(router))
+	}
 
 	// Initialize repositories
-	todoRepo := todo_repository.NewTodoRepository(pool)
+	todoRepo := todo_repository.NewTodoRepository(pool, router)
 	userRepo := user_repository.NewUserRepository(pool)
 	authRepo := auth_repository.NewAuthRepository(pool)
+	reminderRepo := reminder_repository.NewReminderRepository(pool)
+	preferenceRepo := preference_repository.NewPreferenceRepository(pool)
+	webhookRepo := webhook_repository.NewWebhookRepository(pool)
+	listRepo := list_repository.NewListRepository(pool)
+	commentRepo := comment_repository.NewCommentRepository(pool)
+	historyRepo := history_repository.NewHistoryRepository(pool)
+	auditRepo := audit_repository.NewAuditRepository(pool)
+	undoRepo := undo_repository.NewUndoRepository(pool)
+	tokenRepo := token_repository.NewTokenRepository(pool)
+	exportRepo := export_repository.NewExportRepository(pool)
+
+	// Real-time hub fed by Postgres notifications from every instance
+	streamHub := realtime.NewHub(cfg.Stream.ClientBufferSize)
+	streamListener := realtime.NewListener(pool, streamHub)
+
+	// Units of work spanning several repositories
+	txManager := db.NewTxManager(pool)
+
+	// Security audit log, written by the JWT manager and the account services
+	auditService := service.NewAuditService(auditRepo)
 
 	// Initialize JWT manager with userRepo
-	jwtManager := middlewares.NewJWTManager(cfg, userRepo)
+	jwtManager := middlewares.NewJWTManager(cfg, userRepo, auditService)
 
 	// Initialize services
 	emailService := service.NewEmailService(cfg)
-	todoService := service.NewTodoService(todoRepo)
-	userService := service.NewUserService(userRepo)
-	authService := service.NewAuthService(userRepo, authRepo, emailService, cfg)
+	preferenceService := service.NewPreferenceService(preferenceRepo, cfg)
+	reminderService := service.NewReminderService(reminderRepo, emailService, cfg)
+	webhookService := service.NewWebhookService(webhookRepo, cfg)
+	listService := service.NewListService(listRepo, userRepo, emailService, cfg)
+	eventPublisher := service.NewMultiPublisher(webhookService, realtime.NewNotifyPublisher(pool, streamHub))
+	notificationService := service.NewNotificationService(userRepo, preferenceService, emailService, cfg)
+	todoService := service.NewTodoService(todoRepo, historyRepo, undoRepo, listService, reminderService, preferenceService, notificationService, eventPublisher, txManager, cfg)
+	commentService := service.NewCommentService(commentRepo, userRepo, todoService, notificationService)
+	userService := service.NewUserService(userRepo, tokenRepo, auditService, emailService, txManager, cfg)
+	authService := service.NewAuthService(userRepo, authRepo, emailService, auditService, txManager, cfg)
+	digestService := service.NewDigestService(preferenceRepo, todoRepo, emailService, cfg)
+	tokenService := service.NewTokenService(tokenRepo, userRepo, auditService)
+	caldavService := service.NewCalDAVService(todoRepo, todoService, preferenceService, cfg)
+	healthService := service.NewHealthService(pool, migrator, emailService)
+	exportService := service.NewExportService(exportRepo, userRepo, todoRepo, commentRepo, listService, preferenceService, auditService, emailService, cfg)
 
 	// Initialize handlers
-	todoHandler := handlers.NewTodoHandler(todoService)
+	todoHandler := handlers.NewTodoHandler(todoService, cfg)
 	userHandler := handlers.NewUserHandler(userService)
 	authHandler := handlers.NewAuthHandler(authService, jwtManager)
+	digestHandler := handlers.NewDigestHandler(digestService)
+	preferenceHandler := handlers.NewPreferenceHandler(preferenceService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	streamHandler := handlers.NewStreamHandler(streamHub, cfg)
+	listHandler := handlers.NewListHandler(listService)
+	commentHandler := handlers.NewCommentHandler(commentService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+	tokenHandler := handlers.NewTokenHandler(tokenService)
+	caldavHandler := handlers.NewCalDAVHandler(caldavService, cfg.App.Name)
+	exportHandler := handlers.NewExportHandler(exportService)
+	healthHandler := handlers.NewHealthHandler(healthService)
+
+	// Probes for load balancers and orchestrators
+	setupHealthRoutes(app, healthHandler)
+	// Prefork children each count their own requests, so successive scrapes would jump between unrelated series
+	if !cfg.Server.Prefork {
+		setupMetricsRoutes(app, router)
+	}
 
 	// API routes
-	setupAPIRoutes(app, todoHandler, userHandler, authHandler, jwtManager)
+	setupAPIRoutes(app, todoHandler, userHandler, authHandler, digestHandler, preferenceHandler, webhookHandler, streamHandler, listHandler, commentHandler, auditHandler, tokenHandler, exportHandler, jwtManager)
+
+	// CalDAV routes for task clients, outside of the versioned API
+	if cfg.DAV.Enabled {
+		setupDAVRoutes(app, caldavHandler, middlewares.AuthenticateBasicOrToken(tokenService, cfg.App.Name, cfg.DAV.AllowPasswordAuth))
+	}
+
+	// Background workers
+	setupWorkers(supervisor, cfg, todoService, userService, reminderService, digestService, webhookService, exportService, streamListener, router)
 
 	// Swagger documentation
 	app.Get("/swagger/*", fiberSwagger.WrapHandler)
 
 	// 404 handler
 	app.Use(middlewares.NotFound)
+
+	return healthService
+}
+
+// setupWorkers starts background workers that stop with the supervisor
+func setupWorkers(supervisor *worker.Supervisor, cfg *config.Config, todoService service.TodoService, userService service.UserService, reminderService service.ReminderService, digestService service.DigestService, webhookService service.WebhookService, exportService service.ExportService, streamListener worker.Worker, router *db.Router) {
+	workers := []worker.Worker{streamListener}
+
+	if router.HasReplicas() {
+		// Replicas changing health are logged by the check itself
+		workers = append(workers, worker.NewPeriodic("replica-health", time.Duration(cfg.Database.ReplicaCheckInterval)*time.Second, func(ctx context.Context) error {
+			router.CheckReplicas(ctx)
+			return nil
+		}))
+	}
+
+	if cfg.Reminder.Enabled {
+		workers = append(workers, worker.NewPeriodic("reminders", time.Duration(cfg.Reminder.PollInterval)*time.Second, func(ctx context.Context) error {
+			_, err := reminderService.ProcessDueReminders(ctx)
+			return err
+		}))
+	}
+
+	if cfg.Digest.Enabled {
+		workers = append(workers, worker.NewPeriodic("digests", time.Duration(cfg.Digest.PollInterval)*time.Second, func(ctx context.Context) error {
+			_, err := digestService.SendDueDigests(ctx)
+			return err
+		}))
+	}
+
+	if cfg.Webhook.Enabled {
+		workers = append(workers, worker.NewPeriodic("webhooks", time.Duration(cfg.Webhook.PollInterval)*time.Second, func(ctx context.Context) error {
+			_, err := webhookService.ProcessDueDeliveries(ctx)
+			return err
+		}))
+	}
+
+	if cfg.Trash.PurgeEnabled {
+		retention := time.Duration(cfg.Trash.RetentionDays) * 24 * time.Hour
+		workers = append(workers, worker.NewPeriodic("trash-purge", time.Duration(cfg.Trash.PurgeInterval)*time.Second, func(ctx context.Context) error {
+			_, err := todoService.PurgeTrash(ctx, retention)
+			return err
+		}))
+	}
+
+	if cfg.Account.PurgeEnabled {
+		workers = append(workers, worker.NewPeriodic("account-purge", time.Duration(cfg.Account.PurgeInterval)*time.Second, func(ctx context.Context) error {
+			_, err := userService.PurgeScheduledDeletions(ctx)
+			return err
+		}))
+	}
+
+	if cfg.Export.Enabled {
+		workers = append(workers, worker.NewPeriodic("data-exports", time.Duration(cfg.Export.PollInterval)*time.Second, func(ctx context.Context) error {
+			if _, err := exportService.ProcessPendingExports(ctx); err != nil {
+				return err
+			}
+			_, err := exportService.PurgeExpiredExports(ctx)
+			return err
+		}))
+	}
+
+	supervisor.Start(workers...)
 }
 
 // setupAPIRoutes sets up API routes with handlers
@@ -59,32 +218,83 @@ func setupAPIRoutes(
 	todoHandler *handlers.TodoHandler,
 	userHandler *handlers.UserHandler,
 	authHandler *handlers.AuthHandler,
+	digestHandler *handlers.DigestHandler,
+	preferenceHandler *handlers.PreferenceHandler,
+	webhookHandler *handlers.WebhookHandler,
+	streamHandler *handlers.StreamHandler,
+	listHandler *handlers.ListHandler,
+	commentHandler *handlers.CommentHandler,
+	auditHandler *handlers.AuditHandler,
+	tokenHandler *handlers.TokenHandler,
+	exportHandler *handlers.ExportHandler,
 	jwtManager *middlewares.JWTManager,
 ) {
 	// API group v1
 	api := app.Group("/api/v1")
 
 	// Setup routes with dependency injection
-	setupTodoRoutes(api, todoHandler, jwtManager)
-	setupUserRoutes(api, userHandler, jwtManager)
+	setupTodoRoutes(api, todoHandler, commentHandler, jwtManager)
+	setupListRoutes(api, listHandler, jwtManager)
+	setupUserRoutes(api, userHandler, preferenceHandler, webhookHandler, auditHandler, tokenHandler, exportHandler, jwtManager)
+	setupAdminRoutes(api, auditHandler, jwtManager)
 	setupAuthRoutes(api, authHandler, jwtManager)
+	setupDigestRoutes(api, digestHandler)
+	setupExportRoutes(api, exportHandler)
+	setupStreamRoutes(api, streamHandler, jwtManager)
 }
 
 // setupTodoRoutes sets up todo-related routes with dependency injection
-func setupTodoRoutes(api fiber.Router, todoHandler *handlers.TodoHandler, jwtManager *middlewares.JWTManager) {
+func setupTodoRoutes(api fiber.Router, todoHandler *handlers.TodoHandler, commentHandler *handlers.CommentHandler, jwtManager *middlewares.JWTManager) {
 	todos := api.Group("/todos")
 
 	todos.Use(middlewares.AuthenticateJWT(jwtManager)) 
 
 	todos.Get("/", todoHandler.GetTodos)
 	todos.Post("/", todoHandler.CreateTodo)
+	todos.Get("/stats", todoHandler.GetTodoStats)
+	todos.Get("/export", todoHandler.ExportTodos)
+	todos.Post("/import", todoHandler.ImportTodos)
+	todos.Get("/trash", todoHandler.GetTrash)
+	todos.Post("/complete", todoHandler.CompleteTodos)
+	todos.Delete("/completed", todoHandler.DeleteCompletedTodos)
+	todos.Post("/undo", todoHandler.UndoTodoAction)
 	todos.Get("/:id", todoHandler.GetTodo)
 	todos.Put("/:id", todoHandler.UpdateTodo)
 	todos.Delete("/:id", todoHandler.DeleteTodo)
+	todos.Post("/:id/restore", todoHandler.RestoreTodo)
+	todos.Get("/:id/reminders", todoHandler.GetTodoReminders)
+	todos.Put("/:id/reminders", todoHandler.UpdateTodoReminders)
+	todos.Put("/:id/assignee", todoHandler.AssignTodo)
+	todos.Get("/:id/history", todoHandler.GetTodoHistory)
+	todos.Get("/:id/comments", commentHandler.GetComments)
+	todos.Post("/:id/comments", commentHandler.CreateComment)
+	todos.Put("/:id/comments/:commentId", commentHandler.UpdateComment)
+	todos.Delete("/:id/comments/:commentId", commentHandler.DeleteComment)
+}
+
+// setupListRoutes sets up shared list routes with dependency injection
+func setupListRoutes(api fiber.Router, listHandler *handlers.ListHandler, jwtManager *middlewares.JWTManager) {
+	lists := api.Group("/lists")
+
+	lists.Use(middlewares.AuthenticateJWT(jwtManager))
+
+	lists.Get("/", listHandler.GetLists)
+	lists.Post("/", listHandler.CreateList)
+	lists.Get("/invitations/:token", listHandler.GetInvitation)
+	lists.Post("/invitations/:token/accept", listHandler.AcceptInvitation)
+	lists.Get("/:id", listHandler.GetList)
+	lists.Put("/:id", listHandler.UpdateList)
+	lists.Delete("/:id", listHandler.DeleteList)
+	lists.Get("/:id/members", listHandler.GetMembers)
+	lists.Put("/:id/members/:userId", listHandler.UpdateMember)
+	lists.Delete("/:id/members/:userId", listHandler.RemoveMember)
+	lists.Get("/:id/invitations", listHandler.GetInvitations)
+	lists.Post("/:id/invitations", listHandler.InviteMember)
+	lists.Delete("/:id/invitations/:invitationId", listHandler.RevokeInvitation)
 }
 
 // setupUserRoutes sets up user-related routes with dependency injection
-func setupUserRoutes(api fiber.Router, userHandler *handlers.UserHandler, jwtManager *middlewares.JWTManager) {
+func setupUserRoutes(api fiber.Router, userHandler *handlers.UserHandler, preferenceHandler *handlers.PreferenceHandler, webhookHandler *handlers.WebhookHandler, auditHandler *handlers.AuditHandler, tokenHandler *handlers.TokenHandler, exportHandler *handlers.ExportHandler, jwtManager *middlewares.JWTManager) {
 	users := api.Group("/users")
 
 	users.Use(middlewares.AuthenticateJWT(jwtManager)) 
@@ -93,6 +303,85 @@ func setupUserRoutes(api fiber.Router, userHandler *handlers.UserHandler, jwtMan
 	users.Put("/profile", userHandler.UpdateUserProfile)
 	users.Delete("/profile", userHandler.DeleteUserProfile)
 	users.Put("/change-password", userHandler.ChangePassword)
+	users.Get("/security-events", auditHandler.GetSecurityEvents)
+	users.Get("/preferences", preferenceHandler.GetPreferences)
+	users.Put("/preferences", preferenceHandler.UpdatePreferences)
+	users.Get("/digest", preferenceHandler.GetDigestSettings)
+	users.Put("/digest", preferenceHandler.UpdateDigestSettings)
+	users.Get("/webhooks", webhookHandler.GetWebhooks)
+	users.Post("/webhooks", webhookHandler.CreateWebhook)
+	users.Get("/webhooks/:id", webhookHandler.GetWebhook)
+	users.Put("/webhooks/:id", webhookHandler.UpdateWebhook)
+	users.Delete("/webhooks/:id", webhookHandler.DeleteWebhook)
+	users.Get("/webhooks/:id/deliveries", webhookHandler.GetWebhookDeliveries)
+	users.Post("/webhooks/:id/test", webhookHandler.SendTestEvent)
+	users.Get("/tokens", tokenHandler.GetTokens)
+	users.Post("/tokens", tokenHandler.CreateToken)
+	users.Delete("/tokens/:id", tokenHandler.RevokeToken)
+	users.Post("/export", exportHandler.RequestExport)
+}
+
+// setupHealthRoutes sets up the probes outside of the versioned API, without authentication
+func setupHealthRoutes(app *fiber.App, healthHandler *handlers.HealthHandler) {
+	app.Get("/healthz", healthHandler.Live)
+	app.Get("/readyz", healthHandler.Ready)
+	app.Get("/version", healthHandler.Version)
+}
+
+// setupMetricsRoutes exposes the Prometheus metrics, including the statistics of the database pools
+func setupMetricsRoutes(app *fiber.App, router *db.Router) {
+	registry := metrics.NewRegistry(router.Pools())
+	app.Get("/metrics", adaptor.HTTPHandler(promhttp.HandlerFor(registry, promhttp.HandlerOpts{})))
+}
+
+// setupDAVRoutes sets up the CalDAV endpoints, authenticated with personal access tokens or Basic auth.
+// OPTIONS and service discovery stay public since clients probe them before sending credentials.
+func setupDAVRoutes(app *fiber.App, caldavHandler *handlers.CalDAVHandler, authenticate fiber.Handler) {
+	app.All("/.well-known/caldav", caldavHandler.WellKnown)
+
+	dav := app.Group("/dav")
+
+	dav.Options("/*", caldavHandler.Options)
+	dav.Add("PROPFIND", "/", authenticate, caldavHandler.PropfindRoot)
+	dav.Add("PROPFIND", "/principals/:userId", authenticate, caldavHandler.PropfindPrincipal)
+	dav.Add("PROPFIND", "/calendars/:userId", authenticate, caldavHandler.PropfindHome)
+	dav.Add("PROPFIND", "/calendars/:userId/todos", authenticate, caldavHandler.PropfindCollection)
+	dav.Add("PROPPATCH", "/calendars/:userId/todos", authenticate, caldavHandler.Proppatch)
+	dav.Add("REPORT", "/calendars/:userId/todos", authenticate, caldavHandler.Report)
+	dav.Add("PROPFIND", "/calendars/:userId/todos/:resource", authenticate, caldavHandler.PropfindObject)
+	dav.Get("/calendars/:userId/todos/:resource", authenticate, caldavHandler.GetObject)
+	dav.Put("/calendars/:userId/todos/:resource", authenticate, caldavHandler.PutObject)
+	dav.Delete("/calendars/:userId/todos/:resource", authenticate, caldavHandler.DeleteObject)
+}
+
+// setupAdminRoutes sets up routes restricted to administrators
+func setupAdminRoutes(api fiber.Router, auditHandler *handlers.AuditHandler, jwtManager *middlewares.JWTManager) {
+	admin := api.Group("/admin")
+
+	admin.Use(middlewares.AuthenticateJWT(jwtManager), middlewares.RequireAdmin())
+
+	admin.Get("/security-events", auditHandler.QuerySecurityEvents)
+}
+
+// setupDigestRoutes sets up public digest routes reached from email links
+func setupDigestRoutes(api fiber.Router, digestHandler *handlers.DigestHandler) {
+	digest := api.Group("/digest")
+	digest.Get("/unsubscribe/:token", digestHandler.Unsubscribe)
+}
+
+// setupExportRoutes sets up public data export downloads reached from email links
+func setupExportRoutes(api fiber.Router, exportHandler *handlers.ExportHandler) {
+	exports := api.Group("/exports")
+	exports.Get("/:token", exportHandler.DownloadExport)
+}
+
+// setupStreamRoutes sets up the real-time event stream, which also accepts the token as a query parameter
+func setupStreamRoutes(api fiber.Router, streamHandler *handlers.StreamHandler, jwtManager *middlewares.JWTManager) {
+	api.Get("/stream",
+		middlewares.TokenFromQuery("access_token"),
+		middlewares.AuthenticateJWT(jwtManager),
+		streamHandler.Stream,
+	)
 }
 
 // setupAuthRoutes sets up authentication-related routes with dependency injection
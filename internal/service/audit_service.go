@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"go-backend-todo/internal/models"
+	audit_repository "go-backend-todo/internal/repository/audit"
+
+	"github.com/google/uuid"
+)
+
+// AuditService interface defines business logic for the security audit log
+type AuditService interface {
+	// Record appends an event for the client of the request ctx belongs to, logging failures instead of returning them
+	Record(ctx context.Context, eventType models.AuditEventType, userID *uuid.UUID, email, details string)
+	GetUserEvents(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.AuditEvent, int64, error)
+	QueryEvents(ctx context.Context, filter models.AuditFilter) ([]*models.AuditEvent, int64, error)
+}
+
+// auditService implementation of AuditService interface
+type auditService struct {
+	auditRepo audit_repository.AuditRepository
+}
+
+// NewAuditService creates a new instance of audit service
+func NewAuditService(auditRepo audit_repository.AuditRepository) AuditService {
+	return &auditService{
+		auditRepo: auditRepo,
+	}
+}
+
+// Record appends an event to the audit log with the IP address and user agent of the current request
+func (s *auditService) Record(ctx context.Context, eventType models.AuditEventType, userID *uuid.UUID, email, details string) {
+	client := models.ClientInfoFromContext(ctx)
+
+	event := &models.AuditEvent{
+		UserID:    userID,
+		EventType: eventType,
+		Email:     email,
+		IPAddress: client.IPAddress,
+		UserAgent: client.UserAgent,
+		Details:   details,
+	}
+
+	// Record even when the request that caused the event was cancelled
+	if err := s.auditRepo.Create(context.WithoutCancel(ctx), event); err != nil {
+		log.Printf("Failed to record %s audit event: %v", eventType, err)
+	}
+}
+
+// GetUserEvents retrieves the security events of a user, newest first
+func (s *auditService) GetUserEvents(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.AuditEvent, int64, error) {
+	return s.QueryEvents(ctx, models.AuditFilter{
+		UserID: &userID,
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+// QueryEvents retrieves the security events matching a filter, newest first
+func (s *auditService) QueryEvents(ctx context.Context, filter models.AuditFilter) ([]*models.AuditEvent, int64, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = 20
+	}
+	if filter.Limit > 100 {
+		filter.Limit = 100
+	}
+
+	events, err := s.auditRepo.Query(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get security events: %w", err)
+	}
+
+	total, err := s.auditRepo.Count(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count security events: %w", err)
+	}
+
+	return events, total, nil
+}
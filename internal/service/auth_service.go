@@ -2,7 +2,11 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"go-backend-todo/internal/config"
+	"go-backend-todo/internal/db"
+	"go-backend-todo/internal/metrics"
 	"go-backend-todo/internal/models"
 	"go-backend-todo/internal/utils"
 	"log"
@@ -19,20 +23,29 @@ type AuthService interface {
 	VerifyEmail(ctx context.Context, verificationToken string) error
 	RecoverPassword(ctx context.Context, req *models.RecoverPasswordRequest, recoverToken string) error
 	ResetPassword(ctx context.Context, req *models.ResetPasswordRequest) error
+
+	// CreateAdmin registers an administrator whose email address is confirmed right away
+	CreateAdmin(ctx context.Context, req *models.RegisterRequest) (*models.UserProfile, error)
+	// PurgeExpiredTokens clears the verification and recovery tokens that are past their TTL
+	PurgeExpiredTokens(ctx context.Context) (int64, error)
 }
 
 type authService struct {
 	userRepo     user_repository.UserRepository
 	authRepo     auth_repository.AuthRepository
 	emailService EmailService
+	auditService AuditService
+	txManager    db.TxManager
 	config       *config.Config
 }
 
-func NewAuthService(userRepo user_repository.UserRepository, authRepo auth_repository.AuthRepository, emailService EmailService, cfg *config.Config) AuthService {
+func NewAuthService(userRepo user_repository.UserRepository, authRepo auth_repository.AuthRepository, emailService EmailService, auditService AuditService, txManager db.TxManager, cfg *config.Config) AuthService {
 	return &authService{
 		userRepo:     userRepo,
 		authRepo:     authRepo,
 		emailService: emailService,
+		auditService: auditService,
+		txManager:    txManager,
 		config:       cfg,
 	}
 }
@@ -47,16 +60,32 @@ func (s *authService) Login(ctx context.Context, req *models.LoginRequest) (*mod
 		// Check if error is due to timeout
 		if loginCtx.Err() == context.DeadlineExceeded {
 			log.Println("Login operation timed out")
+			metrics.Logins.WithLabelValues(metrics.ResultError).Inc()
 			return nil, utils.ErrTimeout("Login operation timed out")
 		}
 
 		log.Println("Error during login:", err)
-		if err.Error() == "invalid credentials" {
+		// Only wrong credentials are a failed login, anything else could not decide it
+		if errors.Is(err, utils.ErrBadCredentials) {
+			s.auditService.Record(ctx, models.AuditLoginFailed, nil, req.Email, "invalid email or password")
+			metrics.Logins.WithLabelValues(metrics.ResultFailure).Inc()
 			return nil, utils.ErrInvalidCredentials("Invalid email or password")
 		}
+		metrics.Logins.WithLabelValues(metrics.ResultError).Inc()
 		return nil, err
 	}
 
+	s.auditService.Record(ctx, models.AuditLoginSucceeded, &user.UserID, req.Email, "")
+	metrics.Logins.WithLabelValues(metrics.ResultSuccess).Inc()
+
+	// Logging in during the grace period of a scheduled deletion keeps the account
+	cancelled, err := s.userRepo.CancelDeletion(ctx, user.UserID)
+	if err != nil {
+		log.Printf("Failed to cancel account deletion for user %s: %v", user.UserID, err)
+	} else if cancelled {
+		s.auditService.Record(ctx, models.AuditDeletionCancelled, &user.UserID, req.Email, "")
+	}
+
 	// Create separate timeout context for token version increment
 	tokenCtx, tokenCancel := context.WithTimeout(context.Background(), time.Duration(s.config.Timeouts.UserTimeout.TokenVersionTimeout)*time.Second)
 	defer tokenCancel()
@@ -93,6 +122,54 @@ func (s *authService) Register(ctx context.Context, req *models.RegisterRequest,
 	registerCtx, cancel := context.WithTimeout(ctx, time.Duration(s.config.Timeouts.AuthTimeout.RegisterTimeout)*time.Second)
 	defer cancel()
 
+	// The checks and the insert share a transaction, so a failure leaves nothing behind
+	err := s.txManager.WithinTx(registerCtx, func(txCtx context.Context) error {
+		if err := s.checkRegistration(txCtx, req); err != nil {
+			return err
+		}
+
+		// Create user account with timeout
+		createCtx, createCancel := context.WithTimeout(txCtx, time.Duration(s.config.Timeouts.UserTimeout.CreateUserTimeout)*time.Second)
+		defer createCancel()
+
+		err := s.userRepo.Create(createCtx, req, verificationToken)
+		if err != nil {
+			if createCtx.Err() == context.DeadlineExceeded {
+				log.Printf("User creation timed out for email: %s", req.Email)
+				return utils.ErrTimeout("User creation timed out")
+			}
+			log.Println("Error registering user:", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.auditService.Record(ctx, models.AuditUserRegistered, nil, req.Email, "")
+
+	// Send confirmation email with timeout (use background context to not inherit parent timeout)
+	emailSendCtx, emailSendCancel := context.WithTimeout(context.Background(), time.Duration(s.config.Timeouts.EmailTimeout.EmailSendTimeout)*time.Second)
+	defer emailSendCancel()
+
+	err = s.emailService.SendVerificationEmail(emailSendCtx, req.Username, req.Email, verificationToken)
+	if err != nil {
+		if emailSendCtx.Err() == context.DeadlineExceeded {
+			log.Printf("Verification email send timed out for email: %s", req.Email)
+			// Don't fail registration if email fails - user is already created
+			log.Printf("User %s registered successfully but email failed to send", req.Email)
+		} else {
+			log.Printf("Error sending verification email to %s: %v", req.Email, err)
+		}
+		// Don't return error here - user is already registered
+	}
+
+	return nil
+}
+
+// checkRegistration rejects a registration whose email or username is taken or whose password is too weak
+func (s *authService) checkRegistration(registerCtx context.Context, req *models.RegisterRequest) error {
 	// Check email existence with timeout
 	emailCtx, emailCancel := context.WithTimeout(registerCtx, time.Duration(s.config.Timeouts.UserTimeout.EmailAlreadyExistsTimeout)*time.Second)
 	defer emailCancel()
@@ -126,41 +203,7 @@ func (s *authService) Register(ctx context.Context, req *models.RegisterRequest,
 	}
 
 	// Password strength validation (no timeout needed - local operation)
-	if err := s.userRepo.ValidatePasswordStrength(req.Password); err != nil {
-		return err
-	}
-
-	// Create user account with timeout
-	createCtx, createCancel := context.WithTimeout(registerCtx, time.Duration(s.config.Timeouts.UserTimeout.CreateUserTimeout)*time.Second)
-	defer createCancel()
-
-	err = s.userRepo.Create(createCtx, req, verificationToken)
-	if err != nil {
-		if createCtx.Err() == context.DeadlineExceeded {
-			log.Printf("User creation timed out for email: %s", req.Email)
-			return utils.ErrTimeout("User creation timed out")
-		}
-		log.Println("Error registering user:", err)
-		return err
-	}
-
-	// Send confirmation email with timeout (use background context to not inherit parent timeout)
-	emailSendCtx, emailSendCancel := context.WithTimeout(context.Background(), time.Duration(s.config.Timeouts.EmailTimeout.EmailSendTimeout)*time.Second)
-	defer emailSendCancel()
-
-	err = s.emailService.SendVerificationEmail(emailSendCtx, req.Username, req.Email, verificationToken)
-	if err != nil {
-		if emailSendCtx.Err() == context.DeadlineExceeded {
-			log.Printf("Verification email send timed out for email: %s", req.Email)
-			// Don't fail registration if email fails - user is already created
-			log.Printf("User %s registered successfully but email failed to send", req.Email)
-		} else {
-			log.Printf("Error sending verification email to %s: %v", req.Email, err)
-		}
-		// Don't return error here - user is already registered
-	}
-
-	return nil
+	return s.userRepo.ValidatePasswordStrength(req.Password)
 }
 
 func (s *authService) VerifyEmail(ctx context.Context, verificationToken string) error {
@@ -183,7 +226,7 @@ func (s *authService) VerifyEmail(ctx context.Context, verificationToken string)
 	}
 
 	// Use the same timeout context for email verification
-	err = s.authRepo.VerifyEmail(verifyCtx, verificationToken)
+	userID, err := s.authRepo.VerifyEmail(verifyCtx, verificationToken)
 	if err != nil {
 		if verifyCtx.Err() == context.DeadlineExceeded {
 			log.Printf("Email verification operation timed out for token: %s", verificationToken)
@@ -192,6 +235,8 @@ func (s *authService) VerifyEmail(ctx context.Context, verificationToken string)
 		return err
 	}
 
+	s.auditService.Record(ctx, models.AuditEmailVerified, &userID, "", "")
+
 	return nil
 }
 
@@ -247,7 +292,7 @@ func (s *authService) ResetPassword(ctx context.Context, req *models.ResetPasswo
 	}
 
 	// Use the same timeout context for password reset
-	err = s.authRepo.ResetPassword(resetCtx, req)
+	userID, err := s.authRepo.ResetPassword(resetCtx, req)
 	if err != nil {
 		if resetCtx.Err() == context.DeadlineExceeded {
 			log.Printf("Password reset operation timed out for token: %s", req.Token)
@@ -256,5 +301,67 @@ func (s *authService) ResetPassword(ctx context.Context, req *models.ResetPasswo
 		return err
 	}
 
+	s.auditService.Record(ctx, models.AuditPasswordReset, &userID, "", "")
+
 	return nil
 }
+
+// CreateAdmin registers an administrator with the same checks as a registration, without sending a verification email
+func (s *authService) CreateAdmin(ctx context.Context, req *models.RegisterRequest) (*models.UserProfile, error) {
+	// The account is confirmed below, so the verification token is never sent and only needs to be unguessable
+	verificationToken, err := generateRandomToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate verification token: %w", err)
+	}
+
+	// Either the administrator exists with a confirmed email and the admin role, or nothing was created
+	var user *models.UserProfile
+	err = s.txManager.WithinTx(ctx, func(txCtx context.Context) error {
+		if err := s.checkRegistration(txCtx, req); err != nil {
+			return err
+		}
+
+		if err := s.userRepo.Create(txCtx, req, verificationToken); err != nil {
+			return err
+		}
+
+		created, err := s.userRepo.GetByEmail(txCtx, req.Email)
+		if err != nil {
+			return err
+		}
+
+		if _, err := s.userRepo.ConfirmEmail(txCtx, created.UserID); err != nil {
+			return fmt.Errorf("failed to confirm email: %w", err)
+		}
+
+		if err := s.userRepo.SetRole(txCtx, created.UserID, models.AdminRole); err != nil {
+			return fmt.Errorf("failed to grant admin role: %w", err)
+		}
+
+		user, err = s.userRepo.GetByID(txCtx, created.UserID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Audit events are written after the commit, since a failed audit insert would abort the transaction
+	s.auditService.Record(ctx, models.AuditUserRegistered, &user.UserID, req.Email, "created by an administrator")
+	s.auditService.Record(ctx, models.AuditEmailVerified, &user.UserID, req.Email, "confirmed by an administrator")
+	s.auditService.Record(ctx, models.AuditRoleChanged, &user.UserID, req.Email, string(models.AdminRole))
+
+	return user, nil
+}
+
+// PurgeExpiredTokens clears verification and recovery tokens older than their TTL, which could no longer be used anyway
+func (s *authService) PurgeExpiredTokens(ctx context.Context) (int64, error) {
+	now := time.Now()
+	verifyBefore := now.Add(-time.Duration(s.config.Token.VerifyEmailTokenTTL) * time.Minute)
+	recoverBefore := now.Add(-time.Duration(s.config.Token.RecoverPasswordTokenTTL) * time.Minute)
+
+	cleared, err := s.authRepo.ClearExpiredTokens(ctx, verifyBefore, recoverBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear expired tokens: %w", err)
+	}
+	return cleared, nil
+}
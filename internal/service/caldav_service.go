@@ -0,0 +1,222 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go-backend-todo/internal/config"
+	"go-backend-todo/internal/models"
+	todo_repository "go-backend-todo/internal/repository/todo"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrPreconditionFailed is returned when an If-Match or If-None-Match condition does not hold
+	ErrPreconditionFailed = errors.New("precondition failed")
+	// ErrInvalidCalendarData is returned when a CalDAV resource is not an iCalendar object with a usable VTODO
+	ErrInvalidCalendarData = errors.New("invalid calendar data")
+	// ErrTodoIDTaken is returned when a CalDAV client creates a resource whose ID is used by a todo it cannot see
+	ErrTodoIDTaken = errors.New("todo ID is already in use")
+)
+
+// CalDAVService interface defines the business logic behind the CalDAV task collection of a user.
+// Resources are named after todo IDs and their ETags derive from updated_at.
+type CalDAVService interface {
+	GetTodos(ctx context.Context, userID uuid.UUID) ([]*models.Todo, error)
+	GetTodo(ctx context.Context, userID, id uuid.UUID) (*models.Todo, error)
+	// PutTodo creates or updates a todo from an iCalendar object, reporting whether it was created
+	PutTodo(ctx context.Context, userID, id uuid.UUID, data []byte, ifMatch, ifNoneMatch string) (*models.Todo, bool, error)
+	DeleteTodo(ctx context.Context, userID, id uuid.UUID, ifMatch string) error
+	// CollectionTag changes whenever a todo of the collection is added, changed or deleted
+	CollectionTag(ctx context.Context, userID uuid.UUID) (string, error)
+	Render(todo *models.Todo) ([]byte, error)
+}
+
+// caldavService implementation of CalDAVService interface
+type caldavService struct {
+	todoRepo          todo_repository.TodoRepository
+	todoService       TodoService
+	preferenceService PreferenceService
+	config            *config.Config
+}
+
+// NewCalDAVService creates a new instance of CalDAV service
+func NewCalDAVService(todoRepo todo_repository.TodoRepository, todoService TodoService, preferenceService PreferenceService, cfg *config.Config) CalDAVService {
+	return &caldavService{
+		todoRepo:          todoRepo,
+		todoService:       todoService,
+		preferenceService: preferenceService,
+		config:            cfg,
+	}
+}
+
+// GetTodos retrieves every todo visible to the user
+func (s *caldavService) GetTodos(ctx context.Context, userID uuid.UUID) ([]*models.Todo, error) {
+	todos := []*models.Todo{}
+	err := s.todoRepo.StreamByUserID(ctx, userID, func(todo *models.Todo) error {
+		todos = append(todos, todo)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get todos: %w", err)
+	}
+	return todos, nil
+}
+
+// GetTodo retrieves a todo the user can view, hiding whether it is missing or not shared with the user
+func (s *caldavService) GetTodo(ctx context.Context, userID, id uuid.UUID) (*models.Todo, error) {
+	todo, err := s.todoService.GetTodoByID(ctx, id, userID)
+	if errors.Is(err, ErrTodoNotFound) || errors.Is(err, ErrListForbidden) {
+		return nil, ErrTodoNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+// PutTodo stores a VTODO under the todo ID of its resource. New resources become personal todos.
+// A VTODO without DUE keeps the deadline of an existing todo, and new ones are due at the end of the day.
+func (s *caldavService) PutTodo(ctx context.Context, userID, id uuid.UUID, data []byte, ifMatch, ifNoneMatch string) (*models.Todo, bool, error) {
+	prefs, err := s.preferenceService.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, false, err
+	}
+	loc := prefs.Location()
+
+	vtodo, err := parseICSTodo(data, loc)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrInvalidCalendarData, err)
+	}
+	title := strings.TrimSpace(vtodo.Summary)
+	if title == "" {
+		return nil, false, fmt.Errorf("%w: SUMMARY is required", ErrInvalidCalendarData)
+	}
+	if utf8.RuneCountInString(title) > maxImportTitleLength {
+		title = string([]rune(title)[:maxImportTitleLength])
+	}
+
+	existing, err := s.todoService.GetTodoByID(ctx, id, userID)
+	exists := err == nil
+	if err := checkPreconditions(existing, exists, ifMatch, ifNoneMatch); err != nil {
+		return nil, false, err
+	}
+
+	if exists {
+		req := models.UpdateTodoRequest{
+			Title:     &title,
+			Deadline:  vtodo.Due,
+			Completed: &vtodo.Completed,
+		}
+		todo, err := s.todoService.UpdateTodo(ctx, id, req, userID)
+		return todo, false, err
+	}
+
+	// The ID may belong to a trashed todo or one of another user
+	if _, err := s.todoRepo.GetByID(ctx, id); err == nil {
+		return nil, false, ErrTodoIDTaken
+	}
+	if _, err := s.todoRepo.GetDeletedByID(ctx, id); err == nil {
+		return nil, false, ErrTodoIDTaken
+	}
+
+	deadline := endOfDay(time.Now().In(loc))
+	if vtodo.Due != nil {
+		deadline = *vtodo.Due
+	}
+	todo, err := s.todoService.CreateTodo(ctx, models.CreateTodoRequest{ID: &id, Title: title, Deadline: deadline}, userID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if vtodo.Completed {
+		todo, err = s.todoService.UpdateTodo(ctx, id, models.UpdateTodoRequest{Completed: &vtodo.Completed}, userID)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	return todo, true, nil
+}
+
+// DeleteTodo moves a todo to the trash
+func (s *caldavService) DeleteTodo(ctx context.Context, userID, id uuid.UUID, ifMatch string) error {
+	todo, err := s.todoService.GetTodoByID(ctx, id, userID)
+	if err != nil {
+		return ErrTodoNotFound
+	}
+	if err := checkPreconditions(todo, true, ifMatch, ""); err != nil {
+		return err
+	}
+
+	_, err = s.todoService.DeleteTodo(ctx, id, userID)
+	return err
+}
+
+// CollectionTag derives a tag from the number of visible todos and the time the last one changed
+func (s *caldavService) CollectionTag(ctx context.Context, userID uuid.UUID) (string, error) {
+	state, err := s.todoRepo.GetSyncState(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get sync state: %w", err)
+	}
+
+	var lastModified int64
+	if state.LastModified != nil {
+		lastModified = state.LastModified.UnixMicro()
+	}
+	return `"` + strconv.FormatInt(state.Count, 36) + "-" + strconv.FormatInt(lastModified, 36) + `"`, nil
+}
+
+// Render returns a todo as an iCalendar object with a single VTODO
+func (s *caldavService) Render(todo *models.Todo) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder, err := newTodoEncoder(models.ExportFormatICS, &buf, s.config.App.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := encoder.Begin(); err != nil {
+		return nil, err
+	}
+	if err := encoder.Encode(todo); err != nil {
+		return nil, err
+	}
+	if err := encoder.End(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// checkPreconditions evaluates If-Match and If-None-Match against the current version of a resource
+func checkPreconditions(todo *models.Todo, exists bool, ifMatch, ifNoneMatch string) error {
+	if ifNoneMatch == "*" && exists {
+		return ErrPreconditionFailed
+	}
+	if ifMatch == "" {
+		return nil
+	}
+	if !exists {
+		return ErrPreconditionFailed
+	}
+	if ifMatch == "*" {
+		return nil
+	}
+	for _, etag := range strings.Split(ifMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(etag), "W/") == todo.ETag() {
+			return nil
+		}
+	}
+	return ErrPreconditionFailed
+}
+
+// endOfDay returns the last minute of the day of t, in the location of t
+func endOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 23, 59, 0, 0, t.Location())
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"go-backend-todo/internal/models"
+	comment_repository "go-backend-todo/internal/repository/comment"
+	user_repository "go-backend-todo/internal/repository/user"
+	"go-backend-todo/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrCommentNotFound is returned when a comment does not exist on the todo
+	ErrCommentNotFound = errors.New("comment not found")
+	// ErrCommentForbidden is returned when a user edits or deletes a comment they did not write
+	ErrCommentForbidden = errors.New("only the author can change a comment")
+)
+
+// mentionPattern matches @username mentions that are not part of a word or an email address
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.])@([\w.-]{3,50})`)
+
+// maxMentionsPerComment caps the notifications a single comment can trigger
+const maxMentionsPerComment = 10
+
+// CommentService interface defines business logic for comments on todos
+type CommentService interface {
+	GetComments(ctx context.Context, todoID, userID uuid.UUID) ([]*models.TodoComment, error)
+	CreateComment(ctx context.Context, todoID, userID uuid.UUID, req *models.CreateCommentRequest) (*models.TodoComment, error)
+	UpdateComment(ctx context.Context, todoID, commentID, userID uuid.UUID, req *models.UpdateCommentRequest) (*models.TodoComment, error)
+	DeleteComment(ctx context.Context, todoID, commentID, userID uuid.UUID) error
+}
+
+// commentService implementation of CommentService interface
+type commentService struct {
+	commentRepo         comment_repository.CommentRepository
+	userRepo            user_repository.UserRepository
+	todoService         TodoService
+	notificationService NotificationService
+}
+
+// NewCommentService creates a new instance of comment service
+func NewCommentService(commentRepo comment_repository.CommentRepository, userRepo user_repository.UserRepository, todoService TodoService, notificationService NotificationService) CommentService {
+	return &commentService{
+		commentRepo:         commentRepo,
+		userRepo:            userRepo,
+		todoService:         todoService,
+		notificationService: notificationService,
+	}
+}
+
+// GetComments returns the comments of a todo the user can view, replies nested under their parent
+func (s *commentService) GetComments(ctx context.Context, todoID, userID uuid.UUID) ([]*models.TodoComment, error) {
+	if _, err := s.getTodo(ctx, todoID, userID); err != nil {
+		return nil, err
+	}
+
+	comments, err := s.commentRepo.GetByTodoID(ctx, todoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments: %w", err)
+	}
+
+	return buildCommentThreads(comments), nil
+}
+
+// CreateComment comments on a todo the user can view and notifies the users it mentions
+func (s *commentService) CreateComment(ctx context.Context, todoID, userID uuid.UUID, req *models.CreateCommentRequest) (*models.TodoComment, error) {
+	todo, err := s.getTodo(ctx, todoID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(req.Body) == "" {
+		return nil, utils.ErrInvalidInput("Comment body cannot be empty")
+	}
+
+	if req.ParentID != nil {
+		parent, err := s.commentRepo.GetByID(ctx, *req.ParentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get parent comment: %w", err)
+		}
+		if parent == nil || parent.TodoID != todoID {
+			return nil, ErrCommentNotFound
+		}
+	}
+
+	comment := &models.TodoComment{
+		TodoID:   todoID,
+		ParentID: req.ParentID,
+		AuthorID: userID,
+		Body:     req.Body,
+	}
+
+	if err := s.commentRepo.Create(ctx, comment); err != nil {
+		return nil, fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	s.notifyMentions(ctx, todo, comment, parseMentions(comment.Body))
+
+	return comment, nil
+}
+
+// UpdateComment edits a comment written by the user and notifies the users newly mentioned in it
+func (s *commentService) UpdateComment(ctx context.Context, todoID, commentID, userID uuid.UUID, req *models.UpdateCommentRequest) (*models.TodoComment, error) {
+	todo, err := s.getTodo(ctx, todoID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(req.Body) == "" {
+		return nil, utils.ErrInvalidInput("Comment body cannot be empty")
+	}
+
+	existing, err := s.getAuthoredComment(ctx, todoID, commentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := s.commentRepo.Update(ctx, commentID, userID, req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update comment: %w", err)
+	}
+	if comment == nil {
+		return nil, ErrCommentNotFound
+	}
+
+	// Only notify users who were not already mentioned before the edit
+	previous := make(map[string]bool)
+	for _, username := range parseMentions(existing.Body) {
+		previous[username] = true
+	}
+	var added []string
+	for _, username := range parseMentions(comment.Body) {
+		if !previous[username] {
+			added = append(added, username)
+		}
+	}
+	s.notifyMentions(ctx, todo, comment, added)
+
+	return comment, nil
+}
+
+// DeleteComment deletes a comment written by the user, keeping the replies of other users in the thread
+func (s *commentService) DeleteComment(ctx context.Context, todoID, commentID, userID uuid.UUID) error {
+	if _, err := s.getTodo(ctx, todoID, userID); err != nil {
+		return err
+	}
+
+	if _, err := s.getAuthoredComment(ctx, todoID, commentID, userID); err != nil {
+		return err
+	}
+
+	deleted, err := s.commentRepo.Delete(ctx, commentID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+	if !deleted {
+		return ErrCommentNotFound
+	}
+
+	return nil
+}
+
+// getTodo retrieves a todo the user can view, hiding whether it is missing or not shared with the user
+func (s *commentService) getTodo(ctx context.Context, todoID, userID uuid.UUID) (*models.Todo, error) {
+	todo, err := s.todoService.GetTodoByID(ctx, todoID, userID)
+	if errors.Is(err, ErrTodoNotFound) || errors.Is(err, ErrListForbidden) {
+		return nil, ErrTodoNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+// getAuthoredComment retrieves a comment of a todo and checks that the user wrote it
+func (s *commentService) getAuthoredComment(ctx context.Context, todoID, commentID, userID uuid.UUID) (*models.TodoComment, error) {
+	comment, err := s.commentRepo.GetByID(ctx, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+	if comment == nil || comment.TodoID != todoID {
+		return nil, ErrCommentNotFound
+	}
+	if comment.AuthorID != userID {
+		return nil, ErrCommentForbidden
+	}
+	return comment, nil
+}
+
+// notifyMentions notifies the mentioned users who can view the todo, logging failures instead of failing the comment
+func (s *commentService) notifyMentions(ctx context.Context, todo *models.Todo, comment *models.TodoComment, usernames []string) {
+	for _, username := range usernames {
+		user, err := s.userRepo.GetByUsername(ctx, username)
+		if err != nil {
+			// Not every @word is a username
+			continue
+		}
+
+		// Don't leak the todo to users who cannot see it
+		if _, err := s.todoService.GetTodoByID(ctx, todo.ID, user.UserID); err != nil {
+			continue
+		}
+
+		if err := s.notificationService.NotifyCommentMention(ctx, todo, comment, user); err != nil {
+			log.Printf("Failed to notify %s of mention in comment %s: %v", username, comment.ID, err)
+		}
+	}
+}
+
+// parseMentions returns the distinct usernames mentioned in a comment body, in order of appearance
+func parseMentions(body string) []string {
+	seen := make(map[string]bool)
+	var usernames []string
+
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		// Trailing punctuation ends the sentence, not the username
+		username := strings.TrimRight(match[1], ".-")
+		if len(username) < 3 || seen[username] {
+			continue
+		}
+
+		seen[username] = true
+		usernames = append(usernames, username)
+		if len(usernames) == maxMentionsPerComment {
+			break
+		}
+	}
+
+	return usernames
+}
+
+// buildCommentThreads nests replies under their parent, keeping the order of comments
+func buildCommentThreads(comments []*models.TodoComment) []*models.TodoComment {
+	byID := make(map[uuid.UUID]*models.TodoComment, len(comments))
+	for _, comment := range comments {
+		byID[comment.ID] = comment
+	}
+
+	threads := []*models.TodoComment{}
+	for _, comment := range comments {
+		if comment.ParentID != nil {
+			if parent, ok := byID[*comment.ParentID]; ok {
+				parent.Replies = append(parent.Replies, comment)
+				continue
+			}
+		}
+		threads = append(threads, comment)
+	}
+
+	return threads
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go-backend-todo/internal/config"
+	"go-backend-todo/internal/models"
+	preference_repository "go-backend-todo/internal/repository/preference"
+	todo_repository "go-backend-todo/internal/repository/todo"
+	"go-backend-todo/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// digestUnsubscribePurpose scopes signed unsubscribe tokens to the digest
+	digestUnsubscribePurpose = "digest-unsubscribe"
+	// digestSectionLimit caps the number of todos listed per digest section
+	digestSectionLimit = 20
+)
+
+// DigestService interface defines business logic for the daily digest email
+type DigestService interface {
+	Unsubscribe(ctx context.Context, token string) error
+	SendDueDigests(ctx context.Context) (int, error)
+}
+
+// digestService implementation of DigestService interface
+type digestService struct {
+	preferenceRepo preference_repository.PreferenceRepository
+	todoRepo       todo_repository.TodoRepository
+	emailService   EmailService
+	config         *config.Config
+}
+
+// NewDigestService creates a new instance of digest service
+func NewDigestService(preferenceRepo preference_repository.PreferenceRepository, todoRepo todo_repository.TodoRepository, emailService EmailService, cfg *config.Config) DigestService {
+	return &digestService{
+		preferenceRepo: preferenceRepo,
+		todoRepo:       todoRepo,
+		emailService:   emailService,
+		config:         cfg,
+	}
+}
+
+// Unsubscribe disables the digest of the user identified by a signed unsubscribe token
+func (s *digestService) Unsubscribe(ctx context.Context, token string) error {
+	subject, err := parseSignedLinkToken(s.config.Token.UnsubscribeTokenSecret, digestUnsubscribePurpose, token)
+	if err != nil {
+		return err
+	}
+
+	userID, err := uuid.Parse(subject)
+	if err != nil {
+		return utils.ErrInvalidCredentials("Invalid or expired link")
+	}
+
+	return s.preferenceRepo.SetEmailDigest(ctx, userID, false)
+}
+
+// SendDueDigests claims the digests whose local digest hour has been reached and emails them.
+// Claimed digests are not retried, so a failed delivery is logged and skipped until the next day.
+func (s *digestService) SendDueDigests(ctx context.Context) (int, error) {
+	due, err := s.preferenceRepo.ClaimDueDigests(ctx, time.Now(), s.config.Digest.SendWindowHours, s.config.Digest.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim due digests: %w", err)
+	}
+
+	sent := 0
+	for _, recipient := range due {
+		if err := s.sendDigest(ctx, recipient); err != nil {
+			log.Printf("Failed to send digest to user %s: %v", recipient.UserID, err)
+			continue
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// sendDigest builds and emails the digest of one recipient, skipping days with nothing due
+func (s *digestService) sendDigest(ctx context.Context, recipient *models.DueDigest) error {
+	loc, err := time.LoadLocation(recipient.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	window := newTodoStatsWindow(time.Now(), loc, time.Weekday(recipient.WeekStart))
+	digest, err := s.buildDigest(ctx, recipient.UserID, window)
+	if err != nil {
+		return err
+	}
+	if digest.IsEmpty() {
+		return nil
+	}
+
+	token, err := generateSignedLinkToken(
+		s.config.Token.UnsubscribeTokenSecret, s.config.App.Name, digestUnsubscribePurpose,
+		recipient.UserID.String(), time.Duration(s.config.Token.UnsubscribeTokenTTL)*time.Minute,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to generate unsubscribe token: %w", err)
+	}
+	unsubscribeURL := fmt.Sprintf("%s/api/v1/digest/unsubscribe/%s", s.config.App.BaseURL, token)
+
+	emailCtx, cancel := context.WithTimeout(ctx, time.Duration(s.config.Timeouts.EmailTimeout.EmailSendTimeout)*time.Second)
+	defer cancel()
+
+	return s.emailService.SendDigestEmail(emailCtx, recipient.Email, recipient.Username, digest, unsubscribeURL)
+}
+
+// buildDigest collects the statistics and todo sections of a digest
+func (s *digestService) buildDigest(ctx context.Context, userID uuid.UUID, window models.TodoStatsWindow) (*models.TodoDigest, error) {
+	stats, err := s.todoRepo.GetStats(ctx, userID, window)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get todo stats: %w", err)
+	}
+
+	overdue, err := s.todoRepo.GetPendingByDeadline(ctx, userID, nil, &window.Now, digestSectionLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get overdue todos: %w", err)
+	}
+
+	today, err := s.todoRepo.GetPendingByDeadline(ctx, userID, &window.Now, &window.DayEnd, digestSectionLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get today's todos: %w", err)
+	}
+
+	thisWeek, err := s.todoRepo.GetPendingByDeadline(ctx, userID, &window.DayEnd, &window.WeekEnd, digestSectionLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get this week's todos: %w", err)
+	}
+
+	return &models.TodoDigest{
+		Date:     window.DayStart,
+		Stats:    stats,
+		Overdue:  overdue,
+		Today:    today,
+		ThisWeek: thisWeek,
+	}, nil
+}
@@ -3,10 +3,16 @@ package service
 import (
 	"context"
 	"fmt"
+	"html"
 	"log"
+	"net"
 	"net/smtp"
+	"strings"
+	"time"
 
 	"go-backend-todo/internal/config"
+	"go-backend-todo/internal/metrics"
+	"go-backend-todo/internal/models"
 )
 
 // EmailService handles email operations
@@ -15,6 +21,15 @@ type EmailService interface {
 	SendHTMLEmail(ctx context.Context, to, subject, htmlBody string) error
 	SendVerificationEmail(ctx context.Context, to, username, token string) error
 	SendPasswordResetEmail(ctx context.Context, to, username, token string) error
+	SendTodoReminderEmail(ctx context.Context, to, username, todoTitle string, deadline time.Time) error
+	SendDigestEmail(ctx context.Context, to, username string, digest *models.TodoDigest, unsubscribeURL string) error
+	SendListInvitationEmail(ctx context.Context, to, inviterName, listName string, role models.ListRole, invitationURL string) error
+	SendTodoAssignedEmail(ctx context.Context, to, username, assignerName, todoTitle string, deadline time.Time) error
+	SendCommentMentionEmail(ctx context.Context, to, username, authorName, todoTitle, commentBody string) error
+	SendDataExportEmail(ctx context.Context, to, username, downloadURL string, expiresAt time.Time) error
+	SendAccountDeletionEmail(ctx context.Context, to, username string, deletionAt time.Time) error
+	// CheckTransport connects to the SMTP server when one is configured, so readiness can report it unreachable
+	CheckTransport(ctx context.Context) error
 }
 
 type emailService struct {
@@ -31,6 +46,7 @@ func (s *emailService) SendEmail(ctx context.Context, to, subject, body string)
 	if s.cfg.Email.SMTPUsername == "" {
 		// Skip sending email if SMTP not configured (development mode)
 		fmt.Printf("Email would be sent to %s: %s\n", to, subject)
+		metrics.EmailsSent.WithLabelValues(metrics.ResultSkipped).Inc()
 		return nil
 	}
 
@@ -42,7 +58,35 @@ func (s *emailService) SendEmail(ctx context.Context, to, subject, body string)
 
 	// Send email
 	addr := fmt.Sprintf("%s:%d", s.cfg.Email.SMTPHost, s.cfg.Email.SMTPPort)
-	return smtp.SendMail(addr, auth, s.cfg.Email.FromEmail, []string{to}, []byte(msg))
+	err := smtp.SendMail(addr, auth, s.cfg.Email.FromEmail, []string{to}, []byte(msg))
+	metrics.EmailsSent.WithLabelValues(metrics.Outcome(err)).Inc()
+	return err
+}
+
+// CheckTransport greets the SMTP server and hangs up without sending anything
+func (s *emailService) CheckTransport(ctx context.Context) error {
+	if s.cfg.Email.SMTPUsername == "" {
+		// Emails are only logged in development mode
+		return nil
+	}
+
+	addr := fmt.Sprintf("%s:%d", s.cfg.Email.SMTPHost, s.cfg.Email.SMTPPort)
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.cfg.Email.SMTPHost)
+	if err != nil {
+		return err
+	}
+	return client.Quit()
 }
 
 // SendHTMLEmail sends an HTML email
@@ -50,6 +94,7 @@ func (s *emailService) SendHTMLEmail(ctx context.Context, to, subject, htmlBody
 	if s.cfg.Email.SMTPUsername == "" {
 		// Skip sending email if SMTP not configured (development mode)
 		fmt.Printf("HTML Email would be sent to %s: %s\n", to, subject)
+		metrics.EmailsSent.WithLabelValues(metrics.ResultSkipped).Inc()
 		return nil
 	}
 
@@ -63,7 +108,9 @@ func (s *emailService) SendHTMLEmail(ctx context.Context, to, subject, htmlBody
 
 	// Send email
 	addr := fmt.Sprintf("%s:%d", s.cfg.Email.SMTPHost, s.cfg.Email.SMTPPort)
-	return smtp.SendMail(addr, auth, s.cfg.Email.FromEmail, []string{to}, []byte(msg))
+	err := smtp.SendMail(addr, auth, s.cfg.Email.FromEmail, []string{to}, []byte(msg))
+	metrics.EmailsSent.WithLabelValues(metrics.Outcome(err)).Inc()
+	return err
 }
 
 // SendVerificationEmail sends email verification email
@@ -88,6 +135,69 @@ func (s *emailService) SendPasswordResetEmail(ctx context.Context, to, username,
 	// return s.SendHTMLEmail(ctx, to, subject, htmlBody)
 }
 
+// SendTodoReminderEmail sends a reminder about an approaching todo deadline, shown in the deadline's location
+func (s *emailService) SendTodoReminderEmail(ctx context.Context, to, username, todoTitle string, deadline time.Time) error {
+	subject := fmt.Sprintf("Reminder: \"%s\" is due %s", todoTitle, humanizeUntil(time.Until(deadline)))
+
+	htmlBody := s.getTodoReminderEmailTemplate(username, todoTitle, deadline)
+
+	return s.SendHTMLEmail(ctx, to, subject, htmlBody)
+}
+
+// SendDigestEmail sends the daily digest of overdue and upcoming todos
+func (s *emailService) SendDigestEmail(ctx context.Context, to, username string, digest *models.TodoDigest, unsubscribeURL string) error {
+	subject := fmt.Sprintf("Your todos for %s", digest.Date.Format("Monday, 02 Jan"))
+
+	htmlBody := s.getDigestEmailTemplate(username, digest, unsubscribeURL)
+
+	return s.SendHTMLEmail(ctx, to, subject, htmlBody)
+}
+
+// SendListInvitationEmail sends an invitation to join a shared list
+func (s *emailService) SendListInvitationEmail(ctx context.Context, to, inviterName, listName string, role models.ListRole, invitationURL string) error {
+	subject := fmt.Sprintf("%s invited you to \"%s\"", inviterName, listName)
+
+	htmlBody := s.getListInvitationEmailTemplate(inviterName, listName, role, invitationURL)
+
+	return s.SendHTMLEmail(ctx, to, subject, htmlBody)
+}
+
+// SendTodoAssignedEmail tells a user that a todo was assigned to them, with the deadline shown in its location
+func (s *emailService) SendTodoAssignedEmail(ctx context.Context, to, username, assignerName, todoTitle string, deadline time.Time) error {
+	subject := fmt.Sprintf("%s assigned you \"%s\"", assignerName, todoTitle)
+
+	htmlBody := s.getTodoAssignedEmailTemplate(username, assignerName, todoTitle, deadline)
+
+	return s.SendHTMLEmail(ctx, to, subject, htmlBody)
+}
+
+// SendCommentMentionEmail tells a user that they were mentioned in a comment on a todo
+func (s *emailService) SendCommentMentionEmail(ctx context.Context, to, username, authorName, todoTitle, commentBody string) error {
+	subject := fmt.Sprintf("%s mentioned you on \"%s\"", authorName, todoTitle)
+
+	htmlBody := s.getCommentMentionEmailTemplate(username, authorName, todoTitle, commentBody)
+
+	return s.SendHTMLEmail(ctx, to, subject, htmlBody)
+}
+
+// SendDataExportEmail sends the download link of an account data export
+func (s *emailService) SendDataExportEmail(ctx context.Context, to, username, downloadURL string, expiresAt time.Time) error {
+	subject := "Your data export is ready"
+
+	htmlBody := s.getDataExportEmailTemplate(username, downloadURL, expiresAt)
+
+	return s.SendHTMLEmail(ctx, to, subject, htmlBody)
+}
+
+// SendAccountDeletionEmail confirms that an account is scheduled for deletion and explains how to cancel it
+func (s *emailService) SendAccountDeletionEmail(ctx context.Context, to, username string, deletionAt time.Time) error {
+	subject := "Your account is scheduled for deletion"
+
+	htmlBody := s.getAccountDeletionEmailTemplate(username, deletionAt)
+
+	return s.SendHTMLEmail(ctx, to, subject, htmlBody)
+}
+
 // GetVerificationEmailTemplate returns HTML template for email verification
 func (s *emailService) getVerificationEmailTemplate(username, token string) string {
 	verificationURL := fmt.Sprintf("http://localhost:8080/api/v1/auth/verify-email?token=%s", token)
@@ -182,3 +292,364 @@ func (s *emailService) getPasswordResetEmailTemplate(username, token string) str
 
 	return fmt.Sprintf(template, username, resetURL, resetURL, resetURL)
 }
+
+// getTodoReminderEmailTemplate returns HTML template for todo deadline reminders
+func (s *emailService) getTodoReminderEmailTemplate(username, todoTitle string, deadline time.Time) string {
+	template := `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Todo Reminder</title>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #2196F3; color: white; padding: 20px; text-align: center; }
+        .content { padding: 20px; background-color: #f9f9f9; }
+        .todo { background-color: #fff; border-left: 4px solid #2196F3; padding: 15px; margin: 15px 0; }
+        .footer { text-align: center; color: #666; font-size: 12px; margin-top: 20px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>Upcoming Deadline</h1>
+        </div>
+        <div class="content">
+            <h2>Hello %s,</h2>
+            <p>This is a reminder that one of your todos is due %s:</p>
+            <div class="todo">
+                <strong>%s</strong><br>
+                Deadline: %s
+            </div>
+            <p>If you have already finished it, mark it as completed to stop further reminders.</p>
+        </div>
+        <div class="footer">
+            <p>&copy; 2025 Todo App. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>`
+
+	return fmt.Sprintf(template,
+		html.EscapeString(username),
+		humanizeUntil(time.Until(deadline)),
+		html.EscapeString(todoTitle),
+		deadline.Format("Mon, 02 Jan 2006 15:04 MST"),
+	)
+}
+
+// getListInvitationEmailTemplate returns HTML template for shared list invitations
+func (s *emailService) getListInvitationEmailTemplate(inviterName, listName string, role models.ListRole, invitationURL string) string {
+	template := `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>List Invitation</title>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #673AB7; color: white; padding: 20px; text-align: center; }
+        .content { padding: 20px; background-color: #f9f9f9; }
+        .button { display: inline-block; padding: 12px 24px; background-color: #673AB7; color: white; text-decoration: none; border-radius: 4px; }
+        .footer { text-align: center; color: #666; font-size: 12px; margin-top: 20px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>You're Invited</h1>
+        </div>
+        <div class="content">
+            <h2>Hello,</h2>
+            <p><strong>%s</strong> invited you to join the list <strong>%s</strong> as %s.</p>
+            <p>Sign in with this email address and accept the invitation:</p>
+            <p style="text-align: center;">
+                <a href="%s" class="button">View Invitation</a>
+            </p>
+            <p>Or copy and paste this link in your app:</p>
+            <p style="word-break: break-all;">%s</p>
+            <p>If you weren't expecting this invitation, you can ignore this email.</p>
+        </div>
+        <div class="footer">
+            <p>&copy; 2025 Todo App. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>`
+
+	return fmt.Sprintf(template,
+		html.EscapeString(inviterName),
+		html.EscapeString(listName),
+		html.EscapeString(string(role)),
+		invitationURL,
+		invitationURL,
+	)
+}
+
+// getTodoAssignedEmailTemplate returns HTML template for todo assignment notifications
+func (s *emailService) getTodoAssignedEmailTemplate(username, assignerName, todoTitle string, deadline time.Time) string {
+	template := `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Todo Assigned</title>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #2196F3; color: white; padding: 20px; text-align: center; }
+        .content { padding: 20px; background-color: #f9f9f9; }
+        .todo { background-color: #fff; border-left: 4px solid #2196F3; padding: 15px; margin: 15px 0; }
+        .footer { text-align: center; color: #666; font-size: 12px; margin-top: 20px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>New Assignment</h1>
+        </div>
+        <div class="content">
+            <h2>Hello %s,</h2>
+            <p><strong>%s</strong> assigned a todo to you:</p>
+            <div class="todo">
+                <strong>%s</strong><br>
+                Deadline: %s
+            </div>
+        </div>
+        <div class="footer">
+            <p>&copy; 2025 Todo App. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>`
+
+	return fmt.Sprintf(template,
+		html.EscapeString(username),
+		html.EscapeString(assignerName),
+		html.EscapeString(todoTitle),
+		deadline.Format("Mon, 02 Jan 2006 15:04 MST"),
+	)
+}
+
+// getCommentMentionEmailTemplate returns HTML template for comment mention notifications
+func (s *emailService) getCommentMentionEmailTemplate(username, authorName, todoTitle, commentBody string) string {
+	template := `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>You Were Mentioned</title>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #2196F3; color: white; padding: 20px; text-align: center; }
+        .content { padding: 20px; background-color: #f9f9f9; }
+        .comment { background-color: #fff; border-left: 4px solid #2196F3; padding: 15px; margin: 15px 0; white-space: pre-wrap; }
+        .footer { text-align: center; color: #666; font-size: 12px; margin-top: 20px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>New Mention</h1>
+        </div>
+        <div class="content">
+            <h2>Hello %s,</h2>
+            <p><strong>%s</strong> mentioned you in a comment on <strong>%s</strong>:</p>
+            <div class="comment">%s</div>
+        </div>
+        <div class="footer">
+            <p>&copy; 2025 Todo App. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>`
+
+	return fmt.Sprintf(template,
+		html.EscapeString(username),
+		html.EscapeString(authorName),
+		html.EscapeString(todoTitle),
+		html.EscapeString(commentBody),
+	)
+}
+
+// getDataExportEmailTemplate returns HTML template for the download link of a data export
+func (s *emailService) getDataExportEmailTemplate(username, downloadURL string, expiresAt time.Time) string {
+	template := `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Data Export Ready</title>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #607D8B; color: white; padding: 20px; text-align: center; }
+        .content { padding: 20px; background-color: #f9f9f9; }
+        .button { display: inline-block; padding: 12px 24px; background-color: #607D8B; color: white; text-decoration: none; border-radius: 4px; }
+        .footer { text-align: center; color: #666; font-size: 12px; margin-top: 20px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>Your Data Export</h1>
+        </div>
+        <div class="content">
+            <h2>Hello %s,</h2>
+            <p>The export of your account data you requested is ready. It is a ZIP archive of JSON files with your profile, preferences, todos, lists, comments and security events.</p>
+            <p style="text-align: center;">
+                <a href="%s" class="button">Download Export</a>
+            </p>
+            <p>Or copy and paste this link in your browser:</p>
+            <p style="word-break: break-all;">%s</p>
+            <p>The link expires %s (%s UTC). If you didn't request this export, change your password.</p>
+        </div>
+        <div class="footer">
+            <p>&copy; 2025 Todo App. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>`
+
+	return fmt.Sprintf(template,
+		html.EscapeString(username),
+		downloadURL,
+		downloadURL,
+		humanizeUntil(time.Until(expiresAt)),
+		expiresAt.UTC().Format("Mon, 02 Jan 2006 15:04"),
+	)
+}
+
+// getAccountDeletionEmailTemplate returns HTML template for scheduled account deletions
+func (s *emailService) getAccountDeletionEmailTemplate(username string, deletionAt time.Time) string {
+	template := `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Account Deletion Scheduled</title>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #f44336; color: white; padding: 20px; text-align: center; }
+        .content { padding: 20px; background-color: #f9f9f9; }
+        .footer { text-align: center; color: #666; font-size: 12px; margin-top: 20px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>Account Deletion Scheduled</h1>
+        </div>
+        <div class="content">
+            <h2>Hello %s,</h2>
+            <p>Your account and all of its data will be permanently deleted on <strong>%s UTC</strong>. You have been signed out of every device.</p>
+            <p>Changed your mind? Simply log in before then and the deletion will be cancelled.</p>
+            <p>If you didn't request this, log in right away and change your password.</p>
+        </div>
+        <div class="footer">
+            <p>&copy; 2025 Todo App. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>`
+
+	return fmt.Sprintf(template,
+		html.EscapeString(username),
+		deletionAt.UTC().Format("Mon, 02 Jan 2006 15:04"),
+	)
+}
+
+// getDigestEmailTemplate returns HTML template for the daily digest
+func (s *emailService) getDigestEmailTemplate(username string, digest *models.TodoDigest, unsubscribeURL string) string {
+	template := `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Daily Digest</title>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #4CAF50; color: white; padding: 20px; text-align: center; }
+        .content { padding: 20px; background-color: #f9f9f9; }
+        .stats { background-color: #fff; padding: 15px; margin: 15px 0; }
+        .overdue { color: #D32F2F; }
+        .footer { text-align: center; color: #666; font-size: 12px; margin-top: 20px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>Your Daily Digest</h1>
+            <p>%s</p>
+        </div>
+        <div class="content">
+            <h2>Good morning %s,</h2>
+            <div class="stats">
+                %d pending &middot; %d overdue &middot; %d due today &middot; %d due this week
+            </div>
+            %s
+            %s
+            %s
+        </div>
+        <div class="footer">
+            <p>You are receiving this email because you subscribed to the daily digest.</p>
+            <p><a href="%s">Unsubscribe</a></p>
+            <p>&copy; 2025 Todo App. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>`
+
+	loc := digest.Date.Location()
+	return fmt.Sprintf(template,
+		digest.Date.Format("Monday, 02 January 2006"),
+		html.EscapeString(username),
+		digest.Stats.PendingTodos, digest.Stats.OverdueTodos, digest.Stats.TodayTodos, digest.Stats.ThisWeekTodos,
+		digestSection("Overdue", "overdue", digest.Overdue, "02 Jan 15:04", loc),
+		digestSection("Due today", "", digest.Today, "15:04", loc),
+		digestSection("Later this week", "", digest.ThisWeek, "Mon 02 Jan 15:04", loc),
+		unsubscribeURL,
+	)
+}
+
+// digestSection renders a titled list of todos, or nothing when the list is empty
+func digestSection(title, class string, todos []*models.Todo, layout string, loc *time.Location) string {
+	if len(todos) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<h3 class=\"%s\">%s</h3><ul>", class, title)
+	for _, todo := range todos {
+		fmt.Fprintf(&b, "<li><strong>%s</strong> &mdash; %s</li>",
+			html.EscapeString(todo.Title), todo.Deadline.In(loc).Format(layout))
+	}
+	b.WriteString("</ul>")
+	return b.String()
+}
+
+// humanizeUntil renders a duration as a short phrase such as "in 2 hours"
+func humanizeUntil(d time.Duration) string {
+	switch {
+	case d <= time.Minute:
+		return "now"
+	case d < time.Hour:
+		return pluralize(int(d.Minutes()), "minute")
+	case d < 24*time.Hour:
+		return pluralize(int(d.Round(time.Hour).Hours()), "hour")
+	default:
+		return pluralize(int(d.Round(24*time.Hour).Hours()/24), "day")
+	}
+}
+
+func pluralize(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("in 1 %s", unit)
+	}
+	return fmt.Sprintf("in %d %ss", n, unit)
+}
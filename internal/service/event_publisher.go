@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+
+	"go-backend-todo/internal/models"
+)
+
+// EventPublisher delivers todo lifecycle events to interested consumers
+type EventPublisher interface {
+	Publish(ctx context.Context, event models.TodoEvent) error
+}
+
+// multiPublisher fans events out to several publishers
+type multiPublisher struct {
+	publishers []EventPublisher
+}
+
+// NewMultiPublisher creates a publisher that forwards every event to all publishers
+func NewMultiPublisher(publishers ...EventPublisher) EventPublisher {
+	return &multiPublisher{publishers: publishers}
+}
+
+// Publish forwards the event to every publisher, returning the first error after trying them all
+func (p *multiPublisher) Publish(ctx context.Context, event models.TodoEvent) error {
+	var firstErr error
+	for _, publisher := range p.publishers {
+		if err := publisher.Publish(ctx, event); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"go-backend-todo/internal/config"
+	"go-backend-todo/internal/models"
+	comment_repository "go-backend-todo/internal/repository/comment"
+	export_repository "go-backend-todo/internal/repository/export"
+	todo_repository "go-backend-todo/internal/repository/todo"
+	user_repository "go-backend-todo/internal/repository/user"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// dataExportPurpose scopes signed download links to data exports
+	dataExportPurpose = "data-export"
+	// dataExportBatchSize caps the number of exports assembled per poll, since each one reads all of a user's data
+	dataExportBatchSize = 5
+	// dataExportLease is how long a worker owns an export it is assembling
+	dataExportLease = 10 * time.Minute
+	// dataExportEventPage is the page size used to read the security events of a user
+	dataExportEventPage = 500
+	// dataExportFailedRetention is how long failed exports are kept for inspection
+	dataExportFailedRetention = 7 * 24 * time.Hour
+)
+
+var (
+	// ErrExportInProgress is returned when a user requests an export while another one is pending or downloadable
+	ErrExportInProgress = errors.New("a data export is already in progress")
+	// ErrExportNotFound is returned when a download link is invalid or its export expired
+	ErrExportNotFound = errors.New("data export not found or expired")
+)
+
+// ExportService interface defines business logic for exporting all data stored about a user
+type ExportService interface {
+	// RequestExport queues an export whose download link is emailed once the archive is assembled
+	RequestExport(ctx context.Context, userID uuid.UUID) (*models.DataExport, error)
+	ProcessPendingExports(ctx context.Context) (int, error)
+	// GetArchive returns the export behind a signed download link with its ZIP archive
+	GetArchive(ctx context.Context, token string) (*models.DataExport, []byte, error)
+	PurgeExpiredExports(ctx context.Context) (int64, error)
+}
+
+// exportService implementation of ExportService interface
+type exportService struct {
+	exportRepo        export_repository.ExportRepository
+	userRepo          user_repository.UserRepository
+	todoRepo          todo_repository.TodoRepository
+	commentRepo       comment_repository.CommentRepository
+	listService       ListService
+	preferenceService PreferenceService
+	auditService      AuditService
+	emailService      EmailService
+	config            *config.Config
+}
+
+// NewExportService creates a new instance of export service
+func NewExportService(
+	exportRepo export_repository.ExportRepository,
+	userRepo user_repository.UserRepository,
+	todoRepo todo_repository.TodoRepository,
+	commentRepo comment_repository.CommentRepository,
+	listService ListService,
+	preferenceService PreferenceService,
+	auditService AuditService,
+	emailService EmailService,
+	cfg *config.Config,
+) ExportService {
+	return &exportService{
+		exportRepo:        exportRepo,
+		userRepo:          userRepo,
+		todoRepo:          todoRepo,
+		commentRepo:       commentRepo,
+		listService:       listService,
+		preferenceService: preferenceService,
+		auditService:      auditService,
+		emailService:      emailService,
+		config:            cfg,
+	}
+}
+
+// RequestExport queues an export unless the user already has one pending or ready to download
+func (s *exportService) RequestExport(ctx context.Context, userID uuid.UUID) (*models.DataExport, error) {
+	active, err := s.exportRepo.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check data exports: %w", err)
+	}
+	if active != nil {
+		return nil, ErrExportInProgress
+	}
+
+	export := &models.DataExport{UserID: userID}
+	if err := s.exportRepo.Create(ctx, export); err != nil {
+		return nil, fmt.Errorf("failed to create data export: %w", err)
+	}
+
+	s.auditService.Record(ctx, models.AuditDataExportRequested, &userID, "", "")
+	return export, nil
+}
+
+// ProcessPendingExports claims pending exports, assembles their archives and emails the download links.
+// A failed export is retried by later polls until it runs out of attempts.
+func (s *exportService) ProcessPendingExports(ctx context.Context) (int, error) {
+	exports, err := s.exportRepo.ClaimPending(ctx, time.Now(), dataExportLease, dataExportBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim pending data exports: %w", err)
+	}
+
+	processed := 0
+	for _, export := range exports {
+		if err := s.processExport(ctx, export); err != nil {
+			log.Printf("Failed to process data export %s: %v", export.ID, err)
+			failed := export.Attempts >= s.config.Export.MaxAttempts
+			if err := s.exportRepo.MarkFailed(ctx, export.ID, err.Error(), failed); err != nil {
+				log.Printf("Failed to mark data export %s as failed: %v", export.ID, err)
+			}
+			continue
+		}
+		processed++
+	}
+
+	return processed, nil
+}
+
+// processExport assembles the archive of an export, emails the download link and stores the archive.
+// The email goes out first so that a failure to send it is retried like any other, instead of leaving a
+// ready export the user never heard of and cannot request again until it expires.
+func (s *exportService) processExport(ctx context.Context, export *models.DataExport) error {
+	user, err := s.userRepo.GetByID(ctx, export.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return fmt.Errorf("user %s no longer exists", export.UserID)
+	}
+
+	var buf bytes.Buffer
+	if err := s.writeArchive(ctx, &buf, user); err != nil {
+		return fmt.Errorf("failed to assemble archive: %w", err)
+	}
+
+	ttl := time.Duration(s.config.Export.LinkTTL) * time.Hour
+	expiresAt := time.Now().Add(ttl)
+
+	token, err := generateSignedLinkToken(s.config.Token.DataExportTokenSecret, s.config.App.Name, dataExportPurpose, export.ID.String(), ttl)
+	if err != nil {
+		return fmt.Errorf("failed to generate download token: %w", err)
+	}
+
+	downloadURL := fmt.Sprintf("%s/api/v1/exports/%s", s.config.App.BaseURL, token)
+
+	emailCtx, cancel := context.WithTimeout(ctx, time.Duration(s.config.Timeouts.EmailTimeout.EmailSendTimeout)*time.Second)
+	defer cancel()
+
+	if err := s.emailService.SendDataExportEmail(emailCtx, user.Email, user.Username, downloadURL, expiresAt); err != nil {
+		return fmt.Errorf("failed to send data export email: %w", err)
+	}
+
+	// Links are signed for the export rather than the attempt, so if storing fails the link
+	// already sent starts working once a retry stores the archive
+	if err := s.exportRepo.MarkReady(ctx, export.ID, buf.Bytes(), expiresAt); err != nil {
+		return fmt.Errorf("failed to store archive: %w", err)
+	}
+
+	return nil
+}
+
+// writeArchive writes a ZIP archive with one JSON file per kind of data stored about the user
+func (s *exportService) writeArchive(ctx context.Context, w io.Writer, user *models.UserProfile) error {
+	archive := zip.NewWriter(w)
+
+	preferences, err := s.preferenceService.GetPreferences(ctx, user.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get preferences: %w", err)
+	}
+
+	lists, err := s.listService.GetLists(ctx, user.UserID)
+	if err != nil {
+		return err
+	}
+
+	trash, err := s.todoRepo.GetDeleted(ctx, models.TodoFilter{UserID: user.UserID})
+	if err != nil {
+		return fmt.Errorf("failed to get deleted todos: %w", err)
+	}
+
+	comments, err := s.commentRepo.GetByAuthorID(ctx, user.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get comments: %w", err)
+	}
+
+	events, err := s.securityEvents(ctx, user.UserID)
+	if err != nil {
+		return err
+	}
+
+	files := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", user},
+		{"preferences.json", preferences},
+		{"lists.json", lists},
+		{"trash.json", trash},
+		{"comments.json", comments},
+		{"security_events.json", events},
+	}
+	for _, file := range files {
+		if err := writeArchiveJSON(archive, file.name, file.data); err != nil {
+			return err
+		}
+	}
+
+	// Todos are encoded as they are read rather than loaded into a slice first, but the whole archive
+	// is still built in memory and stored as one value
+	f, err := archive.Create("todos.json")
+	if err != nil {
+		return err
+	}
+	encoder, err := newTodoEncoder(models.ExportFormatJSON, f, s.config.App.Name)
+	if err != nil {
+		return err
+	}
+	if err := encoder.Begin(); err != nil {
+		return err
+	}
+	if err := s.todoRepo.StreamByUserID(ctx, user.UserID, encoder.Encode); err != nil {
+		return fmt.Errorf("failed to get todos: %w", err)
+	}
+	if err := encoder.End(); err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+// securityEvents reads every security event of a user, newest first
+func (s *exportService) securityEvents(ctx context.Context, userID uuid.UUID) ([]*models.AuditEvent, error) {
+	events := []*models.AuditEvent{}
+	for offset := 0; ; offset += dataExportEventPage {
+		page, _, err := s.auditService.GetUserEvents(ctx, userID, dataExportEventPage, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get security events: %w", err)
+		}
+		events = append(events, page...)
+		if len(page) < dataExportEventPage {
+			return events, nil
+		}
+	}
+}
+
+// GetArchive validates a signed download link and returns its export
+func (s *exportService) GetArchive(ctx context.Context, token string) (*models.DataExport, []byte, error) {
+	subject, err := parseSignedLinkToken(s.config.Token.DataExportTokenSecret, dataExportPurpose, token)
+	if err != nil {
+		return nil, nil, ErrExportNotFound
+	}
+
+	id, err := uuid.Parse(subject)
+	if err != nil {
+		return nil, nil, ErrExportNotFound
+	}
+
+	export, archive, err := s.exportRepo.GetArchive(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get data export: %w", err)
+	}
+	if export == nil {
+		return nil, nil, ErrExportNotFound
+	}
+
+	s.auditService.Record(ctx, models.AuditDataExportDownloaded, &export.UserID, "", "")
+	return export, archive, nil
+}
+
+// PurgeExpiredExports deletes archives whose download link expired
+func (s *exportService) PurgeExpiredExports(ctx context.Context) (int64, error) {
+	now := time.Now()
+	purged, err := s.exportRepo.DeleteExpired(ctx, now, now.Add(-dataExportFailedRetention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge expired data exports: %w", err)
+	}
+	return purged, nil
+}
+
+// writeArchiveJSON adds an indented JSON file to an archive
+func writeArchiveJSON(archive *zip.Writer, name string, data any) error {
+	f, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
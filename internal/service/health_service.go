@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-backend-todo/internal/db"
+	"go-backend-todo/internal/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// healthCheckTimeout bounds each readiness check, so probes get an answer before they time out themselves
+const healthCheckTimeout = 2 * time.Second
+
+// HealthService interface defines the liveness, readiness and build of an instance for load balancers and orchestrators
+type HealthService interface {
+	// StartDraining makes readiness fail from now on, so traffic moves to other instances before shutdown
+	StartDraining()
+	Readiness(ctx context.Context) *models.Readiness
+	BuildInfo() *models.BuildInfo
+}
+
+// healthService implementation of HealthService interface
+type healthService struct {
+	pool         *pgxpool.Pool
+	migrator     *db.Migrator
+	emailService EmailService
+	draining     atomic.Bool
+
+	buildInfoOnce sync.Once
+	buildInfo     *models.BuildInfo
+}
+
+// NewHealthService creates a new instance of health service
+func NewHealthService(pool *pgxpool.Pool, migrator *db.Migrator, emailService EmailService) HealthService {
+	return &healthService{
+		pool:         pool,
+		migrator:     migrator,
+		emailService: emailService,
+	}
+}
+
+// StartDraining marks the instance as shutting down
+func (s *healthService) StartDraining() {
+	s.draining.Store(true)
+}
+
+// Readiness checks the dependencies needed to serve requests. A draining instance is never ready.
+// The checks run concurrently, so a probe waits at most one check timeout.
+func (s *healthService) Readiness(ctx context.Context) *models.Readiness {
+	checkFuncs := []struct {
+		name string
+		fn   func(ctx context.Context) error
+	}{
+		{"database", s.pool.Ping},
+		{"migrations", s.checkMigrations},
+		{"email", s.emailService.CheckTransport},
+	}
+
+	checks := make([]models.HealthCheck, len(checkFuncs))
+	var wg sync.WaitGroup
+	for i, check := range checkFuncs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checks[i] = s.check(ctx, check.name, check.fn)
+		}()
+	}
+	wg.Wait()
+
+	readiness := &models.Readiness{
+		Ready:    !s.draining.Load(),
+		Draining: s.draining.Load(),
+		Checks:   checks,
+	}
+	for _, check := range checks {
+		readiness.Ready = readiness.Ready && check.OK
+	}
+	return readiness
+}
+
+// BuildInfo reads the build information embedded in the binary by the Go toolchain
+func (s *healthService) BuildInfo() *models.BuildInfo {
+	s.buildInfoOnce.Do(func() {
+		s.buildInfo = &models.BuildInfo{Version: "unknown"}
+
+		info, ok := debug.ReadBuildInfo()
+		if !ok {
+			return
+		}
+
+		s.buildInfo.Version = info.Main.Version
+		s.buildInfo.GoVersion = info.GoVersion
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				s.buildInfo.Revision = setting.Value
+			case "vcs.time":
+				s.buildInfo.CommitTime = setting.Value
+			case "vcs.modified":
+				s.buildInfo.Modified = setting.Value == "true"
+			}
+		}
+	})
+	return s.buildInfo
+}
+
+// checkMigrations fails when the database schema is behind the latest migration this binary knows.
+// A schema ahead of it is fine: during a rolling deploy the new version migrates while the old one still serves.
+func (s *healthService) checkMigrations(ctx context.Context) error {
+	version, err := s.migrator.Version(ctx)
+	if err != nil {
+		return err
+	}
+
+	if latest := s.migrator.Latest(); version < latest {
+		return fmt.Errorf("database schema is at version %d, expected at least %d", version, latest)
+	}
+	return nil
+}
+
+// check runs one readiness check with a timeout
+func (s *healthService) check(ctx context.Context, name string, fn func(ctx context.Context) error) models.HealthCheck {
+	checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	if err := fn(checkCtx); err != nil {
+		return models.HealthCheck{Name: name, Error: err.Error()}
+	}
+	return models.HealthCheck{Name: name, OK: true}
+}
@@ -0,0 +1,368 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go-backend-todo/internal/config"
+	"go-backend-todo/internal/models"
+	list_repository "go-backend-todo/internal/repository/list"
+	user_repository "go-backend-todo/internal/repository/user"
+	"go-backend-todo/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrListNotFound is returned when a list does not exist or the user is not a member
+	ErrListNotFound = errors.New("list not found or access denied")
+	// ErrListForbidden is returned when a member's role does not allow the action
+	ErrListForbidden = errors.New("insufficient permissions for this list")
+)
+
+// ListService interface defines business logic for shared todo lists
+type ListService interface {
+	CreateList(ctx context.Context, userID uuid.UUID, req *models.CreateListRequest) (*models.TodoList, error)
+	GetLists(ctx context.Context, userID uuid.UUID) ([]*models.TodoList, error)
+	GetList(ctx context.Context, id, userID uuid.UUID) (*models.TodoList, error)
+	UpdateList(ctx context.Context, id, userID uuid.UUID, req *models.UpdateListRequest) (*models.TodoList, error)
+	DeleteList(ctx context.Context, id, userID uuid.UUID) error
+
+	GetMembers(ctx context.Context, id, userID uuid.UUID) ([]*models.ListMember, error)
+	UpdateMemberRole(ctx context.Context, id, memberID, userID uuid.UUID, role models.ListRole) error
+	RemoveMember(ctx context.Context, id, memberID, userID uuid.UUID) error
+
+	InviteMember(ctx context.Context, id, userID uuid.UUID, req *models.InviteListMemberRequest) (*models.ListInvitation, error)
+	GetInvitations(ctx context.Context, id, userID uuid.UUID) ([]*models.ListInvitation, error)
+	RevokeInvitation(ctx context.Context, id, invitationID, userID uuid.UUID) error
+	GetInvitation(ctx context.Context, token string, userID uuid.UUID) (*models.ListInvitation, error)
+	AcceptInvitation(ctx context.Context, token string, userID uuid.UUID) (*models.TodoList, error)
+	// PurgeExpiredInvitations deletes the invitations that expired without being accepted
+	PurgeExpiredInvitations(ctx context.Context) (int64, error)
+
+	// Authorize checks that a user has at least the required role in a list and returns the user's role
+	Authorize(ctx context.Context, listID, userID uuid.UUID, required models.ListRole) (models.ListRole, error)
+	GetMemberIDs(ctx context.Context, listID uuid.UUID) ([]uuid.UUID, error)
+}
+
+// listService implementation of ListService interface
+type listService struct {
+	listRepo     list_repository.ListRepository
+	userRepo     user_repository.UserRepository
+	emailService EmailService
+	config       *config.Config
+}
+
+// NewListService creates a new instance of list service
+func NewListService(listRepo list_repository.ListRepository, userRepo user_repository.UserRepository, emailService EmailService, cfg *config.Config) ListService {
+	return &listService{
+		listRepo:     listRepo,
+		userRepo:     userRepo,
+		emailService: emailService,
+		config:       cfg,
+	}
+}
+
+// CreateList creates a list owned by the user
+func (s *listService) CreateList(ctx context.Context, userID uuid.UUID, req *models.CreateListRequest) (*models.TodoList, error) {
+	now := time.Now()
+	list := &models.TodoList{
+		ID:        uuid.New(),
+		Name:      strings.TrimSpace(req.Name),
+		OwnerID:   userID,
+		Role:      models.ListRoleOwner,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if list.Name == "" {
+		return nil, utils.ErrInvalidInput("List name cannot be empty")
+	}
+
+	if err := s.listRepo.Create(ctx, list); err != nil {
+		return nil, fmt.Errorf("failed to create list: %w", err)
+	}
+
+	return list, nil
+}
+
+// GetLists retrieves the lists the user is a member of
+func (s *listService) GetLists(ctx context.Context, userID uuid.UUID) ([]*models.TodoList, error) {
+	lists, err := s.listRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lists: %w", err)
+	}
+
+	return lists, nil
+}
+
+// GetList retrieves a list the user is a member of
+func (s *listService) GetList(ctx context.Context, id, userID uuid.UUID) (*models.TodoList, error) {
+	role, err := s.Authorize(ctx, id, userID, models.ListRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := s.listRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get list: %w", err)
+	}
+
+	list.Role = role
+	return list, nil
+}
+
+// UpdateList renames a list, owner only
+func (s *listService) UpdateList(ctx context.Context, id, userID uuid.UUID, req *models.UpdateListRequest) (*models.TodoList, error) {
+	list, err := s.GetList(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !list.Role.Allows(models.ListRoleOwner) {
+		return nil, ErrListForbidden
+	}
+
+	list.Name = strings.TrimSpace(req.Name)
+	if list.Name == "" {
+		return nil, utils.ErrInvalidInput("List name cannot be empty")
+	}
+
+	if err := s.listRepo.Update(ctx, list); err != nil {
+		return nil, fmt.Errorf("failed to update list: %w", err)
+	}
+
+	return list, nil
+}
+
+// DeleteList deletes a list with all of its todos, owner only
+func (s *listService) DeleteList(ctx context.Context, id, userID uuid.UUID) error {
+	if _, err := s.Authorize(ctx, id, userID, models.ListRoleOwner); err != nil {
+		return err
+	}
+
+	if err := s.listRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete list: %w", err)
+	}
+
+	return nil
+}
+
+// GetMembers retrieves the members of a list the user is a member of
+func (s *listService) GetMembers(ctx context.Context, id, userID uuid.UUID) ([]*models.ListMember, error) {
+	if _, err := s.Authorize(ctx, id, userID, models.ListRoleViewer); err != nil {
+		return nil, err
+	}
+
+	members, err := s.listRepo.GetMembers(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get list members: %w", err)
+	}
+
+	return members, nil
+}
+
+// UpdateMemberRole changes the role of a member, owner only. The owner's own role cannot change.
+func (s *listService) UpdateMemberRole(ctx context.Context, id, memberID, userID uuid.UUID, role models.ListRole) error {
+	if _, err := s.Authorize(ctx, id, userID, models.ListRoleOwner); err != nil {
+		return err
+	}
+	if memberID == userID {
+		return utils.ErrInvalidInput("The owner's role cannot be changed")
+	}
+
+	if err := s.listRepo.UpdateMemberRole(ctx, id, memberID, role); err != nil {
+		return fmt.Errorf("failed to update member role: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveMember removes a member from a list. The owner can remove anyone else and members can remove themselves.
+func (s *listService) RemoveMember(ctx context.Context, id, memberID, userID uuid.UUID) error {
+	required := models.ListRoleOwner
+	if memberID == userID {
+		required = models.ListRoleViewer
+	}
+
+	role, err := s.Authorize(ctx, id, userID, required)
+	if err != nil {
+		return err
+	}
+	if memberID == userID && role == models.ListRoleOwner {
+		return utils.ErrInvalidInput("The owner cannot leave the list, delete it instead")
+	}
+
+	if err := s.listRepo.RemoveMember(ctx, id, memberID); err != nil {
+		return fmt.Errorf("failed to remove member: %w", err)
+	}
+
+	return nil
+}
+
+// InviteMember emails an invitation to join a list, owner only
+func (s *listService) InviteMember(ctx context.Context, id, userID uuid.UUID, req *models.InviteListMemberRequest) (*models.ListInvitation, error) {
+	list, err := s.GetList(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !list.Role.Allows(models.ListRoleOwner) {
+		return nil, ErrListForbidden
+	}
+
+	count, err := s.listRepo.CountMembers(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count list members: %w", err)
+	}
+	if count >= s.config.List.MaxMembers {
+		return nil, utils.ErrInvalidInput(fmt.Sprintf("A list can have at most %d members", s.config.List.MaxMembers))
+	}
+
+	inviter, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inviter: %w", err)
+	}
+
+	token, err := generateRandomToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate invitation token: %w", err)
+	}
+
+	now := time.Now()
+	invitation := &models.ListInvitation{
+		ID:        uuid.New(),
+		ListID:    id,
+		ListName:  list.Name,
+		Email:     strings.ToLower(strings.TrimSpace(req.Email)),
+		Role:      req.Role,
+		TokenHash: hashToken(token),
+		InvitedBy: userID,
+		ExpiresAt: now.Add(time.Duration(s.config.List.InvitationTTL) * time.Hour),
+		CreatedAt: now,
+	}
+
+	if err := s.listRepo.CreateInvitation(ctx, invitation); err != nil {
+		return nil, fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	invitationURL := fmt.Sprintf("%s/api/v1/lists/invitations/%s", s.config.App.BaseURL, token)
+
+	emailCtx, cancel := context.WithTimeout(ctx, time.Duration(s.config.Timeouts.EmailTimeout.EmailSendTimeout)*time.Second)
+	defer cancel()
+
+	// The invitation stays valid if the email fails, so the owner can resend it by inviting again
+	if err := s.emailService.SendListInvitationEmail(emailCtx, invitation.Email, inviter.Username, list.Name, invitation.Role, invitationURL); err != nil {
+		log.Printf("Failed to send invitation %s for list %s: %v", invitation.ID, id, err)
+	}
+
+	return invitation, nil
+}
+
+// GetInvitations retrieves the pending invitations of a list, owner only
+func (s *listService) GetInvitations(ctx context.Context, id, userID uuid.UUID) ([]*models.ListInvitation, error) {
+	if _, err := s.Authorize(ctx, id, userID, models.ListRoleOwner); err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.listRepo.GetPendingInvitations(ctx, id, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invitations: %w", err)
+	}
+
+	return invitations, nil
+}
+
+// RevokeInvitation deletes an invitation of a list, owner only
+func (s *listService) RevokeInvitation(ctx context.Context, id, invitationID, userID uuid.UUID) error {
+	if _, err := s.Authorize(ctx, id, userID, models.ListRoleOwner); err != nil {
+		return err
+	}
+
+	if err := s.listRepo.DeleteInvitation(ctx, invitationID, id); err != nil {
+		return fmt.Errorf("failed to revoke invitation: %w", err)
+	}
+
+	return nil
+}
+
+// GetInvitation retrieves a valid invitation addressed to the user's email
+func (s *listService) GetInvitation(ctx context.Context, token string, userID uuid.UUID) (*models.ListInvitation, error) {
+	invitation, err := s.listRepo.GetInvitationByTokenHash(ctx, hashToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invitation: %w", err)
+	}
+	if invitation == nil || invitation.AcceptedAt != nil || time.Now().After(invitation.ExpiresAt) {
+		return nil, utils.ErrInvalidCredentials("Invalid or expired invitation")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Invitations cannot be forwarded: only the invited address may use them
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, utils.ErrInvalidCredentials("This invitation was sent to a different email address")
+	}
+
+	return invitation, nil
+}
+
+// AcceptInvitation adds the user to the list of a valid invitation addressed to the user's email
+func (s *listService) AcceptInvitation(ctx context.Context, token string, userID uuid.UUID) (*models.TodoList, error) {
+	invitation, err := s.GetInvitation(ctx, token, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.listRepo.CountMembers(ctx, invitation.ListID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count list members: %w", err)
+	}
+	if count >= s.config.List.MaxMembers {
+		return nil, utils.ErrInvalidInput("The list has reached its member limit")
+	}
+
+	if err := s.listRepo.AcceptInvitation(ctx, invitation, userID); err != nil {
+		return nil, fmt.Errorf("failed to accept invitation: %w", err)
+	}
+
+	return s.GetList(ctx, invitation.ListID, userID)
+}
+
+// PurgeExpiredInvitations deletes unaccepted invitations whose link expired
+func (s *listService) PurgeExpiredInvitations(ctx context.Context) (int64, error) {
+	purged, err := s.listRepo.DeleteExpiredInvitations(ctx, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired invitations: %w", err)
+	}
+	return purged, nil
+}
+
+// Authorize checks that a user has at least the required role in a list
+func (s *listService) Authorize(ctx context.Context, listID, userID uuid.UUID, required models.ListRole) (models.ListRole, error) {
+	role, err := s.listRepo.GetMemberRole(ctx, listID, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get list role: %w", err)
+	}
+	if role == "" {
+		return "", ErrListNotFound
+	}
+	if !role.Allows(required) {
+		return role, ErrListForbidden
+	}
+
+	return role, nil
+}
+
+// GetMemberIDs retrieves the user IDs of the members of a list
+func (s *listService) GetMemberIDs(ctx context.Context, listID uuid.UUID) ([]uuid.UUID, error) {
+	ids, err := s.listRepo.GetMemberIDs(ctx, listID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get list members: %w", err)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-backend-todo/internal/config"
+	"go-backend-todo/internal/models"
+	user_repository "go-backend-todo/internal/repository/user"
+
+	"github.com/google/uuid"
+)
+
+// NotificationService interface defines business logic for notifying users about activity on shared todos
+type NotificationService interface {
+	NotifyTodoAssigned(ctx context.Context, todo *models.Todo, assignerID uuid.UUID) error
+	NotifyCommentMention(ctx context.Context, todo *models.Todo, comment *models.TodoComment, mentioned *models.UserProfile) error
+}
+
+// notificationService implementation of NotificationService interface
+type notificationService struct {
+	userRepo          user_repository.UserRepository
+	preferenceService PreferenceService
+	emailService      EmailService
+	config            *config.Config
+}
+
+// NewNotificationService creates a new instance of notification service
+func NewNotificationService(userRepo user_repository.UserRepository, preferenceService PreferenceService, emailService EmailService, cfg *config.Config) NotificationService {
+	return &notificationService{
+		userRepo:          userRepo,
+		preferenceService: preferenceService,
+		emailService:      emailService,
+		config:            cfg,
+	}
+}
+
+// NotifyTodoAssigned emails the assignee of a todo, unless they assigned it to themselves
+func (s *notificationService) NotifyTodoAssigned(ctx context.Context, todo *models.Todo, assignerID uuid.UUID) error {
+	if todo.AssigneeID == nil || *todo.AssigneeID == assignerID {
+		return nil
+	}
+
+	assignee, err := s.userRepo.GetByID(ctx, *todo.AssigneeID)
+	if err != nil {
+		return fmt.Errorf("failed to get assignee: %w", err)
+	}
+
+	assigner, err := s.userRepo.GetByID(ctx, assignerID)
+	if err != nil {
+		return fmt.Errorf("failed to get assigner: %w", err)
+	}
+
+	prefs, err := s.preferenceService.GetPreferences(ctx, assignee.UserID)
+	if err != nil {
+		return err
+	}
+
+	emailCtx, cancel := context.WithTimeout(ctx, time.Duration(s.config.Timeouts.EmailTimeout.EmailSendTimeout)*time.Second)
+	defer cancel()
+
+	return s.emailService.SendTodoAssignedEmail(emailCtx, assignee.Email, assignee.Username, assigner.Username, todo.Title, todo.Deadline.In(prefs.Location()))
+}
+
+// NotifyCommentMention emails a user mentioned in a comment, unless they wrote it
+func (s *notificationService) NotifyCommentMention(ctx context.Context, todo *models.Todo, comment *models.TodoComment, mentioned *models.UserProfile) error {
+	if mentioned.UserID == comment.AuthorID {
+		return nil
+	}
+
+	emailCtx, cancel := context.WithTimeout(ctx, time.Duration(s.config.Timeouts.EmailTimeout.EmailSendTimeout)*time.Second)
+	defer cancel()
+
+	return s.emailService.SendCommentMentionEmail(emailCtx, mentioned.Email, mentioned.Username, comment.AuthorUsername, todo.Title, comment.Body)
+}
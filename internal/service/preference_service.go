@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-backend-todo/internal/config"
+	"go-backend-todo/internal/models"
+	preference_repository "go-backend-todo/internal/repository/preference"
+	"go-backend-todo/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// PreferenceService interface defines business logic for user preferences
+type PreferenceService interface {
+	GetPreferences(ctx context.Context, userID uuid.UUID) (*models.UserPreferences, error)
+	UpdatePreferences(ctx context.Context, userID uuid.UUID, req *models.UpdatePreferencesRequest) (*models.UserPreferences, error)
+
+	// GetDigestSettings and UpdateDigestSettings read and change only the daily digest preferences
+	GetDigestSettings(ctx context.Context, userID uuid.UUID) (*models.DigestSettings, error)
+	UpdateDigestSettings(ctx context.Context, userID uuid.UUID, req *models.UpdateDigestSettingsRequest) (*models.DigestSettings, error)
+}
+
+// preferenceService implementation of PreferenceService interface
+type preferenceService struct {
+	preferenceRepo preference_repository.PreferenceRepository
+	config         *config.Config
+}
+
+// NewPreferenceService creates a new instance of preference service
+func NewPreferenceService(preferenceRepo preference_repository.PreferenceRepository, cfg *config.Config) PreferenceService {
+	return &preferenceService{
+		preferenceRepo: preferenceRepo,
+		config:         cfg,
+	}
+}
+
+// GetPreferences returns the preferences of a user, or the defaults if the user never saved any
+func (s *preferenceService) GetPreferences(ctx context.Context, userID uuid.UUID) (*models.UserPreferences, error) {
+	prefs, err := s.preferenceRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get preferences: %w", err)
+	}
+
+	if prefs == nil {
+		prefs = &models.UserPreferences{
+			UserID:          userID,
+			Timezone:        "UTC",
+			Locale:          "en",
+			WeekStart:       int(time.Monday),
+			DefaultTodoSort: models.SortCreatedAtDesc,
+			EmailReminders:  true,
+			EmailDigest:     false,
+			DigestHour:      s.config.Digest.DefaultSendHour,
+		}
+	}
+
+	return prefs, nil
+}
+
+// UpdatePreferences validates and replaces the preferences of a user
+func (s *preferenceService) UpdatePreferences(ctx context.Context, userID uuid.UUID, req *models.UpdatePreferencesRequest) (*models.UserPreferences, error) {
+	if _, err := time.LoadLocation(req.Timezone); err != nil {
+		return nil, utils.ErrInvalidInput("Unknown timezone")
+	}
+	if !req.DefaultTodoSort.IsValid() {
+		return nil, utils.ErrInvalidInput("Unknown todo sort order")
+	}
+
+	prefs := &models.UserPreferences{
+		UserID:          userID,
+		Timezone:        req.Timezone,
+		Locale:          req.Locale,
+		WeekStart:       *req.WeekStart,
+		DefaultTodoSort: req.DefaultTodoSort,
+		EmailReminders:  *req.EmailReminders,
+		EmailDigest:     *req.EmailDigest,
+		DigestHour:      *req.DigestHour,
+	}
+
+	if err := s.preferenceRepo.Upsert(ctx, prefs); err != nil {
+		return nil, fmt.Errorf("failed to update preferences: %w", err)
+	}
+
+	return prefs, nil
+}
+
+// GetDigestSettings returns the daily digest preferences of a user
+func (s *preferenceService) GetDigestSettings(ctx context.Context, userID uuid.UUID) (*models.DigestSettings, error) {
+	prefs, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return digestSettings(prefs), nil
+}
+
+// UpdateDigestSettings opts a user in or out of the digest and updates the delivery time, keeping the other preferences
+func (s *preferenceService) UpdateDigestSettings(ctx context.Context, userID uuid.UUID, req *models.UpdateDigestSettingsRequest) (*models.DigestSettings, error) {
+	prefs, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	prefs.EmailDigest = req.Enabled
+	if req.SendHour != nil {
+		prefs.DigestHour = *req.SendHour
+	}
+	if req.Timezone != "" {
+		if _, err := time.LoadLocation(req.Timezone); err != nil {
+			return nil, utils.ErrInvalidInput("Unknown timezone")
+		}
+		prefs.Timezone = req.Timezone
+	}
+
+	if err := s.preferenceRepo.Upsert(ctx, prefs); err != nil {
+		return nil, fmt.Errorf("failed to update digest settings: %w", err)
+	}
+
+	return digestSettings(prefs), nil
+}
+
+// digestSettings returns the daily digest part of preferences
+func digestSettings(prefs *models.UserPreferences) *models.DigestSettings {
+	return &models.DigestSettings{
+		UserID:     prefs.UserID,
+		Enabled:    prefs.EmailDigest,
+		SendHour:   prefs.DigestHour,
+		Timezone:   prefs.Timezone,
+		LastSentOn: prefs.DigestLastSentOn,
+		UpdatedAt:  prefs.UpdatedAt,
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"go-backend-todo/internal/config"
+	"go-backend-todo/internal/models"
+	reminder_repository "go-backend-todo/internal/repository/reminder"
+
+	"github.com/google/uuid"
+)
+
+// ReminderService interface defines business logic for todo deadline reminders
+type ReminderService interface {
+	ScheduleReminders(ctx context.Context, todo *models.Todo, offsets []int) ([]*models.TodoReminder, error)
+	RescheduleReminders(ctx context.Context, todo *models.Todo) error
+	GetReminders(ctx context.Context, todoID uuid.UUID) ([]*models.TodoReminder, error)
+	ProcessDueReminders(ctx context.Context) (int, error)
+}
+
+// reminderService implementation of ReminderService interface
+type reminderService struct {
+	reminderRepo reminder_repository.ReminderRepository
+	emailService EmailService
+	config       *config.Config
+}
+
+// NewReminderService creates a new instance of reminder service
+func NewReminderService(reminderRepo reminder_repository.ReminderRepository, emailService EmailService, cfg *config.Config) ReminderService {
+	return &reminderService{
+		reminderRepo: reminderRepo,
+		emailService: emailService,
+		config:       cfg,
+	}
+}
+
+// ScheduleReminders replaces the reminders of a todo, falling back to the configured offsets when offsets is nil
+func (s *reminderService) ScheduleReminders(ctx context.Context, todo *models.Todo, offsets []int) ([]*models.TodoReminder, error) {
+	if offsets == nil {
+		offsets = s.config.Reminder.DefaultOffsets
+	}
+
+	reminders, err := s.reminderRepo.ReplaceForTodo(ctx, todo.ID, todo.Deadline, uniqueOffsets(offsets))
+	if err != nil {
+		return nil, fmt.Errorf("failed to schedule reminders: %w", err)
+	}
+
+	return reminders, nil
+}
+
+// RescheduleReminders moves the existing reminder offsets of a todo to its current deadline
+func (s *reminderService) RescheduleReminders(ctx context.Context, todo *models.Todo) error {
+	existing, err := s.reminderRepo.GetByTodoID(ctx, todo.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get reminders: %w", err)
+	}
+
+	offsets := make([]int, 0, len(existing))
+	for _, reminder := range existing {
+		offsets = append(offsets, reminder.OffsetMinutes)
+	}
+
+	_, err = s.ScheduleReminders(ctx, todo, offsets)
+	return err
+}
+
+// GetReminders retrieves the reminders of a todo
+func (s *reminderService) GetReminders(ctx context.Context, todoID uuid.UUID) ([]*models.TodoReminder, error) {
+	reminders, err := s.reminderRepo.GetByTodoID(ctx, todoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reminders: %w", err)
+	}
+
+	return reminders, nil
+}
+
+// ProcessDueReminders claims due reminders and delivers them by email to users who have reminder emails enabled.
+// Claimed reminders are never retried, so a failed delivery is logged and dropped.
+func (s *reminderService) ProcessDueReminders(ctx context.Context) (int, error) {
+	reminders, err := s.reminderRepo.ClaimDue(ctx, time.Now(), s.config.Reminder.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim due reminders: %w", err)
+	}
+
+	sent := 0
+	for _, reminder := range reminders {
+		if !reminder.EmailEnabled {
+			continue
+		}
+
+		loc, err := time.LoadLocation(reminder.Timezone)
+		if err != nil {
+			loc = time.UTC
+		}
+
+		emailCtx, cancel := context.WithTimeout(ctx, time.Duration(s.config.Timeouts.EmailTimeout.EmailSendTimeout)*time.Second)
+		err = s.emailService.SendTodoReminderEmail(emailCtx, reminder.Email, reminder.Username, reminder.TodoTitle, reminder.Deadline.In(loc))
+		cancel()
+		if err != nil {
+			log.Printf("Failed to send reminder %s for todo %s: %v", reminder.ReminderID, reminder.TodoID, err)
+			continue
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// uniqueOffsets removes duplicate offsets and orders them from the earliest reminder to the latest
+func uniqueOffsets(offsets []int) []int {
+	seen := make(map[int]bool, len(offsets))
+	result := make([]int, 0, len(offsets))
+	for _, offset := range offsets {
+		if offset <= 0 || seen[offset] {
+			continue
+		}
+		seen[offset] = true
+		result = append(result, offset)
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(result)))
+	return result
+}
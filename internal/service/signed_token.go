@@ -0,0 +1,66 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"go-backend-todo/internal/utils"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// signedLinkClaims are the claims of single-purpose tokens embedded in links sent by email
+type signedLinkClaims struct {
+	Purpose string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+// generateSignedLinkToken signs a token for subject that is only accepted for the given purpose
+func generateSignedLinkToken(secret, issuer, purpose, subject string, ttl time.Duration) (string, error) {
+	claims := signedLinkClaims{
+		Purpose: purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Subject:   subject,
+			Issuer:    issuer,
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(secret))
+}
+
+// parseSignedLinkToken validates a token for the given purpose and returns its subject
+func parseSignedLinkToken(secret, purpose, tokenString string) (string, error) {
+	claims := &signedLinkClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || !token.Valid {
+		return "", utils.ErrInvalidCredentials("Invalid or expired link")
+	}
+
+	if claims.Purpose != purpose {
+		return "", utils.ErrInvalidCredentials("Invalid or expired link")
+	}
+
+	return claims.Subject, nil
+}
+
+// generateRandomToken returns n random bytes encoded as hex, for secrets and single-use tokens stored hashed
+func generateRandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashToken returns the hex SHA-256 of a token, so only its hash needs to be stored
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-backend-todo/internal/models"
+)
+
+// todoEncoder writes todos one at a time in an export format
+type todoEncoder interface {
+	Begin() error
+	Encode(todo *models.Todo) error
+	End() error
+}
+
+// newTodoEncoder returns the encoder of an export format writing to w
+func newTodoEncoder(format models.ExportFormat, w io.Writer, productName string) (todoEncoder, error) {
+	switch format {
+	case models.ExportFormatJSON:
+		return &jsonTodoEncoder{w: w}, nil
+	case models.ExportFormatCSV:
+		return &csvTodoEncoder{w: csv.NewWriter(w)}, nil
+	case models.ExportFormatICS:
+		return &icsTodoEncoder{w: w, productName: productName, stamp: time.Now()}, nil
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// jsonTodoEncoder writes todos as a JSON array
+type jsonTodoEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonTodoEncoder) Begin() error {
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *jsonTodoEncoder) Encode(todo *models.Todo) error {
+	data, err := json.Marshal(todo)
+	if err != nil {
+		return err
+	}
+	if e.count > 0 {
+		if _, err := io.WriteString(e.w, ","); err != nil {
+			return err
+		}
+	}
+	e.count++
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonTodoEncoder) End() error {
+	_, err := io.WriteString(e.w, "]\n")
+	return err
+}
+
+// csvTodoHeader is the header row of CSV exports
+var csvTodoHeader = []string{"id", "title", "completed", "deadline", "list_id", "assignee_id", "assignee", "created_at", "updated_at"}
+
+// csvFormulaPrefixes are the leading characters that make spreadsheet applications evaluate a cell as a formula
+const csvFormulaPrefixes = "=+-@\t\r"
+
+// csvTodoEncoder writes todos as CSV with a header row, times in RFC 3339.
+// Text written by users is escaped so that spreadsheets never run it as a formula.
+type csvTodoEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvTodoEncoder) Begin() error {
+	return e.w.Write(csvTodoHeader)
+}
+
+func (e *csvTodoEncoder) Encode(todo *models.Todo) error {
+	var listID, assigneeID, assignee string
+	if todo.ListID != nil {
+		listID = todo.ListID.String()
+	}
+	if todo.AssigneeID != nil {
+		assigneeID = todo.AssigneeID.String()
+	}
+	if todo.Assignee != nil {
+		assignee = todo.Assignee.Username
+	}
+
+	return e.w.Write([]string{
+		todo.ID.String(),
+		escapeCSVFormula(todo.Title),
+		strconv.FormatBool(todo.Completed),
+		todo.Deadline.UTC().Format(time.RFC3339),
+		listID,
+		assigneeID,
+		escapeCSVFormula(assignee),
+		todo.CreatedAt.UTC().Format(time.RFC3339),
+		todo.UpdatedAt.UTC().Format(time.RFC3339),
+	})
+}
+
+func (e *csvTodoEncoder) End() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// escapeCSVFormula prefixes a cell that a spreadsheet would read as a formula with a quote, which makes it text
+func escapeCSVFormula(value string) string {
+	if value != "" && strings.ContainsRune(csvFormulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// icsTimeFormat is the UTC date-time format of iCalendar (RFC 5545)
+const icsTimeFormat = "20060102T150405Z"
+
+// icsMaxLineLength is the maximum length in octets of an iCalendar content line before it is folded
+const icsMaxLineLength = 75
+
+// icsTodoEncoder writes todos as an iCalendar object with one VTODO component per todo.
+// Todos do not record when they were completed, so completed todos only get STATUS and no COMPLETED time.
+type icsTodoEncoder struct {
+	w           io.Writer
+	productName string
+	stamp       time.Time
+}
+
+func (e *icsTodoEncoder) Begin() error {
+	return e.writeLines(
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//"+escapeICSText(e.productName)+"//Todos//EN",
+		"CALSCALE:GREGORIAN",
+	)
+}
+
+func (e *icsTodoEncoder) Encode(todo *models.Todo) error {
+	status := "NEEDS-ACTION"
+	if todo.Completed {
+		status = "COMPLETED"
+	}
+
+	lines := []string{
+		"BEGIN:VTODO",
+		"UID:" + todo.ID.String(),
+		"DTSTAMP:" + e.stamp.UTC().Format(icsTimeFormat),
+		"CREATED:" + todo.CreatedAt.UTC().Format(icsTimeFormat),
+		"LAST-MODIFIED:" + todo.UpdatedAt.UTC().Format(icsTimeFormat),
+		"SUMMARY:" + escapeICSText(todo.Title),
+		"DUE:" + todo.Deadline.UTC().Format(icsTimeFormat),
+		"STATUS:" + status,
+		"END:VTODO",
+	}
+
+	return e.writeLines(lines...)
+}
+
+func (e *icsTodoEncoder) End() error {
+	return e.writeLines("END:VCALENDAR")
+}
+
+// writeLines writes folded content lines terminated by CRLF
+func (e *icsTodoEncoder) writeLines(lines ...string) error {
+	for _, line := range lines {
+		if _, err := io.WriteString(e.w, foldICSLine(line)+"\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// escapeICSText escapes a TEXT property value
+func escapeICSText(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+	return replacer.Replace(value)
+}
+
+// foldICSLine splits a content line longer than 75 octets into continuation lines starting with a space,
+// without splitting UTF-8 characters
+func foldICSLine(line string) string {
+	if len(line) <= icsMaxLineLength {
+		return line
+	}
+
+	var b strings.Builder
+	limit := icsMaxLineLength
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			// Continuation lines lose one octet to the leading space
+			limit = icsMaxLineLength - 1
+			width = 0
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
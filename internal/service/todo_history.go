@@ -0,0 +1,82 @@
+package service
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-backend-todo/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// newTodoHistoryEvent creates a history event of a todo without a field change
+func newTodoHistoryEvent(todoID, actorID uuid.UUID, action models.TodoHistoryAction) *models.TodoHistoryEvent {
+	return &models.TodoHistoryEvent{
+		TodoID:  todoID,
+		ActorID: &actorID,
+		Action:  action,
+	}
+}
+
+// todoFieldChanges returns one history event per field that differs between before and after
+func todoFieldChanges(before, after *models.Todo, actorID uuid.UUID) []*models.TodoHistoryEvent {
+	var events []*models.TodoHistoryEvent
+
+	add := func(field string, oldValue, newValue *string) {
+		if stringPtrEqual(oldValue, newValue) {
+			return
+		}
+		event := newTodoHistoryEvent(after.ID, actorID, models.TodoHistoryUpdated)
+		event.Field, event.OldValue, event.NewValue = &field, oldValue, newValue
+		events = append(events, event)
+	}
+
+	add(models.TodoFieldTitle, &before.Title, &after.Title)
+	add(models.TodoFieldDeadline, formatHistoryTime(before.Deadline), formatHistoryTime(after.Deadline))
+	add(models.TodoFieldCompleted, formatHistoryBool(before.Completed), formatHistoryBool(after.Completed))
+	add(models.TodoFieldAssignee, formatHistoryUUID(before.AssigneeID), formatHistoryUUID(after.AssigneeID))
+
+	return events
+}
+
+// formatReminderOffsets formats the offsets of reminders as a sorted, comma separated list of minutes
+func formatReminderOffsets(reminders []*models.TodoReminder) string {
+	offsets := make([]int, len(reminders))
+	for i, reminder := range reminders {
+		offsets[i] = reminder.OffsetMinutes
+	}
+	sort.Ints(offsets)
+
+	parts := make([]string, len(offsets))
+	for i, offset := range offsets {
+		parts[i] = strconv.Itoa(offset)
+	}
+	return strings.Join(parts, ",")
+}
+
+func formatHistoryTime(t time.Time) *string {
+	value := t.UTC().Format(time.RFC3339)
+	return &value
+}
+
+func formatHistoryBool(b bool) *string {
+	value := strconv.FormatBool(b)
+	return &value
+}
+
+func formatHistoryUUID(id *uuid.UUID) *string {
+	if id == nil {
+		return nil
+	}
+	value := id.String()
+	return &value
+}
+
+func stringPtrEqual(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
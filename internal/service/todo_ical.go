@@ -0,0 +1,178 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// icsTodo holds the properties of a VTODO component that map to a todo
+type icsTodo struct {
+	UID       string
+	Summary   string
+	Due       *time.Time
+	Completed bool
+}
+
+// icsProperty is one unfolded content line of an iCalendar object
+type icsProperty struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// parseICSTodo reads the first VTODO component of an iCalendar object.
+// Floating times and dates are read in loc; dates are due at the end of the day, like in imports.
+func parseICSTodo(data []byte, loc *time.Location) (*icsTodo, error) {
+	var todo *icsTodo
+	depth := 0 // nesting below VTODO, such as VALARM components
+
+	for _, line := range unfoldICSLines(data) {
+		prop, err := parseICSProperty(line)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case prop.Name == "BEGIN" && strings.EqualFold(prop.Value, "VTODO") && todo == nil:
+			todo = &icsTodo{}
+			continue
+		case todo == nil:
+			continue
+		case prop.Name == "BEGIN":
+			depth++
+			continue
+		case prop.Name == "END" && depth > 0:
+			depth--
+			continue
+		case prop.Name == "END" && strings.EqualFold(prop.Value, "VTODO"):
+			return todo, nil
+		case depth > 0:
+			continue
+		}
+
+		switch prop.Name {
+		case "UID":
+			todo.UID = prop.Value
+		case "SUMMARY":
+			todo.Summary = unescapeICSText(prop.Value)
+		case "DUE":
+			due, err := parseICSTime(prop, loc)
+			if err != nil {
+				return nil, err
+			}
+			todo.Due = &due
+		case "STATUS":
+			todo.Completed = strings.EqualFold(prop.Value, "COMPLETED")
+		case "COMPLETED":
+			todo.Completed = true
+		}
+	}
+
+	if todo == nil {
+		return nil, fmt.Errorf("no VTODO component")
+	}
+	return nil, fmt.Errorf("VTODO component is not terminated")
+}
+
+// unfoldICSLines splits an iCalendar object into content lines, joining folded continuation lines
+func unfoldICSLines(data []byte) []string {
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	data = bytes.ReplaceAll(data, []byte("\n "), nil)
+	data = bytes.ReplaceAll(data, []byte("\n\t"), nil)
+
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// parseICSProperty splits a content line into its upper-cased name, parameters and value
+func parseICSProperty(line string) (icsProperty, error) {
+	// The value starts at the first colon outside of a quoted parameter value
+	quoted := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return icsProperty{}, fmt.Errorf("invalid content line %q", line)
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	prop := icsProperty{
+		Name:   strings.ToUpper(parts[0]),
+		Params: make(map[string]string, len(parts)-1),
+		Value:  line[colon+1:],
+	}
+	for _, param := range parts[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		prop.Params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	return prop, nil
+}
+
+// parseICSTime parses a DATE or DATE-TIME value in UTC, in the zone of its TZID parameter or floating in loc
+func parseICSTime(prop icsProperty, loc *time.Location) (time.Time, error) {
+	if tzid := prop.Params["TZID"]; tzid != "" {
+		if tz, err := time.LoadLocation(tzid); err == nil {
+			loc = tz
+		}
+	}
+
+	value := prop.Value
+	if strings.EqualFold(prop.Params["VALUE"], "DATE") || len(value) == len("20060102") {
+		day, err := time.ParseInLocation("20060102", value, loc)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid %s date %q", prop.Name, value)
+		}
+		return day.AddDate(0, 0, 1).Add(-time.Minute), nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(icsTimeFormat, value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid %s time %q", prop.Name, value)
+		}
+		return t, nil
+	}
+
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s time %q", prop.Name, value)
+	}
+	return t, nil
+}
+
+// unescapeICSText reverses escapeICSText
+func unescapeICSText(value string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range value {
+		if escaped {
+			switch r {
+			case 'n', 'N':
+				b.WriteRune('\n')
+			default:
+				b.WriteRune(r)
+			}
+			escaped = false
+			continue
+		}
+		if r == '\\' {
+			escaped = true
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,344 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go-backend-todo/internal/models"
+)
+
+// importRow is a todo read from an import file, with its values still unvalidated
+type importRow struct {
+	row       int
+	title     string
+	deadline  string
+	completed string
+	// fuzzyDeadline is set when the deadline may be written in words, such as "every monday" in Todoist,
+	// in which case a deadline that cannot be read is ignored with a warning
+	fuzzyDeadline bool
+}
+
+// importTimeLayouts are the deadline formats accepted by imports, tried in order.
+// Layouts without a UTC offset are read in the user's time zone.
+var importTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+}
+
+// importDateLayout is the format of deadlines given as a day, which are due at the end of that day
+const importDateLayout = "2006-01-02"
+
+// maxImportTitleLength matches the title length allowed when creating a todo
+const maxImportTitleLength = 255
+
+// todoistTypeTask is the TYPE of the task rows of a Todoist CSV export, other rows are sections and notes
+const todoistTypeTask = "task"
+
+// Columns of a Todoist CSV export
+const (
+	todoistColumnType    = "TYPE"
+	todoistColumnContent = "CONTENT"
+	todoistColumnDate    = "DATE"
+)
+
+// parseImportRows reads the rows of an import file in the given format
+func parseImportRows(r io.Reader, opts models.ImportOptions) ([]importRow, error) {
+	switch opts.Format {
+	case models.ImportFormatJSON:
+		return parseJSONImport(r)
+	case models.ImportFormatCSV:
+		mapping := opts.Mapping
+		if len(mapping) == 0 {
+			mapping = map[string]string{
+				models.ImportFieldTitle:     models.ImportFieldTitle,
+				models.ImportFieldDeadline:  models.ImportFieldDeadline,
+				models.ImportFieldCompleted: models.ImportFieldCompleted,
+			}
+		}
+		return parseCSVImport(r, mapping, nil)
+	case models.ImportFormatTodoist:
+		mapping := map[string]string{
+			models.ImportFieldTitle:    todoistColumnContent,
+			models.ImportFieldDeadline: todoistColumnDate,
+		}
+		rows, err := parseCSVImport(r, mapping, func(header map[string]int, record []string) bool {
+			i, ok := header[strings.ToLower(todoistColumnType)]
+			return !ok || (i < len(record) && strings.EqualFold(strings.TrimSpace(record[i]), todoistTypeTask))
+		})
+		for i := range rows {
+			rows[i].fuzzyDeadline = true
+		}
+		return rows, err
+	case models.ImportFormatMSGraph:
+		return parseMSGraphImport(r)
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q", ErrInvalidImport, opts.Format)
+	}
+}
+
+// parseJSONImport reads an array of todos in the format of our JSON export
+func parseJSONImport(r io.Reader) ([]importRow, error) {
+	var items []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return nil, fmt.Errorf("%w: expected a JSON array of todos: %v", ErrInvalidImport, err)
+	}
+
+	rows := make([]importRow, len(items))
+	for i, item := range items {
+		rows[i].row = i + 1
+
+		var todo struct {
+			Title     string          `json:"title"`
+			Deadline  string          `json:"deadline"`
+			Completed json.RawMessage `json:"completed"`
+		}
+		if err := json.Unmarshal(item, &todo); err != nil {
+			// Leave the row empty so that validation reports it
+			continue
+		}
+		rows[i].title = todo.Title
+		rows[i].deadline = todo.Deadline
+		rows[i].completed = strings.Trim(string(todo.Completed), `"`)
+	}
+
+	return rows, nil
+}
+
+// parseMSGraphImport reads Microsoft To Do tasks in the JSON of the Microsoft Graph API, either as an array
+// or as a list response
+func parseMSGraphImport(r io.Reader) ([]importRow, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	type msDateTime struct {
+		DateTime string `json:"dateTime"`
+		TimeZone string `json:"timeZone"`
+	}
+	type msTask struct {
+		Title       string      `json:"title"`
+		Status      string      `json:"status"`
+		DueDateTime *msDateTime `json:"dueDateTime"`
+	}
+
+	var tasks []json.RawMessage
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("{")) {
+		var list struct {
+			Value []json.RawMessage `json:"value"`
+		}
+		if err := json.Unmarshal(data, &list); err != nil {
+			return nil, fmt.Errorf("%w: expected Microsoft To Do tasks: %v", ErrInvalidImport, err)
+		}
+		tasks = list.Value
+	} else if err := json.Unmarshal(data, &tasks); err != nil {
+		return nil, fmt.Errorf("%w: expected Microsoft To Do tasks: %v", ErrInvalidImport, err)
+	}
+
+	rows := make([]importRow, len(tasks))
+	for i, item := range tasks {
+		rows[i].row = i + 1
+
+		var task msTask
+		if err := json.Unmarshal(item, &task); err != nil {
+			continue
+		}
+		rows[i].title = task.Title
+		rows[i].completed = strconv.FormatBool(strings.EqualFold(task.Status, "completed"))
+		if task.DueDateTime != nil {
+			rows[i].deadline = msGraphDeadline(task.DueDateTime.DateTime, task.DueDateTime.TimeZone)
+		}
+	}
+
+	return rows, nil
+}
+
+// msGraphDeadline resolves a Microsoft Graph date-time in its time zone, which is usually UTC.
+// Zones Go does not know, such as Windows zone names, are left to be read in the user's time zone.
+func msGraphDeadline(dateTime, timeZone string) string {
+	loc, err := time.LoadLocation(timeZone)
+	if timeZone == "" || err != nil {
+		return dateTime
+	}
+
+	t, err := time.ParseInLocation("2006-01-02T15:04:05", dateTime, loc)
+	if err != nil {
+		return dateTime
+	}
+	return t.Format(time.RFC3339)
+}
+
+// parseCSVImport reads a CSV file with a header row, taking each todo field from the column it is mapped to.
+// Records for which include returns false are skipped.
+func parseCSVImport(r io.Reader, mapping map[string]string, include func(header map[string]int, record []string) bool) ([]importRow, error) {
+	reader := csv.NewReader(skipBOM(r))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	headerRecord, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: the file is empty", ErrInvalidImport)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+
+	header := make(map[string]int, len(headerRecord))
+	for i, name := range headerRecord {
+		header[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	columns := make(map[string]int, len(mapping))
+	for field, column := range mapping {
+		switch field {
+		case models.ImportFieldTitle, models.ImportFieldDeadline, models.ImportFieldCompleted:
+		default:
+			return nil, fmt.Errorf("%w: unknown field %q in column mapping", ErrInvalidImport, field)
+		}
+		i, ok := header[strings.ToLower(strings.TrimSpace(column))]
+		if !ok {
+			if field == models.ImportFieldCompleted {
+				continue
+			}
+			return nil, fmt.Errorf("%w: column %q mapped to %s not found", ErrInvalidImport, column, field)
+		}
+		columns[field] = i
+	}
+	if _, ok := columns[models.ImportFieldTitle]; !ok {
+		return nil, fmt.Errorf("%w: no column is mapped to title", ErrInvalidImport)
+	}
+
+	value := func(record []string, field string) string {
+		i, ok := columns[field]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return record[i]
+	}
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidImport, parseErr.StartLine, parseErr.Err)
+			}
+			return nil, err
+		}
+
+		if include != nil && !include(header, record) {
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, importRow{
+			row:       line,
+			title:     value(record, models.ImportFieldTitle),
+			deadline:  value(record, models.ImportFieldDeadline),
+			completed: value(record, models.ImportFieldCompleted),
+		})
+	}
+
+	return rows, nil
+}
+
+// validateImportRow converts a row into a todo, collecting every problem with it and the values it ignored
+func validateImportRow(row importRow, loc *time.Location, defaultDeadline *time.Time) (*models.Todo, []models.ImportRowError, []models.ImportRowError) {
+	var errs, warnings []models.ImportRowError
+	rowError := func(field, message string) {
+		errs = append(errs, models.ImportRowError{Row: row.row, Field: field, Message: message})
+	}
+	rowWarning := func(field, message string) {
+		warnings = append(warnings, models.ImportRowError{Row: row.row, Field: field, Message: message})
+	}
+
+	todo := &models.Todo{Title: strings.TrimSpace(row.title)}
+	if todo.Title == "" {
+		rowError(models.ImportFieldTitle, "title is required")
+	} else if utf8.RuneCountInString(todo.Title) > maxImportTitleLength {
+		rowError(models.ImportFieldTitle, fmt.Sprintf("title is longer than %d characters", maxImportTitleLength))
+	}
+
+	deadline := strings.TrimSpace(row.deadline)
+	var t time.Time
+	var err error
+	if deadline != "" {
+		t, err = parseImportTime(deadline, loc)
+	}
+	switch {
+	case deadline != "" && err == nil:
+		todo.Deadline = t
+	case deadline != "" && !row.fuzzyDeadline:
+		rowError(models.ImportFieldDeadline, fmt.Sprintf("unrecognized deadline %q", deadline))
+	case deadline != "" && defaultDeadline != nil:
+		rowWarning(models.ImportFieldDeadline, fmt.Sprintf("unrecognized date %q ignored, the default deadline is used", deadline))
+		todo.Deadline = *defaultDeadline
+	case deadline != "":
+		rowError(models.ImportFieldDeadline, fmt.Sprintf("unrecognized date %q, set a default deadline to import the todo without it", deadline))
+	case defaultDeadline != nil:
+		todo.Deadline = *defaultDeadline
+	default:
+		rowError(models.ImportFieldDeadline, "deadline is required")
+	}
+
+	completed, err := parseImportBool(row.completed)
+	if err != nil {
+		rowError(models.ImportFieldCompleted, fmt.Sprintf("unrecognized completed value %q", row.completed))
+	}
+	todo.Completed = completed
+
+	return todo, errs, warnings
+}
+
+// parseImportTime parses a deadline in one of importTimeLayouts, or a day that ends in loc
+func parseImportTime(value string, loc *time.Location) (time.Time, error) {
+	for _, layout := range importTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	day, err := time.ParseInLocation(importDateLayout, value, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return day.AddDate(0, 0, 1).Add(-time.Minute), nil
+}
+
+// parseImportBool parses a completed value, treating an empty value as not completed
+func parseImportBool(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "false", "0", "no", "n", "null":
+		return false, nil
+	case "true", "1", "yes", "y", "x", "done", "completed":
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid boolean %q", value)
+	}
+}
+
+// skipBOM drops the UTF-8 byte order mark spreadsheet applications put at the start of CSV files
+func skipBOM(r io.Reader) io.Reader {
+	br := bufio.NewReader(r)
+	if bom, err := br.Peek(3); err == nil && bytes.Equal(bom, []byte{0xEF, 0xBB, 0xBF}) {
+		br.Discard(3)
+	}
+	return br
+}
@@ -2,36 +2,83 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log"
+	"time"
 
+	"go-backend-todo/internal/config"
+	"go-backend-todo/internal/db"
 	"go-backend-todo/internal/models"
+	history_repository "go-backend-todo/internal/repository/history"
 	todo_repository "go-backend-todo/internal/repository/todo"
+	undo_repository "go-backend-todo/internal/repository/undo"
 
 	"github.com/google/uuid"
 )
 
+var (
+	// ErrTodoNotFound is returned when a todo does not exist or the user cannot access it
+	ErrTodoNotFound = errors.New("todo not found or access denied")
+	// ErrInvalidAssignee is returned when a todo is assigned to a user who cannot edit it
+	ErrInvalidAssignee = errors.New("assignee must be able to edit the todo")
+	// ErrInvalidImport is returned when an import file cannot be read in its format
+	ErrInvalidImport = errors.New("invalid import file")
+	// ErrUndoTokenInvalid is returned when an undo token does not exist, has expired or was already used
+	ErrUndoTokenInvalid = errors.New("undo token is invalid or expired")
+)
+
 // TodoService interface defines business logic for todo
 type TodoService interface {
 	CreateTodo(ctx context.Context, req models.CreateTodoRequest, userID uuid.UUID) (*models.Todo, error)
 	GetTodoByID(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*models.Todo, error)
 	UpdateTodo(ctx context.Context, id uuid.UUID, req models.UpdateTodoRequest, userID uuid.UUID) (*models.Todo, error)
-	DeleteTodo(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
-	GetTodosWithPagination(ctx context.Context, userID uuid.UUID, limit, offset int, completed *bool) ([]*models.Todo, int64, error)
+	DeleteTodo(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*models.UndoToken, error)
+	GetTodosWithPagination(ctx context.Context, userID uuid.UUID, limit, offset int, listID, assigneeID *uuid.UUID, completed *bool, sort models.TodoSortOrder) ([]*models.Todo, int64, error)
 	ToggleTodoStatus(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*models.Todo, error)
 	GetTodoStats(ctx context.Context, userID uuid.UUID) (*models.TodoStatsResponse, error)
-	MarkTodosAsCompleted(ctx context.Context, ids []uuid.UUID, userID uuid.UUID) error
-	DeleteCompletedTodos(ctx context.Context, userID uuid.UUID) error
+	MarkTodosAsCompleted(ctx context.Context, ids []uuid.UUID, userID uuid.UUID) (*models.UndoToken, error)
+	DeleteCompletedTodos(ctx context.Context, userID uuid.UUID) (*models.UndoToken, error)
+	Undo(ctx context.Context, token uuid.UUID, userID uuid.UUID) (*models.UndoResult, error)
+	GetTodoReminders(ctx context.Context, id uuid.UUID, userID uuid.UUID) ([]*models.TodoReminder, error)
+	UpdateTodoReminders(ctx context.Context, id uuid.UUID, offsets []int, userID uuid.UUID) ([]*models.TodoReminder, error)
+	AssignTodo(ctx context.Context, id uuid.UUID, assigneeID *uuid.UUID, userID uuid.UUID) (*models.Todo, error)
+	GetTodoHistory(ctx context.Context, id uuid.UUID, userID uuid.UUID, limit, offset int) ([]*models.TodoHistoryEvent, int64, error)
+	ExportTodos(ctx context.Context, userID uuid.UUID, format models.ExportFormat, w io.Writer) error
+	ImportTodos(ctx context.Context, userID uuid.UUID, r io.Reader, opts models.ImportOptions) (*models.ImportResult, error)
+	GetTrash(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.Todo, int64, error)
+	RestoreTodo(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*models.Todo, error)
+	PurgeTrash(ctx context.Context, retention time.Duration) (int64, error)
 }
 
 // todoService implementation of TodoService interface
 type todoService struct {
-	todoRepo todo_repository.TodoRepository
+	todoRepo            todo_repository.TodoRepository
+	historyRepo         history_repository.HistoryRepository
+	undoRepo            undo_repository.UndoRepository
+	listService         ListService
+	reminderService     ReminderService
+	preferenceService   PreferenceService
+	notificationService NotificationService
+	publisher           EventPublisher
+	txManager           db.TxManager
+	config              *config.Config
 }
 
 // NewTodoService creates a new instance of todo service
-func NewTodoService(todoRepo todo_repository.TodoRepository) TodoService {
+func NewTodoService(todoRepo todo_repository.TodoRepository, historyRepo history_repository.HistoryRepository, undoRepo undo_repository.UndoRepository, listService ListService, reminderService ReminderService, preferenceService PreferenceService, notificationService NotificationService, publisher EventPublisher, txManager db.TxManager, cfg *config.Config) TodoService {
 	return &todoService{
-		todoRepo: todoRepo,
+		todoRepo:            todoRepo,
+		historyRepo:         historyRepo,
+		undoRepo:            undoRepo,
+		listService:         listService,
+		reminderService:     reminderService,
+		preferenceService:   preferenceService,
+		notificationService: notificationService,
+		publisher:           publisher,
+		txManager:           txManager,
+		config:              cfg,
 	}
 }
 
@@ -42,43 +89,102 @@ func (s *todoService) CreateTodo(ctx context.Context, req models.CreateTodoReque
 		return nil, fmt.Errorf("title is required")
 	}
 
+	// Adding to a shared list requires edit rights on it
+	if req.ListID != nil {
+		if _, err := s.listService.Authorize(ctx, *req.ListID, userID, models.ListRoleEditor); err != nil {
+			return nil, err
+		}
+	}
+
 	todo := &models.Todo{
-		Title:     req.Title,
-		Deadline:  req.Deadline,
-		Completed: false,
-		UserID:    userID,
+		Title:      req.Title,
+		Deadline:   req.Deadline,
+		Completed:  false,
+		UserID:     userID,
+		ListID:     req.ListID,
+		AssigneeID: req.AssigneeID,
+	}
+
+	if req.ID != nil {
+		todo.ID = *req.ID
 	}
 
-	err := s.todoRepo.Create(ctx, todo)
+	if err := s.validateAssignee(ctx, todo, req.AssigneeID); err != nil {
+		return nil, err
+	}
+
+	err := s.txManager.WithinTx(ctx, func(txCtx context.Context) error {
+		if err := s.todoRepo.Create(txCtx, todo); err != nil {
+			return fmt.Errorf("failed to create todo: %w", err)
+		}
+		return s.recordHistory(txCtx, newTodoHistoryEvent(todo.ID, userID, models.TodoHistoryCreated))
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create todo: %w", err)
+		return nil, err
+	}
+
+	// Don't fail creation if reminders can't be scheduled - the todo is already stored
+	if _, err := s.reminderService.ScheduleReminders(ctx, todo, req.ReminderOffsets); err != nil {
+		log.Printf("Failed to schedule reminders for todo %s: %v", todo.ID, err)
 	}
 
+	s.notifyAssignee(ctx, todo, userID)
+
+	s.publishEvent(ctx, models.TodoCreatedEvent, todo)
+
 	return todo, nil
 }
 
-// GetTodoByID retrieves a todo by ID and checks ownership
+// GetTodoByID retrieves a todo by ID and checks that the user can view it
 func (s *todoService) GetTodoByID(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*models.Todo, error) {
+	return s.authorizeTodo(ctx, id, userID, models.ListRoleViewer)
+}
+
+// authorizeTodo retrieves a todo and checks that the user has at least the required role on it.
+// Personal todos are only accessible to their user; todos of a shared list follow the user's role in the list.
+func (s *todoService) authorizeTodo(ctx context.Context, id uuid.UUID, userID uuid.UUID, required models.ListRole) (*models.Todo, error) {
 	todo, err := s.todoRepo.GetByID(ctx, id)
+	if errors.Is(err, todo_repository.ErrTodoNotFound) {
+		return nil, ErrTodoNotFound
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get todo: %w", err)
 	}
 
-	// Check ownership
-	if todo.UserID != userID {
-		return nil, fmt.Errorf("todo not found or access denied")
+	if err := s.checkTodoAccess(ctx, todo, userID, required); err != nil {
+		return nil, err
 	}
 
 	return todo, nil
 }
 
+// checkTodoAccess checks that the user has at least the required role on a todo
+func (s *todoService) checkTodoAccess(ctx context.Context, todo *models.Todo, userID uuid.UUID, required models.ListRole) error {
+	if todo.ListID == nil {
+		if todo.UserID != userID {
+			return ErrTodoNotFound
+		}
+		return nil
+	}
+
+	if _, err := s.listService.Authorize(ctx, *todo.ListID, userID, required); err != nil {
+		if errors.Is(err, ErrListNotFound) {
+			return ErrTodoNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
 // UpdateTodo updates a todo
 func (s *todoService) UpdateTodo(ctx context.Context, id uuid.UUID, req models.UpdateTodoRequest, userID uuid.UUID) (*models.Todo, error) {
-	// Retrieve the current todo and check ownership
-	todo, err := s.GetTodoByID(ctx, id, userID)
+	// Retrieve the current todo and check edit rights
+	todo, err := s.authorizeTodo(ctx, id, userID, models.ListRoleEditor)
 	if err != nil {
 		return nil, err
 	}
+	before := *todo
 
 	// Update fields if provided in the request
 	if req.Title != nil {
@@ -88,36 +194,76 @@ func (s *todoService) UpdateTodo(ctx context.Context, id uuid.UUID, req models.U
 		todo.Title = *req.Title
 	}
 
+	deadlineChanged := false
 	if req.Deadline != nil {
+		deadlineChanged = !todo.Deadline.Equal(*req.Deadline)
 		todo.Deadline = *req.Deadline
 	}
 
+	wasCompleted := todo.Completed
 	if req.Completed != nil {
 		todo.Completed = *req.Completed
 	}
 
-	err = s.todoRepo.Update(ctx, todo)
+	// The todo, its reminders and the history of the change are stored together or not at all
+	err = s.txManager.WithinTx(ctx, func(txCtx context.Context) error {
+		if err := s.todoRepo.Update(txCtx, todo); err != nil {
+			return fmt.Errorf("failed to update todo: %w", err)
+		}
+
+		changes := todoFieldChanges(&before, todo, userID)
+
+		// Keep reminders in line with the new deadline or the requested offsets
+		if req.ReminderOffsets != nil {
+			_, change, err := s.replaceReminders(txCtx, todo, req.ReminderOffsets, userID)
+			if err != nil {
+				return err
+			}
+			if change != nil {
+				changes = append(changes, change)
+			}
+		} else if deadlineChanged {
+			if err := s.reminderService.RescheduleReminders(txCtx, todo); err != nil {
+				return err
+			}
+		}
+
+		return s.recordHistory(txCtx, changes...)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to update todo: %w", err)
+		return nil, err
+	}
+
+	if todo.Completed && !wasCompleted {
+		s.publishEvent(ctx, models.TodoCompletedEvent, todo)
+	} else {
+		s.publishEvent(ctx, models.TodoUpdatedEvent, todo)
 	}
 
 	return todo, nil
 }
 
-// DeleteTodo deletes a todo
-func (s *todoService) DeleteTodo(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
-	// Check ownership before deleting
-	_, err := s.GetTodoByID(ctx, id, userID)
+// DeleteTodo moves a todo to the trash and returns a token to undo the deletion
+func (s *todoService) DeleteTodo(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*models.UndoToken, error) {
+	// Check edit rights before deleting
+	todo, err := s.authorizeTodo(ctx, id, userID, models.ListRoleEditor)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = s.todoRepo.Delete(ctx, id)
+	err = s.txManager.WithinTx(ctx, func(txCtx context.Context) error {
+		if err := s.todoRepo.Delete(txCtx, id); err != nil {
+			return fmt.Errorf("failed to delete todo: %w", err)
+		}
+		return s.recordHistory(txCtx, newTodoHistoryEvent(todo.ID, userID, models.TodoHistoryDeleted))
+	})
 	if err != nil {
-		return fmt.Errorf("failed to delete todo: %w", err)
+		return nil, err
 	}
 
-	return nil
+	s.publishEvent(ctx, models.TodoDeletedEvent, todo)
+
+	return s.createUndoToken(ctx, userID, models.UndoActionDelete, []uuid.UUID{todo.ID}), nil
 }
 
 // GetUserTodos retrieves todos for a user with filter
@@ -132,8 +278,9 @@ func (s *todoService) GetUserTodos(ctx context.Context, userID uuid.UUID, filter
 	return todos, nil
 }
 
-// GetTodosWithPagination retrieves todos with pagination
-func (s *todoService) GetTodosWithPagination(ctx context.Context, userID uuid.UUID, limit, offset int, completed *bool) ([]*models.Todo, int64, error) {
+// GetTodosWithPagination retrieves the todos visible to a user with pagination, optionally limited to one list
+// or one assignee, using the user's default sort order when sort is empty
+func (s *todoService) GetTodosWithPagination(ctx context.Context, userID uuid.UUID, limit, offset int, listID, assigneeID *uuid.UUID, completed *bool, sort models.TodoSortOrder) ([]*models.Todo, int64, error) {
 	// Set default limit if not provided
 	if limit <= 0 {
 		limit = 10
@@ -142,11 +289,28 @@ func (s *todoService) GetTodosWithPagination(ctx context.Context, userID uuid.UU
 		limit = 100 // Max 100 items per page
 	}
 
+	if listID != nil {
+		if _, err := s.listService.Authorize(ctx, *listID, userID, models.ListRoleViewer); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if sort == "" {
+		prefs, err := s.preferenceService.GetPreferences(ctx, userID)
+		if err != nil {
+			return nil, 0, err
+		}
+		sort = prefs.DefaultTodoSort
+	}
+
 	filter := models.TodoFilter{
-		UserID:    userID,
-		Completed: completed,
-		Limit:     limit,
-		Offset:    offset,
+		UserID:     userID,
+		ListID:     listID,
+		AssigneeID: assigneeID,
+		Completed:  completed,
+		Sort:       sort,
+		Limit:      limit,
+		Offset:     offset,
 	}
 
 	todos, err := s.todoRepo.GetByUserID(ctx, filter)
@@ -165,15 +329,10 @@ func (s *todoService) GetTodosWithPagination(ctx context.Context, userID uuid.UU
 
 // ToggleTodoStatus toggles the completion status of a todo
 func (s *todoService) ToggleTodoStatus(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*models.Todo, error) {
-	// Get current todo
-	todo, err := s.todoRepo.GetByID(ctx, id)
+	// Get current todo and check edit rights
+	todo, err := s.authorizeTodo(ctx, id, userID, models.ListRoleEditor)
 	if err != nil {
-		return nil, fmt.Errorf("todo not found: %w", err)
-	}
-
-	// Check ownership
-	if todo.UserID != userID {
-		return nil, fmt.Errorf("unauthorized access to todo")
+		return nil, err
 	}
 
 	// Toggle status
@@ -187,44 +346,580 @@ func (s *todoService) ToggleTodoStatus(ctx context.Context, id uuid.UUID, userID
 
 // GetTodoStats returns statistics about user's todos
 func (s *todoService) GetTodoStats(ctx context.Context, userID uuid.UUID) (*models.TodoStatsResponse, error) {
-	// TODO: Implement todo statistics
-	// This would typically involve counting todos by different criteria
-	stats := &models.TodoStatsResponse{
-		TotalTodos:     0,
-		CompletedTodos: 0,
-		PendingTodos:   0,
-		OverdueTodos:   0,
-		TodayTodos:     0,
-		ThisWeekTodos:  0,
+	prefs, err := s.preferenceService.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	window := newTodoStatsWindow(time.Now(), prefs.Location(), time.Weekday(prefs.WeekStart))
+
+	stats, err := s.todoRepo.GetStats(ctx, userID, window)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get todo stats: %w", err)
 	}
 
 	return stats, nil
 }
 
-// MarkTodosAsCompleted marks multiple todos as completed
-func (s *todoService) MarkTodosAsCompleted(ctx context.Context, ids []uuid.UUID, userID uuid.UUID) error {
-	// TODO: Check ownership of all todos (can be optimized with batch query)
-	for _, id := range ids {
-		_, err := s.GetTodoByID(ctx, id, userID)
+// MarkTodosAsCompleted marks multiple todos as completed and returns a token to reopen the todos it completed
+func (s *todoService) MarkTodosAsCompleted(ctx context.Context, ids []uuid.UUID, userID uuid.UUID) (*models.UndoToken, error) {
+	var completed []*models.Todo
+	var completedIDs []uuid.UUID
+	// Edit rights are checked in the transaction of the update, so a member removed in between cannot complete todos
+	err := s.txManager.WithinTx(ctx, func(txCtx context.Context) error {
+		// TODO: Check edit rights on all todos (can be optimized with batch query)
+		var changes []*models.TodoHistoryEvent
+		for _, id := range ids {
+			todo, err := s.authorizeTodo(txCtx, id, userID, models.ListRoleEditor)
+			if err != nil {
+				return fmt.Errorf("invalid todo ID %s: %w", id, err)
+			}
+			if todo.Completed {
+				continue
+			}
+			before := *todo
+			todo.Completed = true
+			completed = append(completed, todo)
+			completedIDs = append(completedIDs, todo.ID)
+			changes = append(changes, todoFieldChanges(&before, todo, userID)...)
+		}
+
+		if err := s.todoRepo.MarkAsCompleted(txCtx, ids); err != nil {
+			return fmt.Errorf("failed to mark todos as completed: %w", err)
+		}
+		return s.recordHistory(txCtx, changes...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, todo := range completed {
+		s.publishEvent(ctx, models.TodoCompletedEvent, todo)
+	}
+
+	return s.createUndoToken(ctx, userID, models.UndoActionComplete, completedIDs), nil
+}
+
+// DeleteCompletedTodos moves all completed personal todos for a user to the trash and returns a token to undo the deletion
+func (s *todoService) DeleteCompletedTodos(ctx context.Context, userID uuid.UUID) (*models.UndoToken, error) {
+	var todos []*models.Todo
+	err := s.txManager.WithinTx(ctx, func(txCtx context.Context) error {
+		var err error
+		todos, err = s.todoRepo.DeleteCompleted(txCtx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to delete completed todos: %w", err)
+		}
+
+		events := make([]*models.TodoHistoryEvent, len(todos))
+		for i, todo := range todos {
+			events[i] = newTodoHistoryEvent(todo.ID, userID, models.TodoHistoryDeleted)
+		}
+		return s.recordHistory(txCtx, events...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, len(todos))
+	for i, todo := range todos {
+		ids[i] = todo.ID
+	}
+
+	for _, todo := range todos {
+		s.publishEvent(ctx, models.TodoDeletedEvent, todo)
+	}
+
+	return s.createUndoToken(ctx, userID, models.UndoActionDelete, ids), nil
+}
+
+// Undo reverses a destructive action within its undo window. Deleted todos are restored and completed todos reopened.
+// Todos the user can no longer edit, or that are no longer in the state the action left them in, are skipped.
+func (s *todoService) Undo(ctx context.Context, token uuid.UUID, userID uuid.UUID) (*models.UndoResult, error) {
+	var result *models.UndoResult
+	// The token is only used up together with the changes it undoes
+	err := s.txManager.WithinTx(ctx, func(txCtx context.Context) error {
+		action, err := s.undoRepo.Consume(txCtx, token, userID)
+		if err != nil {
+			return fmt.Errorf("failed to get undo action: %w", err)
+		}
+		if action == nil {
+			return ErrUndoTokenInvalid
+		}
+
+		result = &models.UndoResult{Action: action.Action, Todos: []*models.Todo{}}
+		for _, id := range action.TodoIDs {
+			var todo *models.Todo
+			switch action.Action {
+			case models.UndoActionDelete:
+				todo, err = s.restoreTodo(txCtx, id, userID)
+			case models.UndoActionComplete:
+				todo, err = s.reopenTodo(txCtx, id, userID)
+			default:
+				return fmt.Errorf("unknown undo action %q", action.Action)
+			}
+			// Todos gone or no longer editable by the user are left as they are
+			if errors.Is(err, ErrTodoNotFound) || errors.Is(err, ErrListForbidden) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to undo %s of todo %s: %w", action.Action, id, err)
+			}
+			if todo != nil {
+				result.Todos = append(result.Todos, todo)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, todo := range result.Todos {
+		if result.Action == models.UndoActionDelete {
+			s.afterRestore(ctx, todo)
+		} else {
+			s.publishEvent(ctx, models.TodoUpdatedEvent, todo)
+		}
+	}
+
+	return result, nil
+}
+
+// reopenTodo marks a completed todo as not completed, returning nil when it was reopened in the meantime
+func (s *todoService) reopenTodo(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*models.Todo, error) {
+	todo, err := s.authorizeTodo(ctx, id, userID, models.ListRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if !todo.Completed {
+		return nil, nil
+	}
+
+	before := *todo
+	todo.Completed = false
+	err = s.txManager.WithinTx(ctx, func(txCtx context.Context) error {
+		if err := s.todoRepo.Update(txCtx, todo); err != nil {
+			return fmt.Errorf("failed to update todo: %w", err)
+		}
+		return s.recordHistory(txCtx, todoFieldChanges(&before, todo, userID)...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return todo, nil
+}
+
+// createUndoToken records the todos changed by a destructive action and returns the token to undo it.
+// The action itself has already succeeded, so a failure to record it is logged and no token is returned.
+func (s *todoService) createUndoToken(ctx context.Context, userID uuid.UUID, actionType models.UndoActionType, ids []uuid.UUID) *models.UndoToken {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	action := &models.UndoAction{
+		UserID:    userID,
+		Action:    actionType,
+		TodoIDs:   ids,
+		ExpiresAt: time.Now().Add(time.Duration(s.config.Undo.Window) * time.Second),
+	}
+	if err := s.undoRepo.Create(ctx, action); err != nil {
+		log.Printf("Failed to record undo action %s for user %s: %v", actionType, userID, err)
+		return nil
+	}
+
+	return &models.UndoToken{UndoToken: action.ID, ExpiresAt: action.ExpiresAt}
+}
+
+// GetTodoReminders retrieves the reminders of a todo after checking that the user can view it
+func (s *todoService) GetTodoReminders(ctx context.Context, id uuid.UUID, userID uuid.UUID) ([]*models.TodoReminder, error) {
+	if _, err := s.GetTodoByID(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	return s.reminderService.GetReminders(ctx, id)
+}
+
+// UpdateTodoReminders replaces the reminder offsets of a todo
+func (s *todoService) UpdateTodoReminders(ctx context.Context, id uuid.UUID, offsets []int, userID uuid.UUID) ([]*models.TodoReminder, error) {
+	todo, err := s.authorizeTodo(ctx, id, userID, models.ListRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	if offsets == nil {
+		offsets = []int{}
+	}
+
+	var reminders []*models.TodoReminder
+	err = s.txManager.WithinTx(ctx, func(txCtx context.Context) error {
+		scheduled, change, err := s.replaceReminders(txCtx, todo, offsets, userID)
 		if err != nil {
-			return fmt.Errorf("invalid todo ID %s: %w", id, err)
+			return err
 		}
+		reminders = scheduled
+		if change == nil {
+			return nil
+		}
+		return s.recordHistory(txCtx, change)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	err := s.todoRepo.MarkAsCompleted(ctx, ids)
+	return reminders, nil
+}
+
+// AssignTodo assigns a todo to a user who can edit it, or unassigns it when assigneeID is nil
+func (s *todoService) AssignTodo(ctx context.Context, id uuid.UUID, assigneeID *uuid.UUID, userID uuid.UUID) (*models.Todo, error) {
+	todo, err := s.authorizeTodo(ctx, id, userID, models.ListRoleEditor)
 	if err != nil {
-		return fmt.Errorf("failed to mark todos as completed: %w", err)
+		return nil, err
+	}
+
+	if err := s.validateAssignee(ctx, todo, assigneeID); err != nil {
+		return nil, err
+	}
+
+	before := *todo
+	todo.AssigneeID = assigneeID
+	changes := todoFieldChanges(&before, todo, userID)
+
+	err = s.txManager.WithinTx(ctx, func(txCtx context.Context) error {
+		if err := s.todoRepo.Update(txCtx, todo); err != nil {
+			return fmt.Errorf("failed to update todo: %w", err)
+		}
+		return s.recordHistory(txCtx, changes...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Reload to resolve the assignee's username
+	todo, err = s.todoRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get todo: %w", err)
+	}
+
+	if len(changes) > 0 {
+		s.notifyAssignee(ctx, todo, userID)
+		s.publishEvent(ctx, models.TodoUpdatedEvent, todo)
+	}
+
+	return todo, nil
+}
+
+// GetTodoHistory retrieves the history of a todo the user can view, newest first. The history of a todo
+// in the trash stays readable, since its deletion is part of it.
+func (s *todoService) GetTodoHistory(ctx context.Context, id uuid.UUID, userID uuid.UUID, limit, offset int) ([]*models.TodoHistoryEvent, int64, error) {
+	todo, err := s.todoRepo.GetByIDIncludingDeleted(ctx, id)
+	if errors.Is(err, todo_repository.ErrTodoNotFound) {
+		return nil, 0, ErrTodoNotFound
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get todo: %w", err)
+	}
+
+	if err := s.checkTodoAccess(ctx, todo, userID, models.ListRoleViewer); err != nil {
+		if errors.Is(err, ErrListForbidden) {
+			return nil, 0, ErrTodoNotFound
+		}
+		return nil, 0, err
+	}
+
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	events, err := s.historyRepo.GetByTodoID(ctx, id, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get todo history: %w", err)
+	}
+
+	total, err := s.historyRepo.CountByTodoID(ctx, id)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count todo history: %w", err)
+	}
+
+	return events, total, nil
+}
+
+// ExportTodos writes all todos visible to a user to w in the given format, one todo at a time
+func (s *todoService) ExportTodos(ctx context.Context, userID uuid.UUID, format models.ExportFormat, w io.Writer) error {
+	encoder, err := newTodoEncoder(format, w, s.config.App.Name)
+	if err != nil {
+		return err
+	}
+
+	if err := encoder.Begin(); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+
+	err = s.todoRepo.StreamByUserID(ctx, userID, encoder.Encode)
+	if err != nil {
+		return fmt.Errorf("failed to export todos: %w", err)
+	}
+
+	if err := encoder.End(); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
 	}
 
 	return nil
 }
 
-// DeleteCompletedTodos deletes all completed todos for a user
-func (s *todoService) DeleteCompletedTodos(ctx context.Context, userID uuid.UUID) error {
-	err := s.todoRepo.DeleteCompleted(ctx, userID)
+// ImportTodos validates every row of an import file as personal todos of the user and, unless it is a dry run
+// and no row has errors, inserts them all at once. Imported todos get no reminders and emit no events.
+func (s *todoService) ImportTodos(ctx context.Context, userID uuid.UUID, r io.Reader, opts models.ImportOptions) (*models.ImportResult, error) {
+	rows, err := parseImportRows(r, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > s.config.Import.MaxRows {
+		return nil, fmt.Errorf("%w: more than %d rows", ErrInvalidImport, s.config.Import.MaxRows)
+	}
+
+	prefs, err := s.preferenceService.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.ImportResult{
+		Format:    opts.Format,
+		DryRun:    opts.DryRun,
+		TotalRows: len(rows),
+		Errors:    []models.ImportRowError{},
+		Warnings:  []models.ImportRowError{},
+	}
+	todos := make([]*models.Todo, 0, len(rows))
+	for _, row := range rows {
+		todo, errs, warnings := validateImportRow(row, prefs.Location(), opts.DefaultDeadline)
+		result.Warnings = append(result.Warnings, warnings...)
+		if len(errs) > 0 {
+			result.Errors = append(result.Errors, errs...)
+			continue
+		}
+		todo.UserID = userID
+		todos = append(todos, todo)
+	}
+	result.ValidRows = len(todos)
+
+	if opts.DryRun || len(result.Errors) > 0 || len(todos) == 0 {
+		return result, nil
+	}
+
+	err = s.txManager.WithinTx(ctx, func(txCtx context.Context) error {
+		imported, err := s.todoRepo.CreateMany(txCtx, todos)
+		if err != nil {
+			return fmt.Errorf("failed to import todos: %w", err)
+		}
+		result.Imported = int(imported)
+
+		events := make([]*models.TodoHistoryEvent, len(todos))
+		for i, todo := range todos {
+			events[i] = newTodoHistoryEvent(todo.ID, userID, models.TodoHistoryCreated)
+		}
+		return s.recordHistory(txCtx, events...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetTrash retrieves the deleted todos visible to a user that can still be restored, most recently deleted first
+func (s *todoService) GetTrash(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.Todo, int64, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	filter := models.TodoFilter{
+		UserID: userID,
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	todos, err := s.todoRepo.GetDeleted(ctx, filter)
 	if err != nil {
-		return fmt.Errorf("failed to delete completed todos: %w", err)
+		return nil, 0, fmt.Errorf("failed to get trash: %w", err)
+	}
+
+	total, err := s.todoRepo.CountDeleted(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count trash: %w", err)
+	}
+
+	return todos, total, nil
+}
+
+// RestoreTodo moves a todo out of the trash, rescheduling its reminders that are still ahead
+func (s *todoService) RestoreTodo(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*models.Todo, error) {
+	todo, err := s.restoreTodo(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.afterRestore(ctx, todo)
+
+	return todo, nil
+}
+
+// restoreTodo moves a todo the user can edit out of the trash and records it in its history
+func (s *todoService) restoreTodo(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*models.Todo, error) {
+	todo, err := s.todoRepo.GetDeletedByID(ctx, id)
+	if errors.Is(err, todo_repository.ErrTodoNotFound) {
+		return nil, ErrTodoNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted todo: %w", err)
+	}
+
+	if err := s.checkTodoAccess(ctx, todo, userID, models.ListRoleEditor); err != nil {
+		return nil, err
+	}
+
+	err = s.txManager.WithinTx(ctx, func(txCtx context.Context) error {
+		var err error
+		todo, err = s.todoRepo.Restore(txCtx, id)
+		if errors.Is(err, todo_repository.ErrTodoNotFound) {
+			return ErrTodoNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to restore todo: %w", err)
+		}
+		return s.recordHistory(txCtx, newTodoHistoryEvent(todo.ID, userID, models.TodoHistoryRestored))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return todo, nil
+}
+
+// afterRestore reschedules the reminders of a restored todo and publishes its restoration once it is committed
+func (s *todoService) afterRestore(ctx context.Context, todo *models.Todo) {
+	if err := s.reminderService.RescheduleReminders(ctx, todo); err != nil {
+		log.Printf("Failed to reschedule reminders of restored todo %s: %v", todo.ID, err)
+	}
+
+	s.publishEvent(ctx, models.TodoRestoredEvent, todo)
+}
+
+// PurgeTrash permanently deletes the todos that have been in the trash for longer than retention
+func (s *todoService) PurgeTrash(ctx context.Context, retention time.Duration) (int64, error) {
+	purged, err := s.todoRepo.PurgeDeleted(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge trash: %w", err)
+	}
+
+	if purged > 0 {
+		log.Printf("Purged %d todos from the trash", purged)
+	}
+
+	return purged, nil
+}
+
+// replaceReminders schedules new reminder offsets for a todo and returns the scheduled reminders
+// with the history event of the change, or a nil event if the offsets are unchanged
+func (s *todoService) replaceReminders(ctx context.Context, todo *models.Todo, offsets []int, actorID uuid.UUID) ([]*models.TodoReminder, *models.TodoHistoryEvent, error) {
+	previous, err := s.reminderService.GetReminders(ctx, todo.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	scheduled, err := s.reminderService.ScheduleReminders(ctx, todo, offsets)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	oldValue, newValue := formatReminderOffsets(previous), formatReminderOffsets(scheduled)
+	if oldValue == newValue {
+		return scheduled, nil, nil
+	}
+
+	event := newTodoHistoryEvent(todo.ID, actorID, models.TodoHistoryUpdated)
+	field := models.TodoFieldReminders
+	event.Field, event.OldValue, event.NewValue = &field, &oldValue, &newValue
+	return scheduled, event, nil
+}
+
+// validateAssignee checks that the assignee can work on the todo: personal todos can only be
+// assigned to their user, todos of a shared list to members allowed to edit it
+func (s *todoService) validateAssignee(ctx context.Context, todo *models.Todo, assigneeID *uuid.UUID) error {
+	if assigneeID == nil {
+		return nil
+	}
+
+	if todo.ListID == nil {
+		if *assigneeID != todo.UserID {
+			return ErrInvalidAssignee
+		}
+		return nil
+	}
+
+	if _, err := s.listService.Authorize(ctx, *todo.ListID, *assigneeID, models.ListRoleEditor); err != nil {
+		if errors.Is(err, ErrListNotFound) || errors.Is(err, ErrListForbidden) {
+			return ErrInvalidAssignee
+		}
+		return err
 	}
 
 	return nil
 }
+
+// notifyAssignee notifies the assignee of a todo, logging failures instead of failing the change
+func (s *todoService) notifyAssignee(ctx context.Context, todo *models.Todo, assignerID uuid.UUID) {
+	if err := s.notificationService.NotifyTodoAssigned(ctx, todo, assignerID); err != nil {
+		log.Printf("Failed to notify assignee of todo %s: %v", todo.ID, err)
+	}
+}
+
+// recordHistory appends events to the history of their todos. Callers pass the ctx of the transaction
+// of the change, so that a change is never stored without its history.
+func (s *todoService) recordHistory(ctx context.Context, events ...*models.TodoHistoryEvent) error {
+	if err := s.historyRepo.Create(ctx, events); err != nil {
+		return fmt.Errorf("failed to record todo history: %w", err)
+	}
+	return nil
+}
+
+// publishEvent publishes an event for a todo to its user, or to every member of its list,
+// logging failures instead of failing the change that caused it
+func (s *todoService) publishEvent(ctx context.Context, eventType models.TodoEventType, todo *models.Todo) {
+	event := models.NewTodoEvent(eventType, todo)
+
+	if todo.ListID != nil {
+		memberIDs, err := s.listService.GetMemberIDs(ctx, *todo.ListID)
+		if err != nil {
+			log.Printf("Failed to get recipients of %s event for todo %s: %v", eventType, todo.ID, err)
+		} else {
+			event.RecipientIDs = memberIDs
+		}
+	}
+
+	if err := s.publisher.Publish(ctx, event); err != nil {
+		log.Printf("Failed to publish %s event for todo %s: %v", eventType, todo.ID, err)
+	}
+}
+
+// newTodoStatsWindow computes the day and week boundaries of now in loc for weeks beginning on weekStart
+func newTodoStatsWindow(now time.Time, loc *time.Location, weekStart time.Weekday) models.TodoStatsWindow {
+	local := now.In(loc)
+	dayStart := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	daysIntoWeek := (int(dayStart.Weekday()) - int(weekStart) + 7) % 7
+	weekEnd := dayStart.AddDate(0, 0, 7-daysIntoWeek)
+
+	return models.TodoStatsWindow{
+		Now:      now,
+		DayStart: dayStart,
+		DayEnd:   dayStart.AddDate(0, 0, 1),
+		WeekEnd:  weekEnd,
+	}
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-backend-todo/internal/models"
+	token_repository "go-backend-todo/internal/repository/token"
+	user_repository "go-backend-todo/internal/repository/user"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrTokenNotFound is returned when a personal access token does not exist or belongs to another user
+	ErrTokenNotFound = errors.New("personal access token not found")
+	// ErrInvalidCredentials is returned when a token or a username and password do not authenticate a verified user
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// personalAccessTokenBytes is the number of random bytes of a personal access token
+const personalAccessTokenBytes = 32
+
+// TokenService interface defines business logic for personal access tokens and the clients that authenticate with them
+type TokenService interface {
+	CreateToken(ctx context.Context, userID uuid.UUID, req *models.CreatePersonalAccessTokenRequest) (*models.PersonalAccessToken, error)
+	GetTokens(ctx context.Context, userID uuid.UUID) ([]*models.PersonalAccessToken, error)
+	RevokeToken(ctx context.Context, id, userID uuid.UUID) error
+	// Authenticate returns the user of a personal access token
+	Authenticate(ctx context.Context, token string) (*models.UserProfile, error)
+	// AuthenticateBasic returns the user identified by a username or email and either a personal access token or their password
+	AuthenticateBasic(ctx context.Context, login, secret string, allowPassword bool) (*models.UserProfile, error)
+	// PurgeExpiredTokens deletes the personal access tokens that expired
+	PurgeExpiredTokens(ctx context.Context) (int64, error)
+}
+
+// tokenService implementation of TokenService interface
+type tokenService struct {
+	tokenRepo    token_repository.TokenRepository
+	userRepo     user_repository.UserRepository
+	auditService AuditService
+}
+
+// NewTokenService creates a new instance of token service
+func NewTokenService(tokenRepo token_repository.TokenRepository, userRepo user_repository.UserRepository, auditService AuditService) TokenService {
+	return &tokenService{
+		tokenRepo:    tokenRepo,
+		userRepo:     userRepo,
+		auditService: auditService,
+	}
+}
+
+// CreateToken creates a personal access token. The token itself is only returned here, only its hash is stored.
+func (s *tokenService) CreateToken(ctx context.Context, userID uuid.UUID, req *models.CreatePersonalAccessTokenRequest) (*models.PersonalAccessToken, error) {
+	secret, err := generateRandomToken(personalAccessTokenBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	token := &models.PersonalAccessToken{
+		UserID: userID,
+		Name:   req.Name,
+		Token:  models.PersonalAccessTokenPrefix + secret,
+	}
+	if req.ExpiresInDays != nil {
+		expiresAt := time.Now().AddDate(0, 0, *req.ExpiresInDays)
+		token.ExpiresAt = &expiresAt
+	}
+
+	if err := s.tokenRepo.Create(ctx, token, hashToken(token.Token)); err != nil {
+		return nil, fmt.Errorf("failed to create token: %w", err)
+	}
+
+	return token, nil
+}
+
+// GetTokens retrieves the personal access tokens of a user
+func (s *tokenService) GetTokens(ctx context.Context, userID uuid.UUID) ([]*models.PersonalAccessToken, error) {
+	tokens, err := s.tokenRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// RevokeToken deletes a personal access token of a user
+func (s *tokenService) RevokeToken(ctx context.Context, id, userID uuid.UUID) error {
+	deleted, err := s.tokenRepo.Delete(ctx, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	if !deleted {
+		return ErrTokenNotFound
+	}
+	return nil
+}
+
+// Authenticate looks up the user of an unexpired personal access token, who must have a verified email
+func (s *tokenService) Authenticate(ctx context.Context, token string) (*models.UserProfile, error) {
+	if !strings.HasPrefix(token, models.PersonalAccessTokenPrefix) {
+		return nil, ErrInvalidCredentials
+	}
+
+	pat, err := s.tokenRepo.Use(ctx, hashToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check token: %w", err)
+	}
+	if pat == nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	user, err := s.userRepo.GetByID(ctx, pat.UserID)
+	if err != nil || !canAuthenticate(user) {
+		return nil, ErrInvalidCredentials
+	}
+
+	return user, nil
+}
+
+// AuthenticateBasic checks HTTP basic credentials. The secret may be a personal access token of the user,
+// or their password when allowPassword is set. Failed password attempts are recorded in the audit log.
+func (s *tokenService) AuthenticateBasic(ctx context.Context, login, secret string, allowPassword bool) (*models.UserProfile, error) {
+	var user *models.UserProfile
+	var err error
+	if strings.Contains(login, "@") {
+		user, err = s.userRepo.GetByEmail(ctx, login)
+	} else {
+		user, err = s.userRepo.GetByUsername(ctx, login)
+	}
+	if err != nil || user == nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	if strings.HasPrefix(secret, models.PersonalAccessTokenPrefix) {
+		tokenUser, err := s.Authenticate(ctx, secret)
+		if err != nil {
+			return nil, err
+		}
+		if tokenUser.UserID != user.UserID {
+			return nil, ErrInvalidCredentials
+		}
+		return tokenUser, nil
+	}
+
+	if !allowPassword {
+		return nil, ErrInvalidCredentials
+	}
+
+	if !s.userRepo.VerifyPassword(secret, user.PasswordHash) {
+		s.auditService.Record(ctx, models.AuditLoginFailed, &user.UserID, user.Email, "invalid password for basic authentication")
+		return nil, ErrInvalidCredentials
+	}
+	if !canAuthenticate(user) {
+		return nil, ErrInvalidCredentials
+	}
+
+	return user, nil
+}
+
+// canAuthenticate reports whether a user may use tokens or basic authentication: the email must be confirmed,
+// and accounts awaiting deletion can only be recovered by logging in interactively
+func canAuthenticate(user *models.UserProfile) bool {
+	return user.Status == models.EmailValidationStatusEnum("confirmed") && user.DeletionScheduledAt == nil
+}
+
+// PurgeExpiredTokens deletes expired personal access tokens, which Authenticate already refuses
+func (s *tokenService) PurgeExpiredTokens(ctx context.Context) (int64, error) {
+	purged, err := s.tokenRepo.DeleteExpired(ctx, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired tokens: %w", err)
+	}
+	return purged, nil
+}
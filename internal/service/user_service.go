@@ -2,30 +2,66 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"go-backend-todo/internal/config"
+	"go-backend-todo/internal/db"
 	"go-backend-todo/internal/models"
+	token_repository "go-backend-todo/internal/repository/token"
 	user_repository "go-backend-todo/internal/repository/user"
 	"go-backend-todo/internal/utils"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// accountPurgeBatchSize caps the number of accounts deleted per purge run
+const accountPurgeBatchSize = 50
+
+// ErrIncorrectPassword is returned when an action confirmed by password gets the wrong one
+var ErrIncorrectPassword = errors.New("password is incorrect")
+
 type UserService interface {
 	GetUserByID(ctx context.Context, userID uuid.UUID) (*models.UserProfile, error)
 	GetAllUsers(ctx context.Context, limit, offset int) ([]*models.UserProfile, int64, error)
 	UpdateUserProfile(ctx context.Context, userID uuid.UUID, req models.UpdateProfileRequest) (*models.UserProfile, error)
+	// DeleteUser deletes an account right away, with everything it owns
 	DeleteUser(ctx context.Context, userID uuid.UUID) error
+	// ScheduleDeletion signs the user out everywhere and deletes the account after a grace period, unless they log in again
+	ScheduleDeletion(ctx context.Context, userID uuid.UUID, req *models.DeleteAccountRequest) (*models.AccountDeletion, error)
+	PurgeScheduledDeletions(ctx context.Context) (int, error)
 	GetUserStats(ctx context.Context) (*models.UserStatsResponse, error)
 	ChangePassword(ctx context.Context, userID uuid.UUID, req *models.ChangePasswordRequest) error
+
+	// GetUserByLogin looks up a user by email address, or by username when login is not an email address
+	GetUserByLogin(ctx context.Context, login string) (*models.UserProfile, error)
+	// SetPassword replaces the password of a user without the current one and revokes their sessions
+	SetPassword(ctx context.Context, userID uuid.UUID, newPassword string) error
+	// ConfirmEmail confirms the email address of a user without a verification token, reporting whether it was unconfirmed
+	ConfirmEmail(ctx context.Context, userID uuid.UUID) (bool, error)
+	// RevokeSessions invalidates every access and refresh token issued to a user and deletes their personal access tokens
+	RevokeSessions(ctx context.Context, userID uuid.UUID) error
 }
 
 type userService struct {
-	userRepo user_repository.UserRepository
+	userRepo     user_repository.UserRepository
+	tokenRepo    token_repository.TokenRepository
+	auditService AuditService
+	emailService EmailService
+	txManager    db.TxManager
+	config       *config.Config
 }
 
-func NewUserService(userRepo user_repository.UserRepository) UserService {
+func NewUserService(userRepo user_repository.UserRepository, tokenRepo token_repository.TokenRepository, auditService AuditService, emailService EmailService, txManager db.TxManager, cfg *config.Config) UserService {
 	return &userService{
-		userRepo: userRepo,
+		userRepo:     userRepo,
+		tokenRepo:    tokenRepo,
+		auditService: auditService,
+		emailService: emailService,
+		txManager:    txManager,
+		config:       cfg,
 	}
 }
 
@@ -44,10 +80,83 @@ func (s *userService) UpdateUserProfile(ctx context.Context, userID uuid.UUID, r
 }
 
 func (s *userService) DeleteUser(ctx context.Context, userID uuid.UUID) error {
-	// TODO: Implement delete user
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.Delete(ctx, userID); err != nil {
+		return err
+	}
+
+	s.auditService.Record(ctx, models.AuditAccountDeleted, nil, user.Email, "")
 	return nil
 }
 
+// ScheduleDeletion schedules the deletion of the account after the password is confirmed.
+// Bumping the token version revokes every session, and logging in again cancels the deletion.
+func (s *userService) ScheduleDeletion(ctx context.Context, userID uuid.UUID, req *models.DeleteAccountRequest) (*models.AccountDeletion, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.userRepo.VerifyPassword(req.Password, user.PasswordHash) {
+		s.auditService.Record(ctx, models.AuditDeletionConfirmFailed, &userID, "", "incorrect password to confirm account deletion")
+		return nil, ErrIncorrectPassword
+	}
+
+	deletionAt := time.Now().Add(time.Duration(s.config.Account.DeletionGraceDays) * 24 * time.Hour)
+	// A deletion is never scheduled while the sessions it is meant to end stay valid
+	err = s.txManager.WithinTx(ctx, func(txCtx context.Context) error {
+		if err := s.userRepo.ScheduleDeletion(txCtx, userID, deletionAt); err != nil {
+			return fmt.Errorf("failed to schedule account deletion: %w", err)
+		}
+
+		if err := s.userRepo.IncrementTokenVersion(txCtx, userID); err != nil {
+			return fmt.Errorf("failed to revoke sessions: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.Record(ctx, models.AuditDeletionScheduled, &userID, "", deletionAt.UTC().Format(time.RFC3339))
+
+	emailCtx, cancel := context.WithTimeout(ctx, time.Duration(s.config.Timeouts.EmailTimeout.EmailSendTimeout)*time.Second)
+	defer cancel()
+	if err := s.emailService.SendAccountDeletionEmail(emailCtx, user.Email, user.Username, deletionAt); err != nil {
+		log.Printf("Failed to send account deletion email to user %s: %v", userID, err)
+	}
+
+	return &models.AccountDeletion{DeletionScheduledAt: deletionAt}, nil
+}
+
+// PurgeScheduledDeletions deletes the accounts whose grace period is over
+func (s *userService) PurgeScheduledDeletions(ctx context.Context) (int, error) {
+	now := time.Now()
+	users, err := s.userRepo.GetDueDeletions(ctx, now, accountPurgeBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get due account deletions: %w", err)
+	}
+
+	purged := 0
+	for _, user := range users {
+		deleted, err := s.userRepo.DeleteIfDue(ctx, user.UserID, now)
+		if err != nil {
+			log.Printf("Failed to delete account %s: %v", user.UserID, err)
+			continue
+		}
+		if deleted {
+			s.auditService.Record(ctx, models.AuditAccountDeleted, nil, user.Email, "scheduled deletion")
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
 func (s *userService) GetUserStats(ctx context.Context) (*models.UserStatsResponse, error) {
 	// TODO: Implement user statistics
 	stats := &models.UserStatsResponse{
@@ -80,6 +189,7 @@ func (s *userService) ChangePassword(ctx context.Context, userID uuid.UUID, req
     }
 
     if !s.userRepo.VerifyPassword(req.CurrentPassword, user.PasswordHash) {
+        s.auditService.Record(ctx, models.AuditPasswordChangeFailed, &userID, "", "current password is incorrect")
         return utils.ErrInvalidCredentials("Current password is incorrect")
     }
 
@@ -94,6 +204,70 @@ func (s *userService) ChangePassword(ctx context.Context, userID uuid.UUID, req
         return utils.ErrInternalServerError("Failed to hash password")
     }
 
-    return s.userRepo.UpdatePassword(ctx, userID, hashedPassword)
+    if err := s.userRepo.UpdatePassword(ctx, userID, hashedPassword); err != nil {
+        return err
+    }
+
+    s.auditService.Record(ctx, models.AuditPasswordChanged, &userID, "", "")
+    return nil
 }
 
+
+func (s *userService) GetUserByLogin(ctx context.Context, login string) (*models.UserProfile, error) {
+	if strings.Contains(login, "@") {
+		return s.userRepo.GetByEmail(ctx, login)
+	}
+	return s.userRepo.GetByUsername(ctx, login)
+}
+
+// SetPassword sets a new password chosen by an administrator, with the same strength rules as a password change
+func (s *userService) SetPassword(ctx context.Context, userID uuid.UUID, newPassword string) error {
+	if err := s.userRepo.ValidatePasswordStrength(newPassword); err != nil {
+		return err
+	}
+
+	hashedPassword, err := s.userRepo.HashPassword(newPassword)
+	if err != nil {
+		return utils.ErrInternalServerError("Failed to hash password")
+	}
+
+	// UpdatePassword also bumps the token version and revokes personal access tokens, which signs the user out everywhere
+	if err := s.userRepo.UpdatePassword(ctx, userID, hashedPassword); err != nil {
+		return err
+	}
+
+	s.auditService.Record(ctx, models.AuditPasswordReset, &userID, "", "reset by an administrator")
+	return nil
+}
+
+func (s *userService) ConfirmEmail(ctx context.Context, userID uuid.UUID) (bool, error) {
+	confirmed, err := s.userRepo.ConfirmEmail(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to confirm email: %w", err)
+	}
+
+	if confirmed {
+		s.auditService.Record(ctx, models.AuditEmailVerified, &userID, "", "confirmed by an administrator")
+	}
+	return confirmed, nil
+}
+
+func (s *userService) RevokeSessions(ctx context.Context, userID uuid.UUID) error {
+	// Clients signed in with a personal access token, such as CalDAV clients, are sessions too
+	err := s.txManager.WithinTx(ctx, func(txCtx context.Context) error {
+		if err := s.userRepo.IncrementTokenVersion(txCtx, userID); err != nil {
+			return err
+		}
+
+		if _, err := s.tokenRepo.DeleteByUserID(txCtx, userID); err != nil {
+			return fmt.Errorf("failed to revoke personal access tokens: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.auditService.Record(ctx, models.AuditSessionsRevoked, &userID, "", "")
+	return nil
+}
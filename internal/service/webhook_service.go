@@ -0,0 +1,418 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"go-backend-todo/internal/config"
+	"go-backend-todo/internal/models"
+	webhook_repository "go-backend-todo/internal/repository/webhook"
+	"go-backend-todo/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// ErrWebhookNotFound is returned when a webhook does not exist or belongs to another user
+var ErrWebhookNotFound = errors.New("webhook not found or access denied")
+
+// webhookLastErrorLimit caps the response excerpt stored with a failed delivery
+const webhookLastErrorLimit = 512
+
+// WebhookService interface defines business logic for webhooks and their deliveries.
+// It is also an EventPublisher that queues deliveries for todo events.
+type WebhookService interface {
+	EventPublisher
+	CreateWebhook(ctx context.Context, userID uuid.UUID, req *models.CreateWebhookRequest) (*models.Webhook, error)
+	GetWebhooks(ctx context.Context, userID uuid.UUID) ([]*models.Webhook, error)
+	GetWebhook(ctx context.Context, id, userID uuid.UUID) (*models.Webhook, error)
+	UpdateWebhook(ctx context.Context, id, userID uuid.UUID, req *models.UpdateWebhookRequest) (*models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id, userID uuid.UUID) error
+	GetDeliveries(ctx context.Context, id, userID uuid.UUID, limit, offset int) ([]*models.WebhookDelivery, int64, error)
+	SendTestEvent(ctx context.Context, id, userID uuid.UUID) (*models.WebhookDelivery, error)
+	ProcessDueDeliveries(ctx context.Context) (int, error)
+}
+
+// webhookService implementation of WebhookService interface
+type webhookService struct {
+	webhookRepo webhook_repository.WebhookRepository
+	client      *http.Client
+	config      *config.Config
+}
+
+// webhookPayload is the JSON body posted to webhook endpoints
+type webhookPayload struct {
+	ID         uuid.UUID            `json:"id"`
+	Type       models.TodoEventType `json:"type"`
+	OccurredAt time.Time            `json:"occurred_at"`
+	Data       interface{}          `json:"data"`
+}
+
+// NewWebhookService creates a new instance of webhook service
+func NewWebhookService(webhookRepo webhook_repository.WebhookRepository, cfg *config.Config) WebhookService {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if !cfg.Webhook.AllowPrivateTargets {
+		// Checked on the resolved address so DNS cannot be used to reach internal services
+		dialer.Control = rejectPrivateAddress
+	}
+
+	return &webhookService{
+		webhookRepo: webhookRepo,
+		client: &http.Client{
+			Timeout:   time.Duration(cfg.Webhook.RequestTimeout) * time.Second,
+			Transport: &http.Transport{DialContext: dialer.DialContext},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		config: cfg,
+	}
+}
+
+// CreateWebhook registers a webhook with a freshly generated signing secret
+func (s *webhookService) CreateWebhook(ctx context.Context, userID uuid.UUID, req *models.CreateWebhookRequest) (*models.Webhook, error) {
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	now := time.Now()
+	webhook := &models.Webhook{
+		ID:          uuid.New(),
+		UserID:      userID,
+		URL:         req.URL,
+		Secret:      secret,
+		Events:      uniqueEventTypes(req.Events),
+		Description: req.Description,
+		Active:      true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.webhookRepo.Create(ctx, webhook); err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+// GetWebhooks retrieves the webhooks of a user without their secrets
+func (s *webhookService) GetWebhooks(ctx context.Context, userID uuid.UUID) ([]*models.Webhook, error) {
+	webhooks, err := s.webhookRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	for _, webhook := range webhooks {
+		webhook.Secret = ""
+	}
+	return webhooks, nil
+}
+
+// GetWebhook retrieves a webhook of a user without its secret
+func (s *webhookService) GetWebhook(ctx context.Context, id, userID uuid.UUID) (*models.Webhook, error) {
+	webhook, err := s.getOwnedWebhook(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+// UpdateWebhook applies the provided fields to a webhook of a user
+func (s *webhookService) UpdateWebhook(ctx context.Context, id, userID uuid.UUID, req *models.UpdateWebhookRequest) (*models.Webhook, error) {
+	webhook, err := s.getOwnedWebhook(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		if err := validateWebhookURL(*req.URL); err != nil {
+			return nil, err
+		}
+		webhook.URL = *req.URL
+	}
+	if req.Events != nil {
+		webhook.Events = uniqueEventTypes(req.Events)
+	}
+	if req.Description != nil {
+		webhook.Description = *req.Description
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+	webhook.UpdatedAt = time.Now()
+
+	if err := s.webhookRepo.Update(ctx, webhook); err != nil {
+		return nil, fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+// DeleteWebhook deletes a webhook of a user and its delivery log
+func (s *webhookService) DeleteWebhook(ctx context.Context, id, userID uuid.UUID) error {
+	if _, err := s.getOwnedWebhook(ctx, id, userID); err != nil {
+		return err
+	}
+
+	if err := s.webhookRepo.Delete(ctx, id, userID); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	return nil
+}
+
+// GetDeliveries retrieves the delivery log of a webhook of a user
+func (s *webhookService) GetDeliveries(ctx context.Context, id, userID uuid.UUID, limit, offset int) ([]*models.WebhookDelivery, int64, error) {
+	if _, err := s.getOwnedWebhook(ctx, id, userID); err != nil {
+		return nil, 0, err
+	}
+
+	deliveries, err := s.webhookRepo.GetDeliveries(ctx, id, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+
+	total, err := s.webhookRepo.CountDeliveries(ctx, id)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	return deliveries, total, nil
+}
+
+// SendTestEvent queues a webhook.test delivery, regardless of the webhook's event subscriptions
+func (s *webhookService) SendTestEvent(ctx context.Context, id, userID uuid.UUID) (*models.WebhookDelivery, error) {
+	webhook, err := s.getOwnedWebhook(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := webhookPayload{
+		ID:         uuid.New(),
+		Type:       models.WebhookTestEvent,
+		OccurredAt: time.Now(),
+		Data:       map[string]interface{}{"webhook_id": webhook.ID, "message": "This is a test event"},
+	}
+
+	delivery, err := s.enqueue(ctx, webhook.ID, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to queue test event: %w", err)
+	}
+	return delivery, nil
+}
+
+// Publish queues a delivery of the event for every active webhook of its recipients subscribed to it
+func (s *webhookService) Publish(ctx context.Context, event models.TodoEvent) error {
+	webhooks, err := s.webhookRepo.GetActiveForEvent(ctx, event.Recipients(), event.Type)
+	if err != nil {
+		return fmt.Errorf("failed to get webhooks for event: %w", err)
+	}
+
+	payload := webhookPayload{
+		ID:         event.ID,
+		Type:       event.Type,
+		OccurredAt: event.OccurredAt,
+		Data:       event.Todo,
+	}
+
+	for _, webhook := range webhooks {
+		if _, err := s.enqueue(ctx, webhook.ID, payload); err != nil {
+			return fmt.Errorf("failed to queue webhook delivery: %w", err)
+		}
+	}
+	return nil
+}
+
+// ProcessDueDeliveries claims due deliveries and posts them, rescheduling failures with exponential backoff
+func (s *webhookService) ProcessDueDeliveries(ctx context.Context) (int, error) {
+	// The lease outlives the request so a slow endpoint is never delivered to twice concurrently
+	lease := time.Duration(s.config.Webhook.RequestTimeout)*time.Second + time.Minute
+	deliveries, err := s.webhookRepo.ClaimDueDeliveries(ctx, time.Now(), lease, s.config.Webhook.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim due webhook deliveries: %w", err)
+	}
+
+	delivered := 0
+	for _, delivery := range deliveries {
+		status, err := s.deliver(ctx, delivery)
+		if err == nil {
+			if err := s.webhookRepo.MarkSucceeded(ctx, delivery.ID, status); err != nil {
+				log.Printf("Failed to mark webhook delivery %s as succeeded: %v", delivery.ID, err)
+			}
+			delivered++
+			continue
+		}
+
+		var responseStatus *int
+		if status != 0 {
+			responseStatus = &status
+		}
+
+		var nextAttemptAt *time.Time
+		if delivery.Attempts < s.config.Webhook.MaxAttempts {
+			next := time.Now().Add(s.backoff(delivery.Attempts))
+			nextAttemptAt = &next
+		}
+
+		if err := s.webhookRepo.MarkFailed(ctx, delivery.ID, responseStatus, err.Error(), nextAttemptAt); err != nil {
+			log.Printf("Failed to mark webhook delivery %s as failed: %v", delivery.ID, err)
+		}
+	}
+
+	return delivered, nil
+}
+
+// deliver posts a signed delivery and returns the response status, failing on anything but a 2xx response
+func (s *webhookService) deliver(ctx context.Context, delivery *models.DueWebhookDelivery) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", s.config.App.Name+"-Webhooks")
+	req.Header.Set("X-Webhook-Event", string(delivery.EventType))
+	req.Header.Set("X-Webhook-Delivery", delivery.ID.String())
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+signWebhookPayload(delivery.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, webhookLastErrorLimit))
+		return resp.StatusCode, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, excerpt)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// backoff returns the delay before the next attempt after the given number of attempts
+func (s *webhookService) backoff(attempts int) time.Duration {
+	delay := time.Duration(s.config.Webhook.BackoffBase) * time.Second
+	maxDelay := time.Duration(s.config.Webhook.BackoffMax) * time.Second
+	for i := 1; i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
+
+// enqueue stores a pending delivery of payload for a webhook
+func (s *webhookService) enqueue(ctx context.Context, webhookID uuid.UUID, payload webhookPayload) (*models.WebhookDelivery, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	delivery := &models.WebhookDelivery{
+		ID:            uuid.New(),
+		WebhookID:     webhookID,
+		EventID:       payload.ID,
+		EventType:     payload.Type,
+		Payload:       body,
+		Status:        models.DeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+
+	if err := s.webhookRepo.CreateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// getOwnedWebhook retrieves a webhook, treating webhooks of other users as not found
+func (s *webhookService) getOwnedWebhook(ctx context.Context, id, userID uuid.UUID) (*models.Webhook, error) {
+	webhook, err := s.webhookRepo.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	if webhook == nil {
+		return nil, ErrWebhookNotFound
+	}
+	return webhook, nil
+}
+
+// signWebhookPayload computes the hex HMAC-SHA256 of "timestamp.body" with the webhook secret
+func signWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// generateWebhookSecret generates a random signing secret
+func generateWebhookSecret() (string, error) {
+	token, err := generateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	return "whsec_" + token, nil
+}
+
+// validateWebhookURL only accepts absolute http and https URLs
+func validateWebhookURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return utils.ErrInvalidInput("Webhook URL must be an absolute http or https URL")
+	}
+	return nil
+}
+
+// uniqueEventTypes removes duplicate event types while keeping their order
+func uniqueEventTypes(events []models.TodoEventType) []models.TodoEventType {
+	seen := make(map[models.TodoEventType]bool, len(events))
+	result := make([]models.TodoEventType, 0, len(events))
+	for _, event := range events {
+		if seen[event] {
+			continue
+		}
+		seen[event] = true
+		result = append(result, event)
+	}
+	return result
+}
+
+// rejectPrivateAddress refuses connections to loopback, private, link-local and unspecified addresses
+func rejectPrivateAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsUnspecified() || ip.IsMulticast() {
+		return fmt.Errorf("webhook target %s is not a public address", host)
+	}
+	return nil
+}
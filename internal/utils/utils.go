@@ -1,16 +1,23 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 	"math/rand"
 )
 
+// ErrBadInput is wrapped by the errors of ErrInvalidInput, so handlers can tell them apart from failures
+var ErrBadInput = errors.New("invalid input")
+
 func ErrNotImplemented(feature string) error {
 	return fmt.Errorf("feature not implemented: %s", feature)
 }
 
+// ErrBadCredentials is wrapped by the errors of ErrInvalidCredentials, so callers can tell them apart from failures
+var ErrBadCredentials = errors.New("invalid credentials")
+
 func ErrInvalidCredentials(message string) error {
-	return fmt.Errorf("invalid credentials: %s", message)
+	return fmt.Errorf("%w: %s", ErrBadCredentials, message)
 }
 
 func ErrInternalServerError(message string) error {
@@ -38,7 +45,7 @@ func ErrUnauthorized(message string) error {
 }
 
 func ErrInvalidInput(message string) error {
-	return fmt.Errorf("invalid input: %s", message)
+	return fmt.Errorf("%w: %s", ErrBadInput, message)
 }
 
 func ErrTimeout(message string) error {
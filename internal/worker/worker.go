@@ -0,0 +1,155 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+const (
+	// restartBackoff is how long a failed worker waits before its first restart
+	restartBackoff = time.Second
+	// maxRestartBackoff caps the wait between restarts of a worker that keeps failing
+	maxRestartBackoff = time.Minute
+	// defaultInterval replaces a periodic worker interval that is not positive, which time.NewTicker rejects
+	defaultInterval = time.Minute
+)
+
+// Worker is a long-running background job bound to a context
+type Worker interface {
+	Name() string
+	Run(ctx context.Context) error
+}
+
+// periodicWorker runs a task on a fixed interval
+type periodicWorker struct {
+	name     string
+	interval time.Duration
+	task     func(ctx context.Context) error
+}
+
+// NewPeriodic creates a worker that runs task immediately and then every interval until ctx is cancelled.
+// An interval that is not positive, e.g. a misconfigured one, falls back to defaultInterval.
+func NewPeriodic(name string, interval time.Duration, task func(ctx context.Context) error) Worker {
+	if interval <= 0 {
+		log.Printf("Worker %s has an invalid interval %s, running it every %s", name, interval, defaultInterval)
+		interval = defaultInterval
+	}
+
+	return &periodicWorker{
+		name:     name,
+		interval: interval,
+		task:     task,
+	}
+}
+
+// Name returns the worker name used in logs
+func (w *periodicWorker) Name() string {
+	return w.name
+}
+
+// Run executes the task until ctx is cancelled. Task errors are logged and do not stop the worker.
+func (w *periodicWorker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.task(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Worker %s failed: %v", w.name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Supervisor runs workers until it is stopped, restarting those that fail
+type Supervisor struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewSupervisor creates a supervisor whose workers run until ctx is cancelled or Stop is called
+func NewSupervisor(ctx context.Context) *Supervisor {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Supervisor{ctx: ctx, cancel: cancel}
+}
+
+// Start runs each worker in its own goroutine.
+// A worker that returns an error or panics is restarted with exponential backoff; one that returns nil is done.
+func (s *Supervisor) Start(workers ...Worker) {
+	for _, w := range workers {
+		s.wg.Add(1)
+		go func(w Worker) {
+			defer s.wg.Done()
+			s.supervise(w)
+		}(w)
+	}
+}
+
+// Stop cancels the workers and waits for them to return, giving up after timeout
+func (s *Supervisor) Stop(timeout time.Duration) error {
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("workers did not stop within %s", timeout)
+	}
+}
+
+// supervise runs a worker until it finishes or the supervisor stops
+func (s *Supervisor) supervise(w Worker) {
+	backoff := restartBackoff
+	for {
+		log.Printf("Worker %s started", w.Name())
+		started := time.Now()
+		err := run(s.ctx, w)
+		if s.ctx.Err() != nil {
+			log.Printf("Worker %s stopped", w.Name())
+			return
+		}
+		if err == nil {
+			log.Printf("Worker %s finished", w.Name())
+			return
+		}
+
+		// A worker that ran for a while before failing starts over with a short backoff
+		if time.Since(started) > maxRestartBackoff {
+			backoff = restartBackoff
+		}
+		log.Printf("Worker %s failed, restarting in %s: %v", w.Name(), backoff, err)
+
+		select {
+		case <-s.ctx.Done():
+			log.Printf("Worker %s stopped", w.Name())
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxRestartBackoff)
+	}
+}
+
+// run runs a worker, turning a panic into an error
+func run(ctx context.Context, w Worker) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return w.Run(ctx)
+}